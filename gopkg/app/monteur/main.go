@@ -30,6 +30,13 @@ import (
 
 func main() {
 	action := ""
+	args := []string{}
+	opts := &monteur.Options{}
+	quiet := false
+	verbose := false
+	debug := false
+	runs := uint(0)
+	threshold := uint(0)
 
 	// setup CLI manager
 	m := oshelper.NewArgParser()
@@ -49,10 +56,16 @@ Monteur - Getting the job done locally and remotely at scale!
 		`$ monteur test`,
 		`$ monteur prepare`,
 		`$ monteur build`,
+		`$ monteur build --log-format json --events build-events.jsonl`,
 		`$ monteur package`,
 		`$ monteur release`,
 		`$ monteur compose`,
 		`$ monteur publish`,
+		`$ monteur validate`,
+		`$ monteur inspect build`,
+		`$ monteur stats package`,
+		`$ monteur secrets edit .configs/monteur/secrets/main.toml`,
+		`$ monteur cache list`,
 	}
 
 	_ = m.Add(&oshelper.Argument{
@@ -175,9 +188,211 @@ Monteur - Getting the job done locally and remotely at scale!
 		},
 	})
 
+	_ = m.Add(&oshelper.Argument{
+		Name:  "Validate",
+		Label: []string{"validate"},
+		Value: &action,
+		Help:  "check all job configurations without running them",
+		HelpExamples: []string{
+			"$ monteur validate",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "Inspect",
+		Label:      []string{"inspect"},
+		ValueLabel: "STAGE [TASK]",
+		Value:      &action,
+		Trailing:   &args,
+		Help: "print a stage's variables with their origins and " +
+			"its filesystem pathing",
+		HelpExamples: []string{
+			"$ monteur inspect build",
+			"$ monteur inspect package linux-amd64",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "Stats",
+		Label:      []string{"stats"},
+		ValueLabel: "STAGE",
+		Value:      &action,
+		Trailing:   &args,
+		Help: "print a stage's timing trends, critical path, slowest " +
+			"steps, and regressions from its run history",
+		HelpExamples: []string{
+			"$ monteur stats package",
+			"$ monteur stats package --runs 20 --threshold 10",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "Runs",
+		Label:      []string{"--runs"},
+		ValueLabel: "N",
+		Value:      &runs,
+		Help: "set the number of previous runs used as the stats " +
+			"baseline (default: 10)",
+		HelpExamples: []string{
+			"$ monteur stats package --runs 20",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "Threshold",
+		Label:      []string{"--threshold"},
+		ValueLabel: "PERCENT",
+		Value:      &threshold,
+		Help: "set the slowdown percentage over the baseline median " +
+			"flagged as regression (default: 20)",
+		HelpExamples: []string{
+			"$ monteur stats package --threshold 10",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "Secrets",
+		Label:      []string{"secrets"},
+		ValueLabel: "ACTION FILE",
+		Value:      &action,
+		Trailing:   &args,
+		Help: "encrypt, decrypt, or edit a secrets file using the key " +
+			"from MONTEUR_SECRETS_KEY, MONTEUR_SECRETS_KEY_FILE, " +
+			"or prompt",
+		HelpExamples: []string{
+			"$ monteur secrets encrypt path/to/secrets.toml",
+			"$ monteur secrets decrypt path/to/secrets.toml",
+			"$ monteur secrets edit path/to/secrets.toml",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "Cache",
+		Label:      []string{"cache"},
+		ValueLabel: "ACTION",
+		Value:      &action,
+		Trailing:   &args,
+		Help: "list, prune (unused for 30 days), or clear the user " +
+			"level download cache shared across repositories",
+		HelpExamples: []string{
+			"$ monteur cache list",
+			"$ monteur cache prune",
+			"$ monteur cache clear",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "LogFormat",
+		Label:      []string{"--log-format"},
+		ValueLabel: "text|json",
+		Value:      &opts.LogFormat,
+		Help: "set the logs and terminal printout format where 'json' " +
+			"writes JSON Lines records",
+		HelpExamples: []string{
+			"$ monteur build --log-format json",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:       "Events",
+		Label:      []string{"--events"},
+		ValueLabel: "PATH|FD",
+		Value:      &opts.Events,
+		Help: "stream the tasks' lifecycle events in JSON Lines " +
+			"format into a file or an opened file descriptor",
+		HelpExamples: []string{
+			"$ monteur build --events build-events.jsonl",
+			"$ monteur build --events 3 3>build-events.jsonl",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:  "Wait",
+		Label: []string{"--wait"},
+		Value: &opts.Wait,
+		Help: "wait for another run holding the workspace lock to " +
+			"finish instead of failing",
+		HelpExamples: []string{
+			"$ monteur build --wait",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:  "Force",
+		Label: []string{"--force"},
+		Value: &opts.Force,
+		Help: "run all tasks even when their declared inputs and " +
+			"outputs are up to date",
+		HelpExamples: []string{
+			"$ monteur build --force",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:  "UpdateLock",
+		Label: []string{"--update-lock"},
+		Value: &opts.UpdateLock,
+		Help: "accept https-download setup sources drifted from " +
+			"setup.lock by re-pinning them instead of failing",
+		HelpExamples: []string{
+			"$ monteur setup --update-lock",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:  "Offline",
+		Label: []string{"--offline"},
+		Value: &opts.Offline,
+		Help: "source setup downloads only from the download cache " +
+			"and fail when any of them is missing",
+		HelpExamples: []string{
+			"$ monteur setup --offline",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:  "Quiet",
+		Label: []string{"--quiet", "-q"},
+		Value: &quiet,
+		Help:  "only print errors and outputs to the terminal",
+		HelpExamples: []string{
+			"$ monteur build --quiet",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:  "Verbose",
+		Label: []string{"--verbose", "-v"},
+		Value: &verbose,
+		Help:  "print all job statements to the terminal",
+		HelpExamples: []string{
+			"$ monteur build --verbose",
+		},
+	})
+
+	_ = m.Add(&oshelper.Argument{
+		Name:  "Debug",
+		Label: []string{"--debug"},
+		Value: &debug,
+		Help: "print all job statements, tasks' full step traces, " +
+			"and debug statements to the terminal",
+		HelpExamples: []string{
+			"$ monteur build --debug",
+		},
+	})
+
 	// parse the CLI arguments
 	m.Parse()
 
+	switch {
+	case debug:
+		opts.Verbosity = "debug"
+	case verbose:
+		opts.Verbosity = "verbose"
+	case quiet:
+		opts.Verbosity = "quiet"
+	}
+
 	// execute according to action
 	switch action {
 	case "help", "--help", "-h":
@@ -189,23 +404,37 @@ Monteur - Getting the job done locally and remotely at scale!
 	case "init":
 		os.Exit(monteur.Init())
 	case "setup":
-		os.Exit(monteur.Setup())
+		os.Exit(monteur.Setup(opts))
 	case "clean":
-		os.Exit(monteur.Clean())
+		os.Exit(monteur.Clean(opts))
 	case "test":
-		os.Exit(monteur.Test())
+		os.Exit(monteur.Test(opts))
 	case "prepare":
-		os.Exit(monteur.Prepare())
+		os.Exit(monteur.Prepare(opts))
 	case "build":
-		os.Exit(monteur.Build())
+		os.Exit(monteur.Build(opts))
 	case "package":
-		os.Exit(monteur.Package())
+		os.Exit(monteur.Package(opts))
 	case "release":
-		os.Exit(monteur.Release())
+		os.Exit(monteur.Release(opts))
 	case "compose":
-		os.Exit(monteur.Compose())
+		os.Exit(monteur.Compose(opts))
 	case "publish":
-		os.Exit(monteur.Publish())
+		os.Exit(monteur.Publish(opts))
+	case "inspect":
+		args = append(args, "", "")
+		os.Exit(monteur.Inspect(args[0], args[1]))
+	case "stats":
+		args = append(args, "")
+		os.Exit(monteur.Stats(args[0], runs, threshold))
+	case "secrets":
+		args = append(args, "", "")
+		os.Exit(monteur.Secrets(args[0], args[1]))
+	case "cache":
+		args = append(args, "")
+		os.Exit(monteur.Cache(args[0]))
+	case "validate":
+		os.Exit(monteur.Validate())
 	default:
 		fmt.Fprintf(os.Stderr,
 			"[ ERROR ] unknown action. Use 'help' to start.\n",
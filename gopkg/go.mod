@@ -6,6 +6,9 @@ replace gitlab.com/zoralab/monteur/gopkg => ./
 
 require (
 	github.com/pelletier/go-toml/v2 v2.0.0-beta.4.0.20211201025922-c862c344b302
+	github.com/ulikunitz/xz v0.5.15
 	gitlab.com/zoralab/cerigo v0.0.2
+	golang.org/x/crypto v0.0.0-20220214200702-86341886e292
 	golang.org/x/sys v0.0.0-20211124211545-fe61309f8881
+	golang.org/x/term v0.0.0-20210503060354-a79de5458b56
 )
@@ -40,10 +40,11 @@ func Init() (statusCode int) {
 //
 // The action shall download all the dependencies and setup the locally working
 // Monteur filesystem specified by the setup/jobs configuration files.
-func Setup() (statusCode int) {
+func Setup(opts *Options) (statusCode int) {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_SETUP,
 		ErrorTag: libmonteur.ERROR_SETUP,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -54,10 +55,11 @@ func Setup() (statusCode int) {
 // This action is to clean up the repository from a previous run, allowing a
 // fresh run on the next round. The deepness and coverage area are specified by
 // the clean/jobs configuration files.
-func Clean() int {
+func Clean(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_CLEAN,
 		ErrorTag: libmonteur.ERROR_CLEAN,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -69,10 +71,11 @@ func Clean() int {
 // development or a continuous improvement autonomous run. That way, anyone
 // including the CI infrastructure can run testing for the repository both
 // manually and autonomously at any given time.
-func Test() int {
+func Test(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_TEST,
 		ErrorTag: libmonteur.ERROR_TEST,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -83,10 +86,11 @@ func Test() int {
 // This action is to prepare the repository for the next version's Build,
 // Package and Release API where its job are not suitable to be inside any of
 // them.
-func Prepare() int {
+func Prepare(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_PREPARE,
 		ErrorTag: libmonteur.ERROR_PREPARE,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -97,10 +101,11 @@ func Prepare() int {
 // This action is to build the release version software into many of its
 // variants such as but not limited to operating system, CPU types, packaging
 // types (e.g. plugins).
-func Build() int {
+func Build(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_BUILD,
 		ErrorTag: libmonteur.ERROR_BUILD,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -111,10 +116,11 @@ func Build() int {
 // This action packages the built software into many distributions channel
 // formats like .msi for Microsoft Windows OS, .deb for Debian-based Linux OS,
 // .rpm for RPM-based Linux OS, .dmg for MacOS, .appImage for AppImage.
-func Package() int {
+func Package(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_PACKAGE,
 		ErrorTag: libmonteur.ERROR_PACKAGE,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -125,10 +131,11 @@ func Package() int {
 // This action is to update all necessary documents like changelog, version
 // numbers, build configurations as programmed for the next release. This
 // function should be done before building the next version release.
-func Release() int {
+func Release(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_RELEASE,
 		ErrorTag: libmonteur.ERROR_RELEASE,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -138,10 +145,11 @@ func Release() int {
 //
 // This action is to build the publication artifacts prior to `Publish`. It is
 // for local review and editing without publishing to the main web.
-func Compose() int {
+func Compose(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_COMPOSE,
 		ErrorTag: libmonteur.ERROR_COMPOSE,
+		Options:  opts,
 	}
 
 	return api.Run()
@@ -151,10 +159,86 @@ func Compose() int {
 //
 // this action generates the documentations artifact and publish it to its
 // reading channels such as web, file server for PDF files, and etc.
-func Publish() int {
+func Publish(opts *Options) int {
 	api := &apiCommand{
 		Job:      libmonteur.JOB_PUBLISH,
 		ErrorTag: libmonteur.ERROR_PUBLISH,
+		Options:  opts,
+	}
+
+	return api.Run()
+}
+
+// Validate is the function to statically check all the job configurations.
+//
+// This action strictly decodes every job's settings and task files without
+// running any of them. All issues like unknown keys, bad data types, unknown
+// command types, missing conditions, bad templates, unresolved variables and
+// empty secrets are reported together with their file location.
+func Validate() int {
+	api := &apiValidate{}
+
+	return api.Run()
+}
+
+// Inspect is the function to print the merged variables list of a stage.
+//
+// This action prints every variable available to the given stage together
+// with the layer it originates from (workspace, stage config, task and
+// package). When `task` is given (matching its Metadata.Name or its filename
+// without extension), the task's layers are included. All the filesystem
+// pathing are listed at the end. Secrets are always redacted.
+func Inspect(stage string, task string) int {
+	api := &apiInspect{
+		Stage: stage,
+		Task:  task,
+	}
+
+	return api.Run()
+}
+
+// Stats is the function to print the timing statistics of a stage.
+//
+// This action analyzes the stage's run history recorded in HistoryDir. It
+// prints the per-task trends, the critical path, the slowest steps, and flags
+// all regressions where the latest run is slower than the median of the last
+// `runs` passed runs by more than `threshold` percent. Zero values use the
+// defaults (10 runs and 20 percent).
+func Stats(stage string, runs uint, threshold uint) int {
+	api := &apiStats{
+		Stage:     stage,
+		Runs:      runs,
+		Threshold: threshold,
+	}
+
+	return api.Run()
+}
+
+// Secrets is the function to encrypt, decrypt or edit a secrets file.
+//
+// The `action` is either `encrypt`, `decrypt` or `edit`. Encryption and
+// decryption are done in place. Editing decrypts the file into a private
+// temporary file, opens it with `$EDITOR`, and encrypts the edited contents
+// back. The key is sourced from `MONTEUR_SECRETS_KEY`,
+// `MONTEUR_SECRETS_KEY_FILE`, or an interactive prompt in that order.
+func Secrets(action string, path string) int {
+	api := &apiSecrets{
+		Action: action,
+		Path:   path,
+	}
+
+	return api.Run()
+}
+
+// Cache is the function to manage the user level download cache.
+//
+// The `action` is either `list`, `prune` or `clear`. Pruning removes the
+// downloads unused for 30 days while clearing removes everything. The cache is
+// shared across all repositories and is located at `MONTEUR_DOWNLOADS_DIR` or
+// the `monteur/downloads` directory inside the user cache directory.
+func Cache(action string) int {
+	api := &apiCache{
+		Action: action,
 	}
 
 	return api.Run()
@@ -0,0 +1,108 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// Options are the optional run settings for the job functions (e.g. `Build`).
+//
+// A `nil` Options is the same as its zero value.
+type Options struct {
+	// LogFormat is the format for both log files and terminal printouts.
+	//
+	// It is either `text` (default) or `json` where each statement is a
+	// JSON Lines record with timestamp, stage, task, step, level, and
+	// message fields.
+	LogFormat string
+
+	// Events is the destination of the tasks' lifecycle events stream.
+	//
+	// It is either a filepath or an opened file descriptor number (e.g.
+	// `3`). The events (`queued`, `started`, `step-started`,
+	// `step-finished`, `done`, and `error`) are written in JSON Lines
+	// format. Empty means disabled.
+	Events string
+
+	// Verbosity is the terminal printout level.
+	//
+	// It is either `quiet` (errors and outputs only), `normal` (default;
+	// task-level progress, warnings, errors and outputs), `verbose` (all
+	// statements) or `debug` (`verbose` with the tasks' full step traces
+	// and debug statements). Log files always receive the full traces.
+	Verbosity string
+
+	// Wait is to wait for the workspace lock held by another run instead of
+	// failing immediately.
+	Wait bool
+
+	// Force is to run all tasks even when they are up to date with their
+	// declared `Inputs` and `Outputs`.
+	Force bool
+
+	// UpdateLock is to accept the setup sources drifted from `setup.lock`
+	// by re-pinning them instead of failing.
+	UpdateLock bool
+
+	// Offline is to source the setup downloads only from the user level
+	// download cache and to fail when any of them is missing.
+	Offline bool
+}
+
+func _sanitizeOptions(opts *Options) (out *Options, err error) {
+	out = &Options{}
+	if opts != nil {
+		*out = *opts
+	}
+
+	switch out.LogFormat {
+	case "":
+		out.LogFormat = libmonteur.LOG_FORMAT_TEXT
+	case libmonteur.LOG_FORMAT_TEXT, libmonteur.LOG_FORMAT_JSON:
+	default:
+		return nil, fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_LOG_FORMAT_UNKNOWN,
+			out.LogFormat,
+		)
+	}
+
+	switch out.Verbosity {
+	case "":
+		out.Verbosity = libmonteur.LOG_VERBOSITY_NORMAL
+	case libmonteur.LOG_VERBOSITY_QUIET,
+		libmonteur.LOG_VERBOSITY_NORMAL,
+		libmonteur.LOG_VERBOSITY_VERBOSE,
+		libmonteur.LOG_VERBOSITY_DEBUG:
+	default:
+		return nil, fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_LOG_VERBOSITY_BAD,
+			out.Verbosity,
+		)
+	}
+
+	if strings.HasPrefix(out.Events, "-") {
+		return nil, fmt.Errorf("%s: bad destination '%s'",
+			libmonteur.ERROR_LOG_EVENTS_OPEN,
+			out.Events,
+		)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libdownloads"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/styler"
+)
+
+type apiCache struct {
+	cache *libdownloads.Cache
+
+	Action string
+}
+
+// Run is to execute the apiCache algorithm.
+func (api *apiCache) Run() (statusCode int) {
+	var list []*libdownloads.Entry
+	var err error
+
+	api.cache, err = libdownloads.Open()
+	if err != nil {
+		return _reportError(nil, libmonteur.ERROR_CACHE, err)
+	}
+
+	switch api.Action {
+	case libmonteur.DOWNLOADS_ACTION_LIST:
+		list, err = api.cache.List()
+	case libmonteur.DOWNLOADS_ACTION_PRUNE:
+		list, err = api.cache.Prune(libmonteur.DOWNLOADS_PRUNE_AGE)
+	case libmonteur.DOWNLOADS_ACTION_CLEAR:
+		list, err = api.cache.Clear()
+	default:
+		err = fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_DOWNLOADS_ACTION_UNKNOWN,
+			api.Action,
+		)
+	}
+
+	if err != nil {
+		return _reportError(nil, libmonteur.ERROR_CACHE, err)
+	}
+
+	api._print(list)
+
+	return STATUS_OK
+}
+
+func (api *apiCache) _print(list []*libdownloads.Entry) {
+	var title string
+	var size int64
+
+	switch api.Action {
+	case libmonteur.DOWNLOADS_ACTION_PRUNE:
+		title = "Pruned Downloads"
+	case libmonteur.DOWNLOADS_ACTION_CLEAR:
+		title = "Cleared Downloads"
+	default:
+		title = "Cached Downloads"
+	}
+
+	entries := []string{}
+	for _, entry := range list {
+		size += entry.Size
+		entries = append(entries, fmt.Sprintf("%s (%s, last used %s) %s",
+			entry.Archive,
+			styler.Bytes(entry.Size),
+			entry.LastUsed.Local().Format(time.RFC3339),
+			entry.URL,
+		))
+	}
+
+	fmt.Fprintf(os.Stdout, "%s%s%s%s",
+		styler.BoxString(title, styler.BORDER_DOUBLE),
+		styler.PortraitKV("Directory", api.cache.Dir),
+		styler.PortraitKV("Total", fmt.Sprintf("%d file(s), %s",
+			len(list),
+			styler.Bytes(size),
+		)),
+		styler.PortraitKArray("Files", entries),
+	)
+}
@@ -16,13 +16,20 @@
 package monteur
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libcmd"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libhistory"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblock"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblockfile"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libprogress"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libreport"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libworkspace"
 )
 
@@ -31,13 +38,24 @@ type apiCommand struct {
 	workspace *libworkspace.Workspace
 	settings  *libcmd.Run
 	logger    *liblog.Logger
+	options   *Options
+	events    *os.File
+	lock      *liblock.Lock
+	pins      *liblockfile.Lockfile
 
 	Job      string
 	ErrorTag string
+	Options  *Options
 }
 
 // Run is to execute the apiCommand algorithm.
 func (api *apiCommand) Run() (statusCode int) {
+	defer func() {
+		_ = api.lock.Release()
+		_closeEvents(api.events)
+		api.workspace.Close()
+	}()
+
 	err := api._init()
 	if err != nil {
 		return _reportError(api.logger, api.ErrorTag, err)
@@ -52,15 +70,36 @@ func (api *apiCommand) Run() (statusCode int) {
 	// execute each task in parallel
 	c := &conductor.Conductor{
 		Runners: api.workers,
-		Log:     api.logger,
+		Log:     api.logger.Progress(),
+		Filter:  api.workspace.Secrets.Filter,
+		Stage:   api.Job,
+	}
+
+	if api.events != nil {
+		c.Events = api.events
+	}
+
+	renderer := api._renderer()
+	if renderer != nil {
+		c.Log = renderer
+		c.Display = renderer
+		renderer.Start()
 	}
 
 	err = c.Run()
 	if err != nil {
+		renderer.Stop()
 		return _reportError(api.logger, api.ErrorTag, err)
 	}
 
 	err = c.Coordinate()
+	renderer.Stop()
+	api._record(api._report(c, err))
+	if err != nil {
+		return _reportError(api.logger, api.ErrorTag, err)
+	}
+
+	err = api.pins.Save()
 	if err != nil {
 		return _reportError(api.logger, api.ErrorTag, err)
 	}
@@ -90,10 +129,22 @@ func (api *apiCommand) _filter(path string, info os.FileInfo, err error) error {
 		s.Variables[k] = v
 	}
 
+	// each task records its own queried secrets
+	secrets := api.workspace.Secrets.Session()
+	s.Variables[libmonteur.VAR_SECRETS] = secrets
+	s.Variables[libmonteur.VAR_LOG_FORMAT] = api.options.LogFormat
+	s.Variables[libmonteur.VAR_LOG_VERBOSITY] = api.options.Verbosity
+	s.Variables[libmonteur.VAR_FORCE] = api.options.Force
+	s.Variables[libmonteur.VAR_OFFLINE] = api.options.Offline
+
+	if api.pins != nil {
+		s.Variables[libmonteur.VAR_SETUP_LOCK] = api.pins
+	}
+
 	_logVariables(api.logger, &s.Variables)
 
 	api.logger.Info("Decode Task Data from config file...")
-	err = s.Parse(path, api.workspace.Secrets)
+	err = s.Parse(path, secrets)
 	if err != nil {
 		return err //nolint:wrapcheck
 	}
@@ -109,12 +160,34 @@ func (api *apiCommand) _filter(path string, info os.FileInfo, err error) error {
 func (api *apiCommand) _init() (err error) {
 	api.workers = map[string]conductor.Job{}
 
+	api.options, err = _sanitizeOptions(api.Options)
+	if err != nil {
+		return err
+	}
+
 	err = _initWorkspace(api.Job, &api.workspace)
 	if err != nil {
 		return err
 	}
 
-	err = _initLogger(&api.logger, api.workspace)
+	err = _initLogger(&api.logger, api.workspace, api.options)
+	if err != nil {
+		return err
+	}
+
+	api.events, err = _openEvents(api.options.Events)
+	if err != nil {
+		return err
+	}
+
+	err = api._lock()
+	if err != nil {
+		return err
+	}
+
+	api._retainLogs()
+
+	err = api._loadPins()
 	if err != nil {
 		return err
 	}
@@ -132,3 +205,142 @@ func (api *apiCommand) _init() (err error) {
 
 	return nil
 }
+
+// _lock acquires the workspace lock before touching any shared directories.
+// When Options.Wait is set, it polls until the other run releases the lock.
+func (api *apiCommand) _lock() (err error) {
+	var holder *liblock.Holder
+
+	lock := &liblock.Lock{
+		Path:  api.workspace.Filesystem.LockFile,
+		Stage: api.Job,
+	}
+
+	api.logger.Info("Locking workspace: '%s'", lock.Path)
+
+	for waiting := false; ; waiting = true {
+		holder, err = lock.Acquire()
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if holder == nil {
+			api.lock = lock
+			return nil
+		}
+
+		if !api.options.Wait {
+			return fmt.Errorf("%s: %s. Retry later or use --wait",
+				libmonteur.ERROR_LOCK_BUSY,
+				holder,
+			)
+		}
+
+		if !waiting {
+			api.logger.Warning("Waiting for workspace lock held by %s...",
+				holder,
+			)
+		}
+
+		time.Sleep(liblock.WAIT_INTERVAL)
+	}
+}
+
+// _loadPins reads the setup lockfile only for the setup job.
+func (api *apiCommand) _loadPins() (err error) {
+	if api.Job != libmonteur.JOB_SETUP {
+		return nil
+	}
+
+	api.pins = &liblockfile.Lockfile{
+		Path:   api.workspace.Filesystem.SetupLockFile,
+		Update: api.options.UpdateLock,
+	}
+
+	api.logger.Info("Loading setup lockfile: '%s'", api.pins.Path)
+
+	return api.pins.Load() //nolint:wrapcheck
+}
+
+func (api *apiCommand) _retainLogs() {
+	removed, err := api.workspace.PruneLogs()
+	for _, path := range removed {
+		api.logger.Info("Removed old log: '%s'", path)
+	}
+
+	if err != nil {
+		api.logger.Warning("%s", err)
+	}
+
+	err = api.workspace.LinkLatestLog()
+	if err != nil {
+		api.logger.Warning("%s", err)
+	}
+}
+
+// _renderer creates the live progress display only for interactive terminal
+// with the normal text printouts. Otherwise, the plain lines are used.
+func (api *apiCommand) _renderer() *libprogress.Renderer {
+	if api.options.LogFormat != libmonteur.LOG_FORMAT_TEXT ||
+		api.options.Verbosity != libmonteur.LOG_VERBOSITY_NORMAL ||
+		!libprogress.IsInteractive(os.Stdout) {
+		return nil
+	}
+
+	log := api.logger.Progress()
+	log.ToTerminal = false
+
+	return &libprogress.Renderer{
+		Log:      log,
+		Terminal: os.Stdout,
+	}
+}
+
+func (api *apiCommand) _report(c *conductor.Conductor,
+	failure error) (report *libreport.Report) {
+	report = &libreport.Report{
+		Started: *api.workspace.Timestamp,
+		Stage:   api.Job,
+		Dir:     api.workspace.Filesystem.WorkspaceLogDir,
+	}
+
+	if failure != nil {
+		report.Error = failure.Error()
+	}
+
+	for _, result := range c.Results() {
+		report.Add(result)
+	}
+
+	err := report.Write()
+	if err != nil {
+		api.logger.Warning("%s", err)
+		return report
+	}
+
+	api.logger.Info("Report: '%s'", filepath.Join(report.Dir,
+		libmonteur.FILE_REPORT_HTML,
+	))
+
+	return report
+}
+
+func (api *apiCommand) _record(report *libreport.Report) {
+	run := &libhistory.Run{
+		Timestamp: report.Started.UTC().Format(time.RFC3339),
+		Stage:     report.Stage,
+		Status:    report.Status(),
+		Duration:  report.Duration().Milliseconds(),
+	}
+
+	for _, task := range report.Tasks {
+		run.Add(task.Result)
+	}
+
+	err := run.Save(filepath.Join(api.workspace.Filesystem.HistoryDir,
+		libhistory.Filename(api.Job),
+	))
+	if err != nil {
+		api.logger.Warning("%s", err)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/filesystem"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libcmd"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libworkspace"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/styler"
+)
+
+type apiInspect struct {
+	workspace *libworkspace.Workspace
+	inspector *libcmd.Inspector
+	tasks     []*libcmd.Inspection
+
+	Stage string
+	Task  string
+}
+
+// Run is to execute the apiInspect algorithm.
+func (api *apiInspect) Run() (statusCode int) {
+	var subject *libcmd.Inspection
+
+	defer func() {
+		api.workspace.Close()
+	}()
+
+	err := api._init()
+	if err != nil {
+		return _reportError(nil, libmonteur.ERROR_INSPECT, err)
+	}
+
+	if api.Task == "" {
+		api._print(api.inspector.Stage())
+		api._printTasks()
+		api._printPathing()
+
+		return STATUS_OK
+	}
+
+	for _, v := range api.tasks {
+		name := filepath.Base(v.Path)
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+
+		if v.Name == api.Task || name == api.Task {
+			subject = v
+			break
+		}
+	}
+
+	if subject == nil {
+		return _reportError(nil, libmonteur.ERROR_INSPECT, fmt.Errorf(
+			"%s: '%s'",
+			libmonteur.ERROR_INSPECT_TASK_MISSING,
+			api.Task,
+		))
+	}
+
+	api._print(subject)
+	for _, k := range _sortedInspections(subject.Packages) {
+		api._print(subject.Packages[k])
+	}
+	api._printPathing()
+
+	return STATUS_OK
+}
+
+func (api *apiInspect) _init() (err error) {
+	if !_isStage(api.Stage) {
+		return fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_INSPECT_STAGE_UNKNOWN,
+			api.Stage,
+		)
+	}
+
+	err = _initWorkspace(api.Stage, &api.workspace)
+	if err != nil {
+		return err
+	}
+
+	api.inspector = &libcmd.Inspector{Job: api.Stage}
+	api.inspector.Init(*api.workspace.Variables)
+
+	if _, err = os.Stat(api.workspace.JobTOMLFile); err == nil {
+		_, err = api.inspector.Settings(api.workspace.JobTOMLFile)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	api.tasks = []*libcmd.Inspection{}
+	if !filesystem.IsDirExists(api.workspace.ConfigDir) {
+		return nil
+	}
+
+	//nolint:wrapcheck
+	return filepath.Walk(api.workspace.ConfigDir, api._filter)
+}
+
+func (api *apiInspect) _filter(path string, info os.FileInfo, err error) error {
+	var ok bool
+	var subject *libcmd.Inspection
+
+	ok, err = libmonteur.AcceptTOML(path, info, err)
+	if !ok {
+		return err //nolint:wrapcheck
+	}
+
+	subject, err = api.inspector.Task(path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	api.tasks = append(api.tasks, subject)
+
+	return nil
+}
+
+func (api *apiInspect) _print(subject *libcmd.Inspection) {
+	var keys []string
+	var width int
+
+	title := "Stage: " + api.Stage
+	if subject.Name != "" {
+		title += " / " + subject.Name
+	}
+
+	fmt.Fprintf(os.Stdout, "%s",
+		styler.BoxString(title, styler.BORDER_DOUBLE),
+	)
+
+	if subject.Path != "" {
+		fmt.Fprintf(os.Stdout, "%s",
+			styler.PortraitKV("Path", subject.Path),
+		)
+	}
+
+	for k := range subject.Variables {
+		keys = append(keys, k)
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(os.Stdout, "%-*s = %s    ⟵ %s\n",
+			width,
+			k,
+			api._value(subject.Variables[k]),
+			subject.Origins[k],
+		)
+	}
+	fmt.Fprintf(os.Stdout, "\n")
+}
+
+func (api *apiInspect) _value(value interface{}) string {
+	var out string
+
+	switch v := value.(type) {
+	case *libsecrets.Secrets:
+		return libmonteur.SECRET_REDACTED
+	case *libmonteur.Software:
+		out = fmt.Sprintf("%T{Name: %#v, Version: %#v}",
+			v,
+			v.Name,
+			v.Version,
+		)
+	case *time.Time:
+		out = v.String()
+	default:
+		out = fmt.Sprintf("%#v", v)
+	}
+
+	return api.workspace.Secrets.Filter(out)
+}
+
+func (api *apiInspect) _printTasks() {
+	list := []string{}
+
+	for _, v := range api.tasks {
+		list = append(list, v.Name+" ("+v.Path+")")
+	}
+
+	fmt.Fprintf(os.Stdout, "%s%s",
+		styler.BoxString("Tasks", styler.BORDER_SINGLE),
+		styler.PortraitKArray("Available Tasks", list),
+	)
+}
+
+func (api *apiInspect) _printPathing() {
+	fmt.Fprintf(os.Stdout, "%s%s",
+		styler.BoxString("Pathing", styler.BORDER_SINGLE),
+		api.workspace.Filesystem.String(),
+	)
+}
+
+func _sortedInspections(list map[string]*libcmd.Inspection) (out []string) {
+	for k := range list {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+
+	return out
+}
@@ -0,0 +1,182 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/secrets"
+)
+
+type apiSecrets struct {
+	key []byte
+
+	Action string
+	Path   string
+}
+
+// Run is to execute the apiSecrets algorithm.
+func (api *apiSecrets) Run() (statusCode int) {
+	var err error
+
+	if api.Path == "" {
+		return _reportError(nil, libmonteur.ERROR_SECRETS, fmt.Errorf(
+			libmonteur.ERROR_SECRET_PATH_MISSING,
+		))
+	}
+
+	defer func() {
+		secrets.Zero(api.key)
+	}()
+
+	switch api.Action {
+	case libmonteur.SECRET_ACTION_ENCRYPT:
+		api.key, err = libsecrets.Key(true)
+		if err == nil {
+			err = libsecrets.EncryptFile(api.Path, api.key)
+		}
+	case libmonteur.SECRET_ACTION_DECRYPT:
+		api.key, err = libsecrets.Key(false)
+		if err == nil {
+			err = libsecrets.DecryptFile(api.Path, api.key)
+		}
+	case libmonteur.SECRET_ACTION_EDIT:
+		err = api._edit()
+	default:
+		err = fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_SECRET_ACTION_UNKNOWN,
+			api.Action,
+		)
+	}
+
+	if err != nil {
+		return _reportError(nil, libmonteur.ERROR_SECRETS, err)
+	}
+
+	fmt.Fprintf(os.Stdout, "Secrets %s %s %s\n",
+		api.Action,
+		api.Path,
+		libmonteur.LOG_SUCCESS,
+	)
+
+	return STATUS_OK
+}
+
+func (api *apiSecrets) _edit() (err error) {
+	var plain, edited []byte
+	var tmp *os.File
+
+	_, err = os.Stat(api.Path)
+	exists := err == nil
+
+	api.key, err = libsecrets.Key(!exists)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if exists {
+		plain, err = libsecrets.ReadFile(api.Path, api.key)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+	defer secrets.Zero(plain)
+
+	// stage the plain data in a private temporary file for the editor
+	tmp, err = os.CreateTemp("", "monteur-secrets-*.toml")
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SECRET_EDIT, err)
+	}
+	defer api._shred(tmp.Name())
+
+	err = tmp.Chmod(libsecrets.FILE_PERMISSION)
+	if err == nil {
+		_, err = tmp.Write(plain)
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SECRET_EDIT, err)
+	}
+
+	err = api._runEditor(tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	edited, err = os.ReadFile(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SECRET_EDIT, err)
+	}
+	defer secrets.Zero(edited)
+
+	if exists && bytes.Equal(plain, edited) {
+		return nil
+	}
+
+	plain, err = libsecrets.Seal(edited, api.key)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	//nolint:wrapcheck
+	return os.WriteFile(api.Path, plain, libsecrets.FILE_PERMISSION)
+}
+
+func (api *apiSecrets) _runEditor(path string) (err error) {
+	editor := strings.Fields(os.Getenv(libmonteur.ENV_EDITOR))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		}
+	}
+
+	//nolint:gosec
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SECRET_EDIT, err)
+	}
+
+	return nil
+}
+
+func (api *apiSecrets) _shred(path string) {
+	info, err := os.Stat(path)
+	if err == nil {
+		_ = os.WriteFile(path,
+			make([]byte, info.Size()),
+			libsecrets.FILE_PERMISSION,
+		)
+	}
+
+	_ = os.Remove(path)
+}
@@ -0,0 +1,218 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libhistory"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libworkspace"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/styler"
+)
+
+type apiStats struct {
+	workspace *libworkspace.Workspace
+	stats     *libhistory.Stats
+
+	Stage     string
+	Runs      uint
+	Threshold uint
+}
+
+// Run is to execute the apiStats algorithm.
+func (api *apiStats) Run() (statusCode int) {
+	var runs []*libhistory.Run
+
+	defer func() {
+		api.workspace.Close()
+	}()
+
+	if !_isStage(api.Stage) {
+		return _reportError(nil, libmonteur.ERROR_STATS, fmt.Errorf(
+			"%s: '%s'",
+			libmonteur.ERROR_INSPECT_STAGE_UNKNOWN,
+			api.Stage,
+		))
+	}
+
+	err := _initWorkspace(api.Stage, &api.workspace)
+	if err != nil {
+		return _reportError(nil, libmonteur.ERROR_STATS, err)
+	}
+
+	runs, err = libhistory.Load(filepath.Join(
+		api.workspace.Filesystem.HistoryDir,
+		libhistory.Filename(api.Stage),
+	))
+	if err != nil {
+		return _reportError(nil, libmonteur.ERROR_STATS, err)
+	}
+
+	if api.Runs == 0 {
+		api.Runs = libhistory.DEFAULT_WINDOW
+	}
+
+	if api.Threshold == 0 {
+		api.Threshold = libhistory.DEFAULT_THRESHOLD
+	}
+
+	api.stats = libhistory.Analyze(runs, api.Runs, api.Threshold)
+
+	api._printSummary()
+	api._printTasks()
+	api._printCriticalPath()
+	api._printSlowestSteps()
+	api._printRegressions()
+
+	return STATUS_OK
+}
+
+func (api *apiStats) _printSummary() {
+	fmt.Fprintf(os.Stdout, "%s%s%s%s%s",
+		styler.BoxString("Stats: "+api.Stage, styler.BORDER_DOUBLE),
+		styler.PortraitKV("Latest Run", api.stats.Latest.Timestamp),
+		styler.PortraitKV("Status", api.stats.Latest.Status),
+		styler.PortraitKV("Baseline", fmt.Sprintf(
+			"median of %d previous run(s), threshold +%d%%",
+			api.stats.Runs-1,
+			api.Threshold,
+		)),
+		styler.PortraitKV("Wall Time", _formatTrend(api.stats.Stage)),
+	)
+}
+
+func (api *apiStats) _printTasks() {
+	list := []string{}
+
+	for _, trend := range api.stats.Tasks {
+		list = append(list, trend.Name+": "+_formatTrend(trend))
+	}
+
+	fmt.Fprintf(os.Stdout, "%s%s",
+		styler.BoxString("Tasks", styler.BORDER_SINGLE),
+		styler.PortraitKArray("Per-Task Trends", list),
+	)
+}
+
+func (api *apiStats) _printCriticalPath() {
+	task := api.stats.Critical
+	if task == nil {
+		return
+	}
+
+	list := []string{}
+	for _, step := range task.Steps {
+		list = append(list, fmt.Sprintf("%s (%s)",
+			step.Name,
+			_formatDuration(time.Duration(step.Duration)*time.Millisecond),
+		))
+	}
+
+	fmt.Fprintf(os.Stdout, "%s%s%s",
+		styler.BoxString("Critical Path", styler.BORDER_SINGLE),
+		styler.PortraitKV("Task", fmt.Sprintf("%s (%s)",
+			task.Name,
+			_formatDuration(time.Duration(task.Duration)*time.Millisecond),
+		)),
+		styler.PortraitKArray("Steps", list),
+	)
+}
+
+func (api *apiStats) _printSlowestSteps() {
+	list := []string{}
+
+	for i, trend := range api.stats.Steps {
+		if i >= libhistory.SLOWEST_STEPS {
+			break
+		}
+
+		list = append(list, trend.Name+": "+_formatTrend(trend))
+	}
+
+	fmt.Fprintf(os.Stdout, "%s%s",
+		styler.BoxString("Slowest Steps", styler.BORDER_SINGLE),
+		styler.PortraitKArray("Latest Run", list),
+	)
+}
+
+func (api *apiStats) _printRegressions() {
+	list := []string{}
+
+	for _, trend := range api.stats.Regressions {
+		list = append(list, trend.Name+": "+_formatTrend(trend))
+	}
+
+	fmt.Fprintf(os.Stdout, "%s", styler.BoxString("Regressions",
+		styler.BORDER_SINGLE,
+	))
+
+	if len(list) == 0 {
+		fmt.Fprintf(os.Stdout, "None %s\n", libmonteur.LOG_SUCCESS)
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, "%s", styler.PortraitKArray("Regressed", list))
+}
+
+func _formatTrend(trend *libhistory.Trend) (s string) {
+	s = _formatDuration(trend.Latest)
+
+	if trend.Median != 0 {
+		s += fmt.Sprintf(" (median %s, %+.1f%%)",
+			_formatDuration(trend.Median),
+			trend.Change,
+		)
+	}
+
+	s += " " + _sparkline(trend.History)
+
+	if trend.Regressed {
+		s += " ⚠ REGRESSED"
+	}
+
+	return s
+}
+
+func _formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+func _sparkline(list []time.Duration) (s string) {
+	var max time.Duration
+
+	bars := []rune("▁▂▃▄▅▆▇█")
+
+	for _, d := range list {
+		if d > max {
+			max = d
+		}
+	}
+
+	for _, d := range list {
+		i := 0
+		if max > 0 {
+			i = int(int64(d) * int64(len(bars)-1) / int64(max))
+		}
+
+		s += string(bars[i])
+	}
+
+	return s
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/filesystem"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libcmd"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libworkspace"
+)
+
+type apiValidate struct {
+	workspace *libworkspace.Workspace
+	validator *libcmd.Validator
+
+	files  int
+	issues int
+	warned bool
+}
+
+// Run is to execute the apiValidate algorithm.
+func (api *apiValidate) Run() (statusCode int) {
+	var err error
+
+	defer func() {
+		api.workspace.Close()
+	}()
+
+	for _, job := range []string{
+		libmonteur.JOB_SETUP,
+		libmonteur.JOB_CLEAN,
+		libmonteur.JOB_TEST,
+		libmonteur.JOB_PREPARE,
+		libmonteur.JOB_BUILD,
+		libmonteur.JOB_PACKAGE,
+		libmonteur.JOB_RELEASE,
+		libmonteur.JOB_COMPOSE,
+		libmonteur.JOB_PUBLISH,
+	} {
+		err = api._validateJob(job)
+		if err != nil {
+			return _reportError(nil, libmonteur.ERROR_VALIDATE, err)
+		}
+	}
+
+	if api.issues != 0 {
+		return _reportError(nil, libmonteur.ERROR_VALIDATE, fmt.Errorf(
+			"%s: %d issue(s) in %d file(s)",
+			libmonteur.ERROR_VALIDATE_FAILED,
+			api.issues,
+			api.files,
+		))
+	}
+
+	fmt.Fprintf(os.Stdout, "Validated %d file(s) %s\n",
+		api.files,
+		libmonteur.LOG_SUCCESS,
+	)
+
+	return STATUS_OK
+}
+
+func (api *apiValidate) _validateJob(job string) (err error) {
+	var list []*libcmd.Issue
+
+	api.workspace.Close()
+
+	err = _initWorkspace(job, &api.workspace)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range api.workspace.Secrets.Warnings() {
+		if !api.warned {
+			fmt.Fprintf(os.Stderr, "[ WARNING ] %s\n", warning)
+		}
+	}
+	api.warned = true
+
+	api.validator = &libcmd.Validator{
+		Job:       job,
+		Secrets:   api.workspace.Secrets,
+		Variables: map[string]interface{}{},
+	}
+
+	for k, v := range *api.workspace.Variables {
+		api.validator.Variables[k] = v
+	}
+
+	if _, err = os.Stat(api.workspace.JobTOMLFile); err == nil {
+		list, err = api.validator.Settings(api.workspace.JobTOMLFile)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		api._report(list)
+	}
+
+	if !filesystem.IsDirExists(api.workspace.ConfigDir) {
+		return nil
+	}
+
+	//nolint:wrapcheck
+	return filepath.Walk(api.workspace.ConfigDir, api._filter)
+}
+
+func (api *apiValidate) _filter(path string,
+	info os.FileInfo, err error) error {
+	var ok bool
+	var list []*libcmd.Issue
+
+	ok, err = libmonteur.AcceptTOML(path, info, err)
+	if !ok {
+		return err //nolint:wrapcheck
+	}
+
+	list, err = api.validator.Task(path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	api._report(list)
+
+	return nil
+}
+
+func (api *apiValidate) _report(list []*libcmd.Issue) {
+	api.files++
+
+	for _, issue := range list {
+		fmt.Fprintf(os.Stderr, "%s\n", issue)
+		api.issues++
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
@@ -38,8 +39,15 @@ func _logVariables(l *liblog.Logger, list *map[string]interface{}) {
 	l.Info(libmonteur.LOG_SUCCESS + "\n")
 }
 
-func _initLogger(l **liblog.Logger, w *libworkspace.Workspace) (err error) {
-	*l = &liblog.Logger{ToTerminal: true}
+func _initLogger(l **liblog.Logger,
+	w *libworkspace.Workspace, opts *Options) (err error) {
+	*l = &liblog.Logger{
+		ToTerminal: true,
+		DebugMode:  opts.Verbosity == libmonteur.LOG_VERBOSITY_DEBUG,
+		Format:     opts.LogFormat,
+		Verbosity:  opts.Verbosity,
+		Stage:      w.Job,
+	}
 	(*l).Init(w.Secrets)
 
 	err = (*l).Add(liblog.TYPE_STATUS, filepath.Join(
@@ -60,9 +68,82 @@ func _initLogger(l **liblog.Logger, w *libworkspace.Workspace) (err error) {
 
 	(*l).Info("\n%s", w.String())
 
+	for _, warning := range w.Secrets.Warnings() {
+		(*l).Warning("%s", warning)
+	}
+
 	return nil
 }
 
+func _openEvents(target string) (f *os.File, err error) {
+	var fd uint64
+
+	if target == "" {
+		return nil, nil
+	}
+
+	fd, err = strconv.ParseUint(target, 10, 32)
+	if err == nil {
+		f = os.NewFile(uintptr(fd), "events")
+		if f == nil {
+			return nil, fmt.Errorf("%s: bad file descriptor '%s'",
+				libmonteur.ERROR_LOG_EVENTS_OPEN,
+				target,
+			)
+		}
+
+		_, err = f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s",
+				libmonteur.ERROR_LOG_EVENTS_OPEN,
+				err,
+			)
+		}
+
+		return f, nil
+	}
+
+	f, err = os.OpenFile(target,
+		os.O_WRONLY|os.O_CREATE|os.O_TRUNC,
+		libmonteur.PERMISSION_FILE,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_LOG_EVENTS_OPEN,
+			err,
+		)
+	}
+
+	return f, nil
+}
+
+func _closeEvents(f *os.File) {
+	// never close the standard streams given by file descriptor
+	if f == nil || f.Fd() <= 2 {
+		return
+	}
+
+	_ = f.Close()
+}
+
+func _isStage(stage string) bool {
+	switch stage {
+	case libmonteur.JOB_SETUP,
+		libmonteur.JOB_CLEAN,
+		libmonteur.JOB_TEST,
+		libmonteur.JOB_PREPARE,
+		libmonteur.JOB_BUILD,
+		libmonteur.JOB_PACKAGE,
+		libmonteur.JOB_RELEASE,
+		libmonteur.JOB_COMPOSE,
+		libmonteur.JOB_PUBLISH:
+		return true
+	default:
+	}
+
+	return false
+}
+
 func _initWorkspace(job string, w **libworkspace.Workspace) (err error) {
 	*w = &libworkspace.Workspace{Job: job}
 
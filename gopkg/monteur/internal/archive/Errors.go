@@ -26,7 +26,8 @@ const (
 	ERROR_ARCHIVE_NOT_DIR     = "archive pathing is not a directory"
 	ERROR_ARCHIVE_READ        = "error reading archive file"
 
-	ERROR_COMPRESSION_INVALID = "invalid compression level"
+	ERROR_COMPRESSION_INVALID     = "invalid compression level"
+	ERROR_COMPRESSION_UNSUPPORTED = "compression is not supported for format"
 
 	ERROR_DIR         = "error with directory"
 	ERROR_DIR_INVALID = "path is not a directory"
@@ -59,6 +60,7 @@ const (
 	ERROR_SYMLINK_CREATE            = "error when creating symlink"
 	ERROR_SYMLINK_EVAL              = "error evaluating symlink"
 	ERROR_SYMLINK_EVAL_FAILED       = "failed to evaluate symlink's target"
+	ERROR_SYMLINK_LOOP              = "symlink loops into its own directory"
 	ERROR_SYMLINK_PATH_EMPTY        = "given symlink pathing is empty"
 	ERROR_SYMLINK_READ              = "error reading symlink's target"
 	ERROR_SYMLINK_TARGET_ABS_FAILED = "failed to absolute symlink's target path"
@@ -16,6 +16,7 @@
 package archive
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -75,10 +76,25 @@ func MkdirAll(path string, mode os.FileMode, decision bool) (err error) {
 // It calls `os.Stat(...)` against the target's path to ensure the target exists
 // and healthy.
 //
-// It also calls `SanitizeCompressionPath(...)` internally for checking the
-// target's path validity for compression.
+// The resolved target **MUST** stay inside the `base` directory. The output is
+// the target pathing expressed under the given `base` pathing.
 func EvalSymlink(base string,
 	path string) (out string, info os.FileInfo, err error) {
+	var root string
+
+	root, err = filepath.Abs(base)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("%s (%s): %s",
+			ERROR_SYMLINK_EVAL_FAILED,
+			base,
+			err,
+		)
+	}
+
 	out, err = filepath.EvalSymlinks(path)
 	if err != nil {
 		return "", nil, fmt.Errorf("%s (%s): %s",
@@ -97,7 +113,11 @@ func EvalSymlink(base string,
 		)
 	}
 
-	out, err = SanitizeCompressionPath(base, out)
+	out, err = filepath.Rel(root, out)
+	if err == nil {
+		out, err = SanitizeCompressionPath(base, out)
+	}
+
 	if err != nil {
 		return "", nil, fmt.Errorf("%s where %s",
 			ERROR_SYMLINK_EVAL,
@@ -273,6 +293,11 @@ func RestoreMetadata(path string,
 		return fmt.Errorf("%s: %s", ERROR_FILE_CHTIMES_FAILED, err)
 	}
 
+	// symlink has no mode of its own and chmod alters its target instead
+	if isSymlink {
+		return nil
+	}
+
 	// restore file mode
 	err = os.Chmod(path, mode)
 	if err != nil {
@@ -285,3 +310,65 @@ func RestoreMetadata(path string,
 
 	return nil
 }
+
+// ExtractFile is to restore a single decompressed stream into a file.
+//
+// It is meant for single file compression formats (e.g. `.gz`) without any
+// archive headers. The given name is checked via `SanitizeCompressionPath(...)`
+// to ensure the file is only created inside the `raw` directory.
+//
+// If mTime is unset (zero), the current time is used.
+func ExtractFile(r io.Reader, raw string, name string, mTime time.Time,
+	createDirectory bool, overwrite bool) (err error) {
+	var f *os.File
+	var bufWriter *bufio.Writer
+	var path string
+
+	now := time.Now()
+
+	// create directory if requested
+	err = MkdirAll(raw, PERMISSION_DIR, createDirectory)
+	if err != nil {
+		return err
+	}
+
+	// check target path is valid
+	path, err = SanitizeCompressionPath(raw, name)
+	if err != nil {
+		return err
+	}
+
+	// overwrite target if requested
+	err = Overwrite(path, overwrite)
+	if err != nil {
+		return err
+	}
+
+	// create destination for write
+	f, err = os.OpenFile(path,
+		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
+		PERMISSION_FILE,
+	)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s", ERROR_FILE_WRITE, path, err)
+	}
+
+	// add buffer for write
+	bufWriter = bufio.NewWriterSize(f, int(2*COPY_SIZE))
+
+	// perform file extraction
+	err = ExtractCopy(bufWriter, r, COPY_SIZE, path)
+	_ = bufWriter.Flush()
+	_ = f.Sync()
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	// restore metadata
+	if mTime.IsZero() {
+		mTime = now
+	}
+
+	return RestoreMetadata(path, now, mTime, PERMISSION_FILE, false)
+}
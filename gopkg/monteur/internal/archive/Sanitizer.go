@@ -133,7 +133,7 @@ func SanitizeCompressionPath(base string, path string) (v string, err error) {
 
 	// Scan for G305: Zip Slip Vulnerability
 	v = filepath.Join(base, path)
-	if !strings.HasPrefix(v, filepath.Clean(base)) {
+	if v != base && !strings.HasPrefix(v, base+string(filepath.Separator)) {
 		return "", fmt.Errorf("%s: %s", ERROR_PATH_OUT_OF_BOUND, path)
 	}
 
@@ -0,0 +1,126 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testSanitizeCompressionPath,
+			Description: `
+SanitizeCompressionPath should accept the path when:
+1. the path is a relative path inside base.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testSanitizeCompressionPath,
+			Description: `
+SanitizeCompressionPath should reject the path when:
+1. the path is a '../' entry leaving base.
+`,
+			Switches: map[string]bool{
+				useParentPath: true,
+				expectError:   true,
+			},
+		}, {
+			UID:      3,
+			TestType: testSanitizeCompressionPath,
+			Description: `
+SanitizeCompressionPath should rebase the path inside base when:
+1. the path is an absolute entry.
+`,
+			Switches: map[string]bool{
+				useAbsolutePath: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testSanitizeCompressionPath,
+			Description: `
+SanitizeCompressionPath should reject the path when:
+1. the path leads into a sibling directory sharing base's name prefix.
+`,
+			Switches: map[string]bool{
+				useSiblingPath: true,
+				expectError:    true,
+			},
+		}, {
+			UID:      5,
+			TestType: testSanitizeCompressionPath,
+			Description: `
+SanitizeCompressionPath should accept the path when:
+1. the path is base itself.
+`,
+			Switches: map[string]bool{
+				useBasePath: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testEvalSymlink,
+			Description: `
+EvalSymlink should resolve the symlink when:
+1. the symlink targets a relative file inside base.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      7,
+			TestType: testEvalSymlink,
+			Description: `
+EvalSymlink should resolve the symlink when:
+1. the symlink targets an absolute file inside base.
+`,
+			Switches: map[string]bool{
+				useAbsolutePath: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testEvalSymlink,
+			Description: `
+EvalSymlink should reject the symlink when:
+1. the symlink escapes base into a sibling directory sharing base's name
+   prefix.
+`,
+			Switches: map[string]bool{
+				useEscapingTarget: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      9,
+			TestType: testEvalSymlink,
+			Description: `
+EvalSymlink should resolve the symlink when:
+1. base is itself a symlink to the raw directory.
+2. the symlink targets a relative file inside base.
+`,
+			Switches: map[string]bool{
+				useSymlinkedBase: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testEvalSymlink,
+			Description: `
+EvalSymlink should reject the symlink when:
+1. base is itself a symlink to the raw directory.
+2. the symlink escapes base.
+`,
+			Switches: map[string]bool{
+				useSymlinkedBase:  true,
+				useEscapingTarget: true,
+				expectError:       true,
+			},
+		},
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testSanitizeCompressionPath = "testSanitizeCompressionPath"
+	testEvalSymlink             = "testEvalSymlink"
+)
+
+const (
+	useParentPath     = "useParentPath"
+	useAbsolutePath   = "useAbsolutePath"
+	useSiblingPath    = "useSiblingPath"
+	useBasePath       = "useBasePath"
+	useEscapingTarget = "useEscapingTarget"
+	useSymlinkedBase  = "useSymlinkedBase"
+
+	expectError = "expectError"
+)
+
+const (
+	rawName     = "raw"
+	siblingName = "raw2"
+	fileName    = "file.txt"
+	linkName    = "link"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createCompressionPath(base string) (path string,
+	expect string) {
+	switch {
+	case s.Switches[useParentPath]:
+		return "../" + fileName, ""
+	case s.Switches[useSiblingPath]:
+		return "../" + siblingName + "/" + fileName, ""
+	case s.Switches[useAbsolutePath]:
+		// absolute entry is rebased into base
+		return "/etc/" + fileName, filepath.Join(base, "etc", fileName)
+	case s.Switches[useBasePath]:
+		return ".", base
+	default:
+		return "dir/" + fileName, filepath.Join(base, "dir", fileName)
+	}
+}
+
+// createRaw creates a raw directory holding a file and a symlink next to a
+// sibling directory sharing raw's name prefix.
+func (s *testScenario) createRaw(t *testing.T) (base string, link string) {
+	root := t.TempDir()
+	raw := filepath.Join(root, rawName)
+	sibling := filepath.Join(root, siblingName)
+
+	for _, dir := range []string{raw, sibling} {
+		err := os.MkdirAll(dir, 0755)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, fileName),
+				[]byte(dir),
+				0644,
+			)
+		}
+
+		if err != nil {
+			t.Fatalf("failed to create test directory: %s", err)
+		}
+	}
+
+	target := fileName
+	switch {
+	case s.Switches[useEscapingTarget]:
+		target = filepath.Join("..", siblingName, fileName)
+	case s.Switches[useAbsolutePath]:
+		target = filepath.Join(raw, fileName)
+	}
+
+	link = filepath.Join(raw, linkName)
+	err := os.Symlink(target, link)
+	if err != nil {
+		t.Fatalf("failed to create test symlink: %s", err)
+	}
+
+	base = raw
+	if s.Switches[useSymlinkedBase] {
+		base = filepath.Join(root, "alias")
+		err = os.Symlink(raw, base)
+		if err != nil {
+			t.Fatalf("failed to create test base symlink: %s", err)
+		}
+
+		link = filepath.Join(base, linkName)
+	}
+
+	return base, link
+}
+
+func (s *testScenario) assertPath(th *thelper.THelper,
+	out string, expect string, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	th.ExpectSameStrings("output", out, "expected output", expect)
+}
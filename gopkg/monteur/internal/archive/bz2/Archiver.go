@@ -0,0 +1,144 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bz2
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+)
+
+const (
+	EXTENSION = ".bz2"
+)
+
+// Archiver is for a plain bzip2 (`.bz2`) compressed single file.
+//
+// The uncompressed file is named after the Archive filename without its last
+// file extension (e.g. `tool.bz2` is `tool`) and is placed inside Raw.
+type Archiver struct {
+	// Archive is the filepath to the `.bz2` compressed file.
+	//
+	// The value is **STRICTLY** a filepath with filename and `.bz2` file
+	// extension.
+	Archive string
+
+	// Raw is the directory path housing the uncompressed file.
+	Raw string
+
+	// CreateDirectory decides on creating missing directory for Raw.
+	//
+	// Default is returning an error (`false`).
+	CreateDirectory bool
+
+	// Overwrite decides on overwriting existing Raw file.
+	//
+	// Default is returning an error (`false`).
+	Overwrite bool
+
+	// ReliefExtension decides to relax file extension checking.
+	//
+	// When set to `true`, Archiver shall not throw an error when checking
+	// Archive for strict `.bz2` file extension.
+	//
+	// Default is returning an error (`false`).
+	ReliefExtension bool
+
+	name string
+}
+
+// Sanitize initializes and check all input data are correct before executions.
+//
+// This function shall returns error if any data is not compliant.
+func (me *Archiver) Sanitize() (err error) {
+	var extension string
+
+	if !me.ReliefExtension {
+		extension = EXTENSION
+	}
+
+	me.Archive, err = archive.SanitizeArchive(me.Archive, extension)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	me.Raw, err = archive.SanitizeRaw(me.Raw)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	me.name = filepath.Base(me.Archive)
+	me.name = strings.TrimSuffix(me.name, filepath.Ext(me.name))
+	if me.name == "" || me.name == filepath.Base(me.Archive) {
+		return fmt.Errorf("%s: '%s'",
+			archive.ERROR_ARCHIVE_EXT_MISSING,
+			me.Archive,
+		)
+	}
+
+	return nil
+}
+
+// Compress is not supported since the Go standard library only offers a bzip2
+// decompressor.
+//
+// It always returns an error.
+func (me *Archiver) Compress() (err error) {
+	return fmt.Errorf("%s: '%s'",
+		archive.ERROR_COMPRESSION_UNSUPPORTED,
+		EXTENSION,
+	)
+}
+
+// Extract is to decompress a `.bz2` file into the Raw directory.
+//
+// It called Archiver.Sanitize() internally.
+func (me *Archiver) Extract() (err error) {
+	var f *os.File
+	var bufReader *bufio.Reader
+
+	err = me.Sanitize()
+	if err != nil {
+		return err
+	}
+
+	// open Archive for extractions
+	f, err = os.Open(me.Archive)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_READ,
+			me.Archive,
+		)
+	}
+	defer f.Close()
+
+	// add read buffer to reduce io call swarm
+	bufReader = bufio.NewReaderSize(f, int(3*archive.COPY_SIZE))
+
+	return archive.ExtractFile(bzip2.NewReader(bufReader), //nolint:wrapcheck
+		me.Raw,
+		me.name,
+		time.Time{},
+		me.CreateDirectory,
+		me.Overwrite,
+	)
+}
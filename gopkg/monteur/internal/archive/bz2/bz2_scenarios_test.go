@@ -0,0 +1,75 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bz2
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should decompress the file when:
+1. the archive is created by the bzip2 CLI tool.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should return an error when:
+1. the archive is not a '.bz2' compressed file.
+`,
+			Switches: map[string]bool{
+				useCorruptedArchive: true,
+				expectError:         true,
+			},
+		}, {
+			UID:      3,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should return an error when:
+1. the destination holds a colliding file.
+2. overwrite is disabled.
+`,
+			Switches: map[string]bool{
+				useExistingFile: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      4,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should decompress the file when:
+1. the destination holds a colliding file.
+2. overwrite is enabled.
+`,
+			Switches: map[string]bool{
+				useExistingFile: true,
+				useOverwrite:    true,
+			},
+		}, {
+			UID:      5,
+			TestType: testCompress,
+			Description: `
+Archiver.Compress should return an error when:
+1. the format only supports extraction.
+`,
+			Switches: map[string]bool{
+				expectError: true,
+			},
+		},
+	}
+}
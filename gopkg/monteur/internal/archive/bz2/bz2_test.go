@@ -0,0 +1,109 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bz2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testExtract  = "testExtract"
+	testCompress = "testCompress"
+)
+
+const (
+	useCorruptedArchive = "useCorruptedArchive"
+	useExistingFile     = "useExistingFile"
+	useOverwrite        = "useOverwrite"
+
+	expectError = "expectError"
+)
+
+const (
+	fileName    = "tool"
+	fileContent = "monteur"
+)
+
+// testArchive holds the fileContent compressed by the bzip2 CLI tool.
+var testArchive = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x05, 0x72,
+	0x16, 0xea, 0x00, 0x00, 0x00, 0x81, 0x80, 0x02, 0x03, 0x96, 0x00, 0x20,
+	0x00, 0x22, 0x03, 0x65, 0x08, 0x60, 0x00, 0x8a, 0xf8, 0xbb, 0x92, 0x29,
+	0xc2, 0x84, 0x80, 0x2b, 0x90, 0xb7, 0x50,
+}
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createArchive(t *testing.T, dir string) string {
+	path := filepath.Join(dir, fileName+EXTENSION)
+
+	data := testArchive
+	if s.Switches[useCorruptedArchive] {
+		data = []byte(fileContent)
+	}
+
+	err := os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatalf("failed to create test archive: %s", err)
+	}
+
+	return path
+}
+
+// createDestination creates the extraction directory, optionally holding a
+// file colliding with the decompressed file.
+func (s *testScenario) createDestination(t *testing.T, dir string) string {
+	raw := filepath.Join(dir, "raw")
+
+	err := os.MkdirAll(raw, 0755)
+	if err == nil && s.Switches[useExistingFile] {
+		err = os.WriteFile(filepath.Join(raw, fileName),
+			[]byte("existing"),
+			0644,
+		)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test destination: %s", err)
+	}
+
+	return raw
+}
+
+func (s *testScenario) assertRaw(th *thelper.THelper, raw string, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	if s.Switches[expectError] {
+		return
+	}
+
+	data, _ := os.ReadFile(filepath.Join(raw, fileName))
+	th.ExpectSameStrings("file content", string(data),
+		"expected file content", fileContent,
+	)
+}
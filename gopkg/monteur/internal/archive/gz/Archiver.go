@@ -0,0 +1,254 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gz
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+)
+
+const (
+	EXTENSION = ".gz"
+)
+
+const (
+	COMPRESSION_NONE             int = gzip.NoCompression
+	COMPRESSION_BEST_SPEED       int = gzip.BestSpeed
+	COMPRESSION_BEST_COMPRESSION int = gzip.BestCompression
+	COMPRESSION_DEFAULT          int = gzip.DefaultCompression
+	COMPRESSION_HUFFMAN_ONLY     int = gzip.HuffmanOnly
+)
+
+// Archiver is for a plain gunzip (`.gz`) compressed single file.
+//
+// The uncompressed file is named after the Archive filename without its last
+// file extension (e.g. `tool.gz` is `tool`) and is placed inside Raw.
+type Archiver struct {
+	// Archive is the filepath to the `.gz` compressed file.
+	//
+	// The value is **STRICTLY** a filepath with filename and `.gz` file
+	// extension.
+	Archive string
+
+	// Raw is the directory path housing the uncompressed file.
+	Raw string
+
+	// Compression is the level of compression for gunzip (`.gz`).
+	//
+	// It is only used in `Compress()`.
+	//
+	// The value can be 1 (Best Speed) upto 9 (Best Compression).
+	// Compression constants are made available for references.
+	//
+	// Default is COMPRESSION_NONE (`0`).
+	Compression int
+
+	// CreateDirectory decides on creating missing directory for Raw.
+	//
+	// It is only used in `Extract()`.
+	//
+	// Default is returning an error (`false`).
+	CreateDirectory bool
+
+	// Overwrite decides on overwriting existing Archive or Raw file.
+	//
+	// Default is returning an error (`false`).
+	Overwrite bool
+
+	// ReliefExtension decides to relax file extension checking.
+	//
+	// When set to `true`, Archiver shall not throw an error when checking
+	// Archive for strict `.gz` file extension.
+	//
+	// Default is returning an error (`false`).
+	ReliefExtension bool
+
+	name string
+}
+
+// Sanitize initializes and check all input data are correct before executions.
+//
+// This function shall returns error if any data is not compliant.
+func (me *Archiver) Sanitize() (err error) {
+	var extension string
+
+	if !me.ReliefExtension {
+		extension = EXTENSION
+	}
+
+	me.Archive, err = archive.SanitizeArchive(me.Archive, extension)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	me.Raw, err = archive.SanitizeRaw(me.Raw)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	me.name = filepath.Base(me.Archive)
+	me.name = strings.TrimSuffix(me.name, filepath.Ext(me.name))
+	if me.name == "" || me.name == filepath.Base(me.Archive) {
+		return fmt.Errorf("%s: '%s'",
+			archive.ERROR_ARCHIVE_EXT_MISSING,
+			me.Archive,
+		)
+	}
+
+	return nil
+}
+
+// Compress is to compress the named file inside Raw into Archive file.
+//
+// It called Archiver.Sanitize() internally.
+func (me *Archiver) Compress() (err error) {
+	var f, in *os.File
+	var info os.FileInfo
+	var gz *gzip.Writer
+	var path string
+
+	err = me.Sanitize()
+	if err != nil {
+		return err
+	}
+
+	if me.Compression < COMPRESSION_HUFFMAN_ONLY ||
+		me.Compression > COMPRESSION_BEST_COMPRESSION {
+		return fmt.Errorf("%s: '%d'",
+			archive.ERROR_COMPRESSION_INVALID,
+			me.Compression,
+		)
+	}
+
+	// open the file to compress
+	path = filepath.Join(me.Raw, me.name)
+	in, err = os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_FILE_READ,
+			path,
+			err,
+		)
+	}
+	defer in.Close()
+
+	info, err = in.Stat()
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_FILE_INFO_READ,
+			path,
+			err,
+		)
+	}
+
+	// check for overwrite
+	err = archive.Overwrite(me.Archive, me.Overwrite)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// begin compressing
+	f, err = os.Create(me.Archive)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_CREATE,
+			me.Archive,
+		)
+	}
+	defer f.Close()
+
+	gz, err = gzip.NewWriterLevel(f, me.Compression)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_CREATE,
+			err,
+		)
+	}
+
+	gz.Name = me.name
+	gz.ModTime = info.ModTime()
+
+	_, err = io.Copy(gz, in)
+	if err != nil {
+		_ = gz.Close()
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_FILE_WRITE,
+			path,
+			err,
+		)
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_CREATE,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Extract is to decompress a `.gz` file into the Raw directory.
+//
+// It called Archiver.Sanitize() internally.
+func (me *Archiver) Extract() (err error) {
+	var f *os.File
+	var gz *gzip.Reader
+	var bufReader *bufio.Reader
+
+	err = me.Sanitize()
+	if err != nil {
+		return err
+	}
+
+	// open Archive for extractions
+	f, err = os.Open(me.Archive)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_READ,
+			me.Archive,
+		)
+	}
+	defer f.Close()
+
+	// add read buffer to reduce io call swarm
+	bufReader = bufio.NewReaderSize(f, int(3*archive.COPY_SIZE))
+
+	gz, err = gzip.NewReader(bufReader)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_READ,
+			err,
+		)
+	}
+	defer gz.Close()
+
+	return archive.ExtractFile(gz, //nolint:wrapcheck
+		me.Raw,
+		me.name,
+		gz.ModTime,
+		me.CreateDirectory,
+		me.Overwrite,
+	)
+}
@@ -0,0 +1,506 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarball
+
+import (
+	"archive/tar"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+)
+
+type compressor struct {
+	writer        *tar.Writer
+	following     map[string]bool
+	raw           string
+	followSymlink bool
+}
+
+type extractor struct {
+	reader    *tar.Reader
+	raw       string
+	overwrite bool
+}
+
+// Compress is to write the raw data directory as a tar stream into w.
+//
+// The caller is responsible to sanitize the raw pathing and to close the
+// compression stream behind w. The tar stream itself is closed before return.
+func Compress(w io.Writer, raw string, followSymlink bool) (err error) {
+	me := &compressor{
+		writer:        tar.NewWriter(w),
+		following:     map[string]bool{},
+		raw:           raw,
+		followSymlink: followSymlink,
+	}
+
+	// walk through Raw data directory and compress it
+	err = filepath.Walk(me.raw, me.compress)
+	if err != nil {
+		_ = me.writer.Close()
+		return err //nolint:wrapcheck
+	}
+
+	err = me.writer.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %s", archive.ERROR_ARCHIVE_CREATE, err)
+	}
+
+	return nil
+}
+
+// Extract is to restore a tar stream from r into the raw data directory.
+//
+// Every entry pathing and symlink target is checked to stay inside the raw
+// directory before any file is written.
+func Extract(r io.Reader, raw string, overwrite bool) (err error) {
+	me := &extractor{
+		reader:    tar.NewReader(r),
+		raw:       raw,
+		overwrite: overwrite,
+	}
+
+	return me.extract()
+}
+
+func (me *compressor) compress(path string, info os.FileInfo, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: (%s) %s",
+			archive.ERROR_FILE_READ,
+			path,
+			err,
+		)
+	}
+
+	return me.compressAs(path, path, info)
+}
+
+// compressAs archives the file at path under the pathing of name, which only
+// differs from path when a followed symlink is being archived.
+func (me *compressor) compressAs(name string,
+	path string, info os.FileInfo) error {
+	var mode os.FileMode
+
+	mode = info.Mode()
+	switch {
+	case mode.IsRegular():
+		return me.compressRegular(name, path, info)
+	case mode.IsDir():
+		return me.compressDir(name, path, info)
+	case mode&os.ModeSymlink != 0:
+		return me.compressSymlink(name, path, info)
+	default:
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_FILE_UNSUPPORTED,
+			path,
+		)
+	}
+}
+
+func (me *compressor) compressSymlink(name string,
+	path string, info os.FileInfo) (err error) {
+	var header *tar.Header
+	var targetInfo os.FileInfo
+	var target, link string
+
+	// ensure symlink is only targeting within data directory
+	target, targetInfo, err = archive.EvalSymlink(me.raw, path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// check follow decision and resolve recursively if set
+	if me.followSymlink {
+		return me.compressFollow(name, target, targetInfo)
+	}
+
+	// save the target relative to the symlink's directory
+	link, err = archive.RelPath(filepath.Dir(name), target)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// create header from info
+	header, err = tar.FileInfoHeader(info, link)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_HEADER_READ,
+			path,
+			err,
+		)
+	}
+
+	// configure pathing
+	header.Name, err = archive.RelPath(me.raw, name)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// write symlink
+	err = me.writer.WriteHeader(header)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_HEADER_WRITE,
+			path,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (me *compressor) compressFollow(name string,
+	target string, info os.FileInfo) (err error) {
+	if !info.IsDir() {
+		return me.compressAs(name, target, info)
+	}
+
+	// a directory already being followed means the symlink loops
+	if me.following[target] {
+		return fmt.Errorf("%s: %s", archive.ERROR_SYMLINK_LOOP, name)
+	}
+
+	me.following[target] = true
+	defer delete(me.following, target)
+
+	// archive the target directory content under the symlink's pathing
+	return filepath.Walk(target, //nolint:wrapcheck
+		func(path string, info os.FileInfo, err error) error {
+			var rel string
+
+			if err != nil {
+				return me.compress(path, info, err)
+			}
+
+			rel, err = filepath.Rel(target, path)
+			if err != nil {
+				return fmt.Errorf("%s (%s): %s",
+					archive.ERROR_PATH_REL_FAILED,
+					path,
+					err,
+				)
+			}
+
+			return me.compressAs(filepath.Join(name, rel), path, info)
+		},
+	)
+}
+
+func (me *compressor) compressDir(name string,
+	path string, info os.FileInfo) (err error) {
+	var header *tar.Header
+
+	// create header from info
+	header, err = tar.FileInfoHeader(info, info.Name())
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_HEADER_READ,
+			path,
+			err,
+		)
+	}
+
+	// configure pathing
+	header.Name, err = archive.RelPath(me.raw, name)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// write to archive
+	err = me.writer.WriteHeader(header)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_HEADER_WRITE,
+			path,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (me *compressor) compressRegular(name string,
+	path string, info os.FileInfo) (err error) {
+	var f *os.File
+	var header *tar.Header
+
+	// create header from info
+	header, err = tar.FileInfoHeader(info, info.Name())
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_HEADER_READ,
+			path,
+			err,
+		)
+	}
+
+	// configure pathing
+	header.Name, err = archive.RelPath(me.raw, name)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// open file to read
+	f, err = os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_FILE_WRITE,
+			path,
+			err,
+		)
+	}
+	defer f.Close()
+
+	// create writer from header
+	err = me.writer.WriteHeader(header)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_HEADER_WRITE,
+			path,
+			err,
+		)
+	}
+
+	// copy file data
+	_, err = io.Copy(me.writer, f)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_FILE_WRITE,
+			path,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (me *extractor) extract() (err error) {
+	var path string
+	var header *tar.Header
+
+	now := time.Now()
+
+	for {
+		header, err = me.reader.Next()
+		switch err {
+		case io.EOF:
+			return nil
+		case nil:
+		default:
+			return fmt.Errorf("%s: %s",
+				archive.ERROR_HEADER_READ,
+				err,
+			)
+		}
+
+		path, err = archive.SanitizeCompressionPath(me.raw, header.Name)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = me.extractDirectory(path, header, now)
+		case tar.TypeReg:
+			err = me.extractRegular(path, header, now)
+		case tar.TypeSymlink:
+			err = me.extractSymlink(path, header, now)
+		default:
+			err = fmt.Errorf("%s (%s): %s",
+				archive.ERROR_FILE_UNSUPPORTED,
+				path,
+				header.Name,
+			)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (me *extractor) extractSymlink(path string,
+	header *tar.Header, now time.Time) (err error) {
+	var mode os.FileMode
+	var aTime, mTime time.Time
+	var target string
+
+	// create housing directory
+	err = archive.MkdirAll(filepath.Dir(path), archive.PERMISSION_DIR, true)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// overwrite Raw if requested
+	err = archive.Overwrite(path, me.overwrite)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// resolve relative target from the symlink's directory
+	target = filepath.FromSlash(header.Linkname)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(header.Name), target)
+	}
+
+	// check target path is valid
+	target, err = archive.SanitizeCompressionPath(me.raw, target)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// keep symlink relative so the extracted directory is relocatable
+	target, err = archive.RelPath(filepath.Dir(path), target)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// create symlink
+	err = archive.CreateSymlink(target, path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// restore metadata
+	mTime = header.ModTime
+	if mTime.IsZero() {
+		mTime = now
+	}
+
+	aTime = header.AccessTime
+	if aTime.IsZero() {
+		aTime = now
+	}
+
+	mode = os.FileMode(header.Mode)
+	if mode < 0000 || mode > 0777 {
+		mode = archive.PERMISSION_FILE
+	}
+
+	return archive.RestoreMetadata(path, //nolint:wrapcheck
+		aTime,
+		mTime,
+		mode,
+		true,
+	)
+}
+
+func (me *extractor) extractDirectory(path string,
+	header *tar.Header, now time.Time) (err error) {
+	var mode os.FileMode
+	var aTime, mTime time.Time
+	// create target directory
+	err = archive.MkdirAll(path, archive.PERMISSION_DIR, true)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// restore metadata
+	mTime = header.ModTime
+	if mTime.IsZero() {
+		mTime = now
+	}
+
+	aTime = header.AccessTime
+	if aTime.IsZero() {
+		aTime = now
+	}
+
+	mode = os.FileMode(header.Mode)
+	if mode < 0000 || mode > 0777 {
+		mode = archive.PERMISSION_DIR
+	}
+
+	return archive.RestoreMetadata(path, //nolint:wrapcheck
+		aTime,
+		mTime,
+		mode,
+		false,
+	)
+}
+
+func (me *extractor) extractRegular(path string,
+	header *tar.Header, now time.Time) (err error) {
+	var mode os.FileMode
+	var aTime, mTime time.Time
+	var f *os.File
+	var bufWriter *bufio.Writer
+
+	// create housing directory
+	err = archive.MkdirAll(filepath.Dir(path), archive.PERMISSION_DIR, true)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// overwrite Raw if requested
+	err = archive.Overwrite(path, me.overwrite)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// create destination for write
+	f, err = os.OpenFile(path,
+		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
+		archive.PERMISSION_FILE,
+	)
+	if err != nil {
+		return fmt.Errorf("%s (%s): %s",
+			archive.ERROR_FILE_WRITE,
+			path,
+			err,
+		)
+	}
+
+	// add buffer for write
+	bufWriter = bufio.NewWriterSize(f, int(2*archive.COPY_SIZE))
+
+	// perform file extraction
+	err = archive.ExtractCopy(bufWriter, me.reader, archive.COPY_SIZE, path)
+	_ = bufWriter.Flush()
+	_ = f.Sync()
+	f.Close()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// restore metadata
+	mTime = header.ModTime
+	if mTime.IsZero() {
+		mTime = now
+	}
+
+	aTime = header.AccessTime
+	if aTime.IsZero() {
+		aTime = now
+	}
+
+	mode = os.FileMode(header.Mode)
+	if mode < 0000 || mode > 0777 {
+		mode = archive.PERMISSION_FILE
+	}
+
+	return archive.RestoreMetadata(path, //nolint:wrapcheck
+		aTime,
+		mTime,
+		mode,
+		false,
+	)
+}
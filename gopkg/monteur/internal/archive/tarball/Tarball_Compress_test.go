@@ -0,0 +1,52 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarball
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testCompress {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		source := s.createRaw(t)
+		raw := filepath.Join(t.TempDir(), "raw")
+		buf := &bytes.Buffer{}
+
+		// test
+		err := Compress(buf, source, s.Switches[useFollowSymlink])
+		if err == nil {
+			err = Extract(buf, raw, false)
+		}
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertRaw(th, raw, err)
+		s.log(th, map[string]interface{}{
+			"source": source,
+			"raw":    raw,
+			"error":  err,
+		})
+		th.Conclude()
+	}
+}
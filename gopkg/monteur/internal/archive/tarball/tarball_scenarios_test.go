@@ -0,0 +1,110 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarball
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testCompress,
+			Description: `
+Compress and Extract should round-trip the raw directory when:
+1. the raw directory holds a file and a relative symlink.
+2. symlink is not followed.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testCompress,
+			Description: `
+Compress and Extract should round-trip the raw directory when:
+1. the raw directory holds a file and a relative symlink.
+2. symlink is followed and saved as its target file.
+`,
+			Switches: map[string]bool{
+				useFollowSymlink: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testCompress,
+			Description: `
+Compress and Extract should round-trip the raw directory when:
+1. the raw directory holds a relative symlink to a directory.
+2. symlink is not followed.
+`,
+			Switches: map[string]bool{
+				useDirSymlink: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testCompress,
+			Description: `
+Compress and Extract should round-trip the raw directory when:
+1. the raw directory holds a relative symlink to a directory.
+2. symlink is followed and saved as its target directory.
+`,
+			Switches: map[string]bool{
+				useDirSymlink:    true,
+				useFollowSymlink: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testCompress,
+			Description: `
+Compress should return an error when:
+1. the raw directory holds a symlink looping into its own directory.
+2. symlink is followed.
+`,
+			Switches: map[string]bool{
+				useSymlinkLoop:   true,
+				useFollowSymlink: true,
+				expectError:      true,
+			},
+		}, {
+			UID:      6,
+			TestType: testExtract,
+			Description: `
+Extract should restore the tar stream when:
+1. the destination holds no colliding file.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      7,
+			TestType: testExtract,
+			Description: `
+Extract should return an error when:
+1. the destination holds a colliding file.
+2. overwrite is disabled.
+`,
+			Switches: map[string]bool{
+				useExistingFile: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      8,
+			TestType: testExtract,
+			Description: `
+Extract should restore the tar stream when:
+1. the destination holds a colliding file.
+2. overwrite is enabled.
+`,
+			Switches: map[string]bool{
+				useExistingFile: true,
+				useOverwrite:    true,
+			},
+		},
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarball
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testCompress = "testCompress"
+	testExtract  = "testExtract"
+)
+
+const (
+	useFollowSymlink = "useFollowSymlink"
+	useExistingFile  = "useExistingFile"
+	useOverwrite     = "useOverwrite"
+	useDirSymlink    = "useDirSymlink"
+	useSymlinkLoop   = "useSymlinkLoop"
+
+	expectError = "expectError"
+)
+
+const (
+	fileName    = "file.txt"
+	fileContent = "monteur"
+	linkTarget  = "../lib/" + fileName
+	dirTarget   = "../lib"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createRaw creates a raw directory holding a file and a symlink pointing to
+// it.
+func (s *testScenario) createRaw(t *testing.T) string {
+	raw := filepath.Join(t.TempDir(), "source")
+
+	err := os.MkdirAll(filepath.Join(raw, "lib"), 0755)
+	if err == nil {
+		err = os.MkdirAll(filepath.Join(raw, "bin"), 0755)
+	}
+
+	if err == nil {
+		err = os.WriteFile(filepath.Join(raw, "lib", fileName),
+			[]byte(fileContent),
+			0644,
+		)
+	}
+
+	target := linkTarget
+	if s.Switches[useDirSymlink] {
+		target = dirTarget
+	}
+
+	if err == nil {
+		err = os.Symlink(filepath.FromSlash(target),
+			filepath.Join(raw, "bin", "link"),
+		)
+	}
+
+	if err == nil && s.Switches[useSymlinkLoop] {
+		err = os.Symlink("..", filepath.Join(raw, "lib", "loop"))
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test raw directory: %s", err)
+	}
+
+	return raw
+}
+
+func (s *testScenario) createStream(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+
+	err := Compress(buf, s.createRaw(t), false)
+	if err != nil {
+		t.Fatalf("failed to create test tar stream: %s", err)
+	}
+
+	return buf
+}
+
+// createDestination creates the extraction directory, optionally holding a
+// file colliding with the tar stream.
+func (s *testScenario) createDestination(t *testing.T) string {
+	raw := filepath.Join(t.TempDir(), "raw")
+
+	err := os.MkdirAll(filepath.Join(raw, "lib"), 0755)
+	if err == nil && s.Switches[useExistingFile] {
+		err = os.WriteFile(filepath.Join(raw, "lib", fileName),
+			[]byte("existing"),
+			0644,
+		)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test destination: %s", err)
+	}
+
+	return raw
+}
+
+func (s *testScenario) assertRaw(th *thelper.THelper, raw string, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	if s.Switches[expectError] {
+		return
+	}
+
+	data, _ := os.ReadFile(filepath.Join(raw, "lib", fileName))
+	th.ExpectSameStrings("file content", string(data),
+		"expected file content", fileContent,
+	)
+
+	link := filepath.Join(raw, "bin", "link")
+	target := filepath.FromSlash(linkTarget)
+	path := link
+	if s.Switches[useDirSymlink] {
+		target = filepath.FromSlash(dirTarget)
+		path = filepath.Join(link, fileName)
+	}
+
+	data, _ = os.ReadFile(path)
+	th.ExpectSameStrings("symlink content", string(data),
+		"expected symlink content", fileContent,
+	)
+
+	info, _ := os.Lstat(link)
+	th.ExpectSameBool("is symlink",
+		info != nil && info.Mode()&os.ModeSymlink != 0,
+		"expect symlink",
+		!s.Switches[useFollowSymlink],
+	)
+
+	if s.Switches[useFollowSymlink] {
+		return
+	}
+
+	out, _ := os.Readlink(link)
+	th.ExpectSameStrings("symlink target", out,
+		"expected symlink target", target,
+	)
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarbz2
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"fmt"
+	"io"
+	"os"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/tarball"
+)
+
+const (
+	EXTENSION = ".tar.bz2"
+)
+
+type Archiver struct {
+	// Archive is the filepath to the `.tar.bz2` archive file.
+	//
+	// The value is **STRICTLY** a filepath with filename and `.tar.bz2`
+	// file extension.
+	Archive string
+
+	// Raw is the directory path for the uncompressed data directory.
+	//
+	// The value **MUST** be an empty or mergeable directory for
+	// decompression.
+	Raw string
+
+	// CleanSlate wipes the entire Raw directory before extraction.
+	//
+	// It is only used in `Extract()`.
+	//
+	// Default is merge with existing (`false`).
+	CleanSlate bool
+
+	// CreateDirectory decides on creating missing directory for Raw.
+	//
+	// It is only used in `Extract()`.
+	//
+	// Default is returning an error (`false`).
+	CreateDirectory bool
+
+	// Overwrite decides on overwriting existing Raw files.
+	//
+	// Default is returning an error (`false`).
+	Overwrite bool
+
+	// ReliefExtension decides to relax file extension checking.
+	//
+	// When set to `true`, Archiver shall not throw an error when checking
+	// Archive for strict `.tar.bz2` file extension.
+	//
+	// Default is returning an error (`false`).
+	ReliefExtension bool
+}
+
+// Sanitize initializes and check all input data are correct before executions.
+//
+// This function shall returns error if any data is not compliant.
+func (me *Archiver) Sanitize() (err error) {
+	var extension string
+
+	if !me.ReliefExtension {
+		extension = EXTENSION
+	}
+
+	me.Archive, err = archive.SanitizeArchive(me.Archive, extension)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	me.Raw, err = archive.SanitizeRaw(me.Raw)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// Compress is not supported since the Go standard library only offers a bzip2 decompressor.
+//
+// It always returns an error.
+func (me *Archiver) Compress() (err error) {
+	return fmt.Errorf("%s: '%s'",
+		archive.ERROR_COMPRESSION_UNSUPPORTED,
+		EXTENSION,
+	)
+}
+
+// Extract is to extract a `.tar.bz2` archived file into the data directory.
+//
+// It called Archiver.Sanitize() internally.
+func (me *Archiver) Extract() (err error) {
+	var f *os.File
+	var x io.Reader
+	var bufReader *bufio.Reader
+
+	err = me.Sanitize()
+	if err != nil {
+		return err
+	}
+
+	if me.CleanSlate {
+		_ = os.RemoveAll(me.Raw)
+	}
+
+	// create directory if requested
+	err = archive.MkdirAll(me.Raw,
+		archive.PERMISSION_DIR,
+		me.CleanSlate || me.CreateDirectory,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// open Archive for extractions
+	f, err = os.Open(me.Archive)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_READ,
+			me.Archive,
+		)
+	}
+	defer f.Close()
+
+	// add read buffer to reduce io call swarm
+	bufReader = bufio.NewReaderSize(f, int(3*archive.COPY_SIZE))
+
+	x = bzip2.NewReader(bufReader)
+
+	return tarball.Extract(x, me.Raw, me.Overwrite) //nolint:wrapcheck
+}
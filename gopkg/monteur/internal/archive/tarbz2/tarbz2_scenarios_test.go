@@ -0,0 +1,63 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarbz2
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should extract the archive when:
+1. the archive is created by the format's CLI tool.
+2. the archive holds a file and a relative symlink.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should return an error when:
+1. the archive is not a '.tar.bz2' compressed file.
+`,
+			Switches: map[string]bool{
+				useCorruptedArchive: true,
+				expectError:         true,
+			},
+		}, {
+			UID:      3,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should return an error when:
+1. the archive has no '.tar.bz2' file extension.
+`,
+			Switches: map[string]bool{
+				useBadExtension: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      4,
+			TestType: testCompress,
+			Description: `
+Archiver.Compress should return an error when:
+1. the format only supports extraction.
+`,
+			Switches: map[string]bool{
+				expectError: true,
+			},
+		},
+	}
+}
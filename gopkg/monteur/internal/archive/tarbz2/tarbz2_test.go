@@ -0,0 +1,116 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarbz2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testExtract  = "testExtract"
+	testCompress = "testCompress"
+)
+
+const (
+	useCorruptedArchive = "useCorruptedArchive"
+	useBadExtension     = "useBadExtension"
+
+	expectError = "expectError"
+)
+
+const (
+	fileName    = "file.txt"
+	fileContent = "monteur"
+	linkTarget  = "../lib/" + fileName
+)
+
+// testArchive holds lib/file.txt and bin/link symlinked to it, created by the
+// CLI tool of the format.
+var testArchive = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x69, 0x1b,
+	0x68, 0xc9, 0x00, 0x00, 0xd4, 0xfb, 0x80, 0xc9, 0x80, 0x08, 0x00, 0x40,
+	0x01, 0xff, 0x80, 0x24, 0x44, 0x73, 0x2f, 0x9e, 0x40, 0x05, 0x08, 0x30,
+	0x00, 0xac, 0xa1, 0x24, 0x93, 0x43, 0xd5, 0x3d, 0x46, 0x86, 0x80, 0xd0,
+	0x31, 0xa9, 0xa6, 0xd4, 0x30, 0x69, 0xa3, 0x4d, 0x30, 0x98, 0x99, 0x30,
+	0x10, 0x34, 0xc0, 0xaa, 0x82, 0x98, 0x86, 0x80, 0xf5, 0x00, 0xc8, 0x00,
+	0xf5, 0x29, 0xf4, 0xf7, 0x23, 0x89, 0xbb, 0x6a, 0xb0, 0x9b, 0x21, 0x28,
+	0x3a, 0x42, 0x46, 0xfb, 0xf9, 0x30, 0xe7, 0x3b, 0x80, 0x90, 0x70, 0x2a,
+	0x68, 0x03, 0x90, 0x82, 0x24, 0x23, 0x98, 0xbc, 0x91, 0x4a, 0x62, 0xc7,
+	0xcc, 0x53, 0x95, 0xd9, 0x48, 0x35, 0x11, 0x07, 0x3c, 0x18, 0x5e, 0x14,
+	0xd1, 0x6b, 0x5c, 0x5b, 0x6c, 0x26, 0x4a, 0x86, 0x4b, 0xee, 0x95, 0xe7,
+	0x49, 0xd9, 0x12, 0x71, 0x2c, 0x5a, 0x9a, 0xe6, 0x4b, 0x06, 0x07, 0x72,
+	0x38, 0x47, 0x60, 0x56, 0x45, 0x90, 0x60, 0x18, 0xaa, 0x52, 0x64, 0x7d,
+	0x10, 0x53, 0x3b, 0x90, 0xec, 0xe6, 0x36, 0x3b, 0x35, 0x4b, 0x4d, 0x3f,
+	0x7d, 0x22, 0x55, 0x53, 0x3f, 0x77, 0x8a, 0xc2, 0xb4, 0x6f, 0x20, 0x7f,
+	0x17, 0x72, 0x45, 0x38, 0x50, 0x90, 0x69, 0x1b, 0x68, 0xc9,
+}
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createArchive(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "archive"+EXTENSION)
+	if s.Switches[useBadExtension] {
+		path = filepath.Join(dir, "archive.tar")
+	}
+
+	data := testArchive
+	if s.Switches[useCorruptedArchive] {
+		data = []byte(fileContent)
+	}
+
+	err := os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatalf("failed to create test archive: %s", err)
+	}
+
+	return path
+}
+
+func (s *testScenario) assertRaw(th *thelper.THelper, raw string, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	if s.Switches[expectError] {
+		return
+	}
+
+	data, _ := os.ReadFile(filepath.Join(raw, "lib", fileName))
+	th.ExpectSameStrings("file content", string(data),
+		"expected file content", fileContent,
+	)
+
+	link := filepath.Join(raw, "bin", "link")
+	out, _ := os.Readlink(link)
+	th.ExpectSameStrings("symlink target", out,
+		"expected symlink target", filepath.FromSlash(linkTarget),
+	)
+
+	data, _ = os.ReadFile(link)
+	th.ExpectSameStrings("symlink content", string(data),
+		"expected symlink content", fileContent,
+	)
+}
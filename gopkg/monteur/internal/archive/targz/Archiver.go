@@ -16,16 +16,14 @@
 package targz
 
 import (
-	"archive/tar"
 	"bufio"
 	"compress/gzip"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
-	"time"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/tarball"
 )
 
 const (
@@ -43,9 +41,6 @@ const (
 )
 
 type Archiver struct {
-	reader *tar.Reader
-	writer *tar.Writer
-
 	// Archive is the filepath to the `.tar.gz` archive file.
 	//
 	// The value is **STRICTLY** a filepath with filename and `.tar.gz`
@@ -194,169 +189,7 @@ func (me *Archiver) Compress() (err error) {
 	}
 	defer gz.Close()
 
-	me.writer = tar.NewWriter(gz)
-	defer me.writer.Close()
-
-	// walk through Raw data directory and compress it
-	return filepath.Walk(me.Raw, me.compress)
-}
-
-func (me *Archiver) compress(path string, info os.FileInfo, err error) error {
-	var mode os.FileMode
-
-	if err != nil {
-		return fmt.Errorf("%s: (%s) %s",
-			archive.ERROR_FILE_READ,
-			path,
-			err,
-		)
-	}
-
-	mode = info.Mode()
-	switch {
-	case mode.IsRegular():
-		return me.compressRegular(path, info)
-	case mode.IsDir():
-		return me.compressDir(path, info)
-	case mode&os.ModeSymlink != 0:
-		return me.compressSymlink(path, info)
-	default:
-		return fmt.Errorf("%s: %s",
-			archive.ERROR_FILE_UNSUPPORTED,
-			path,
-		)
-	}
-}
-
-func (me *Archiver) compressSymlink(path string, info os.FileInfo) (err error) {
-	var header *tar.Header
-	var targetInfo os.FileInfo
-	var target string
-
-	// ensure symlink is only targeting within data directory
-	target, targetInfo, err = archive.EvalSymlink(me.Raw, path)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// check follow decision and resolve recursively if set
-	if me.FollowSymlink {
-		return me.compress(target, targetInfo, nil)
-	}
-
-	// create header from info
-	header, err = tar.FileInfoHeader(info, info.Name())
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_HEADER_READ,
-			path,
-			err,
-		)
-	}
-
-	// configure pathing
-	header.Name, err = archive.RelPath(me.Raw, path)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// write symlink
-	err = me.writer.WriteHeader(header)
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_HEADER_WRITE,
-			path,
-			err,
-		)
-	}
-
-	return nil
-}
-
-func (me *Archiver) compressDir(path string, info os.FileInfo) (err error) {
-	var header *tar.Header
-
-	// create header from info
-	header, err = tar.FileInfoHeader(info, info.Name())
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_HEADER_READ,
-			path,
-			err,
-		)
-	}
-
-	// configure pathing
-	header.Name, err = archive.RelPath(me.Raw, path)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// write to archive
-	err = me.writer.WriteHeader(header)
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_HEADER_WRITE,
-			path,
-			err,
-		)
-	}
-
-	return nil
-}
-
-func (me *Archiver) compressRegular(path string, info os.FileInfo) (err error) {
-	var f *os.File
-	var header *tar.Header
-
-	// create header from info
-	header, err = tar.FileInfoHeader(info, info.Name())
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_HEADER_READ,
-			path,
-			err,
-		)
-	}
-
-	// configure pathing
-	header.Name, err = archive.RelPath(me.Raw, path)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// open file to read
-	f, err = os.Open(path)
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_FILE_WRITE,
-			path,
-			err,
-		)
-	}
-	defer f.Close()
-
-	// create writer from header
-	err = me.writer.WriteHeader(header)
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_HEADER_WRITE,
-			path,
-			err,
-		)
-	}
-
-	// copy file data
-	_, err = io.Copy(me.writer, f)
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_FILE_WRITE,
-			path,
-			err,
-		)
-	}
-
-	return nil
+	return tarball.Compress(gz, me.Raw, me.FollowSymlink) //nolint:wrapcheck
 }
 
 // Extract is to extract a `.tar.gz` archived file into the data directory.
@@ -407,208 +240,5 @@ func (me *Archiver) Extract() (err error) {
 	}
 	defer gz.Close()
 
-	me.reader = tar.NewReader(gz)
-
-	return me.extract()
-}
-
-func (me *Archiver) extract() (err error) {
-	var path string
-	var header *tar.Header
-
-	now := time.Now()
-
-	for {
-		header, err = me.reader.Next()
-		switch err {
-		case io.EOF:
-			return nil
-		case nil:
-		default:
-			return fmt.Errorf("%s: %s",
-				archive.ERROR_HEADER_READ,
-				err,
-			)
-		}
-
-		path, err = archive.SanitizeCompressionPath(me.Raw, header.Name)
-		if err != nil {
-			return err //nolint:wrapcheck
-		}
-
-		switch header.Typeflag {
-		case tar.TypeDir:
-			err = me.extractDirectory(path, header, now)
-		case tar.TypeReg:
-			err = me.extractRegular(path, header, now)
-		case tar.TypeSymlink:
-			err = me.extractSymlink(path, header, now)
-		default:
-			err = fmt.Errorf("%s (%s): %s",
-				archive.ERROR_FILE_UNSUPPORTED,
-				path,
-				header.Name,
-			)
-		}
-
-		if err != nil {
-			return err
-		}
-	}
-}
-
-func (me *Archiver) extractSymlink(path string,
-	header *tar.Header, now time.Time) (err error) {
-	var mode os.FileMode
-	var aTime, mTime time.Time
-	var target string
-
-	// create housing directory
-	err = archive.MkdirAll(filepath.Dir(path), archive.PERMISSION_DIR, true)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// overwrite Raw if requested
-	err = archive.Overwrite(path, me.Overwrite)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// check target path is valid
-	target, err = archive.SanitizeCompressionPath(me.Raw, header.Linkname)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// create symlink
-	err = archive.CreateSymlink(target, path)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// restore metadata
-	mTime = header.ModTime
-	if mTime.IsZero() {
-		mTime = now
-	}
-
-	aTime = header.AccessTime
-	if aTime.IsZero() {
-		aTime = now
-	}
-
-	mode = os.FileMode(header.Mode)
-	if mode < 0000 || mode > 0777 {
-		mode = archive.PERMISSION_FILE
-	}
-
-	return archive.RestoreMetadata(path, //nolint:wrapcheck
-		aTime,
-		mTime,
-		mode,
-		true,
-	)
-}
-
-func (me *Archiver) extractDirectory(path string,
-	header *tar.Header, now time.Time) (err error) {
-	var mode os.FileMode
-	var aTime, mTime time.Time
-	// create target directory
-	err = archive.MkdirAll(path, archive.PERMISSION_DIR, true)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// restore metadata
-	mTime = header.ModTime
-	if mTime.IsZero() {
-		mTime = now
-	}
-
-	aTime = header.AccessTime
-	if aTime.IsZero() {
-		aTime = now
-	}
-
-	mode = os.FileMode(header.Mode)
-	if mode < 0000 || mode > 0777 {
-		mode = archive.PERMISSION_DIR
-	}
-
-	return archive.RestoreMetadata(path, //nolint:wrapcheck
-		aTime,
-		mTime,
-		mode,
-		false,
-	)
-}
-
-func (me *Archiver) extractRegular(path string,
-	header *tar.Header, now time.Time) (err error) {
-	var mode os.FileMode
-	var aTime, mTime time.Time
-	var f *os.File
-	var bufWriter *bufio.Writer
-
-	// create housing directory
-	err = archive.MkdirAll(filepath.Dir(path), archive.PERMISSION_DIR, true)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// overwrite Raw if requested
-	err = archive.Overwrite(path, me.Overwrite)
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// create destination for write
-	f, err = os.OpenFile(path,
-		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
-		archive.PERMISSION_FILE,
-	)
-	if err != nil {
-		return fmt.Errorf("%s (%s): %s",
-			archive.ERROR_FILE_WRITE,
-			path,
-			err,
-		)
-	}
-
-	// add buffer for write
-	bufWriter = bufio.NewWriterSize(f, int(2*archive.COPY_SIZE))
-
-	// perform file extraction
-	err = archive.ExtractCopy(bufWriter, me.reader, archive.COPY_SIZE, path)
-	_ = bufWriter.Flush()
-	_ = f.Sync()
-	f.Close()
-	if err != nil {
-		return err //nolint:wrapcheck
-	}
-
-	// restore metadata
-	mTime = header.ModTime
-	if mTime.IsZero() {
-		mTime = now
-	}
-
-	aTime = header.AccessTime
-	if aTime.IsZero() {
-		aTime = now
-	}
-
-	mode = os.FileMode(header.Mode)
-	if mode < 0000 || mode > 0777 {
-		mode = archive.PERMISSION_FILE
-	}
-
-	return archive.RestoreMetadata(path, //nolint:wrapcheck
-		aTime,
-		mTime,
-		mode,
-		false,
-	)
+	return tarball.Extract(gz, me.Raw, me.Overwrite) //nolint:wrapcheck
 }
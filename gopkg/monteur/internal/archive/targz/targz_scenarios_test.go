@@ -0,0 +1,112 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targz
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should extract the archive when:
+1. the archive only holds a relative file entry.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should reject the archive when:
+1. the archive holds a '../' entry leaving raw.
+`,
+			Switches: map[string]bool{
+				useParentEntry: true,
+				expectError:    true,
+			},
+		}, {
+			UID:      3,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should extract the archive inside raw when:
+1. the archive holds an absolute entry.
+`,
+			Switches: map[string]bool{
+				useAbsoluteEntry: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should reject the archive when:
+1. the archive holds an entry into a sibling directory sharing raw's name
+   prefix.
+`,
+			Switches: map[string]bool{
+				useSiblingEntry: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      5,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should reject the archive when:
+1. the archive holds a symlink escaping raw.
+`,
+			Switches: map[string]bool{
+				useEscapingSymlink: true,
+				expectError:        true,
+			},
+		}, {
+			UID:      6,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should extract a relative symlink when:
+1. the archive holds a symlink relative to its own directory.
+`,
+			Switches: map[string]bool{
+				useNestedSymlink: true,
+			},
+		}, {
+			UID:      7,
+			TestType: testExtract,
+			Description: `
+Archiver.Extract should extract a relative symlink inside raw when:
+1. the archive holds a symlink with an absolute target.
+`,
+			Switches: map[string]bool{
+				useAbsoluteSymlink: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testCompress,
+			Description: `
+Archiver.Compress and Archiver.Extract should round-trip a symlink when:
+1. the raw directory holds a relative symlink to a file.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      9,
+			TestType: testCompress,
+			Description: `
+Archiver.Compress and Archiver.Extract should round-trip a symlink when:
+1. the raw directory holds a relative symlink to a directory.
+`,
+			Switches: map[string]bool{
+				useDirectorySymlink: true,
+			},
+		},
+	}
+}
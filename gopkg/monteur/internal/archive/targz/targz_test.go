@@ -0,0 +1,229 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targz
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testExtract  = "testExtract"
+	testCompress = "testCompress"
+)
+
+const (
+	useParentEntry      = "useParentEntry"
+	useAbsoluteEntry    = "useAbsoluteEntry"
+	useSiblingEntry     = "useSiblingEntry"
+	useEscapingSymlink  = "useEscapingSymlink"
+	useNestedSymlink    = "useNestedSymlink"
+	useAbsoluteSymlink  = "useAbsoluteSymlink"
+	useDirectorySymlink = "useDirectorySymlink"
+
+	expectError = "expectError"
+)
+
+const (
+	rawName     = "raw"
+	siblingName = "raw2"
+	fileName    = "file.txt"
+	fileContent = "monteur"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createEntries lists the crafted tar entries alongside the symlink (if any)
+// and its expected relative target after extraction.
+func (s *testScenario) createEntries() (headers []*tar.Header,
+	link string, target string) {
+	file := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     "lib/" + fileName,
+		Mode:     0644,
+		Size:     int64(len(fileContent)),
+	}
+	headers = []*tar.Header{file}
+
+	switch {
+	case s.Switches[useParentEntry]:
+		file.Name = "../" + fileName
+	case s.Switches[useSiblingEntry]:
+		file.Name = "../" + siblingName + "/" + fileName
+	case s.Switches[useAbsoluteEntry]:
+		file.Name = "/lib/" + fileName
+	case s.Switches[useEscapingSymlink]:
+		link = "bin/link"
+		headers = append(headers, &tar.Header{
+			Typeflag: tar.TypeSymlink,
+			Name:     link,
+			Linkname: "../../" + siblingName + "/" + fileName,
+			Mode:     0777,
+		})
+	case s.Switches[useNestedSymlink]:
+		link = "bin/link"
+		target = filepath.Join("..", "lib", fileName)
+		headers = append(headers, &tar.Header{
+			Typeflag: tar.TypeSymlink,
+			Name:     link,
+			Linkname: "../lib/" + fileName,
+			Mode:     0777,
+		})
+	case s.Switches[useAbsoluteSymlink]:
+		link = "bin/link"
+		target = filepath.Join("..", "lib", fileName)
+		headers = append(headers, &tar.Header{
+			Typeflag: tar.TypeSymlink,
+			Name:     link,
+			Linkname: "/lib/" + fileName,
+			Mode:     0777,
+		})
+	}
+
+	return headers, link, target
+}
+
+func (s *testScenario) createArchive(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "archive"+EXTENSION)
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create test archive: %s", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	headers, _, _ := s.createEntries()
+	for _, header := range headers {
+		err = tw.WriteHeader(header)
+		if err == nil && header.Typeflag == tar.TypeReg {
+			_, err = tw.Write([]byte(fileContent))
+		}
+
+		if err != nil {
+			t.Fatalf("failed to write test archive: %s", err)
+		}
+	}
+
+	err = tw.Close()
+	if err == nil {
+		err = gz.Close()
+	}
+
+	if err != nil {
+		t.Fatalf("failed to close test archive: %s", err)
+	}
+
+	return path
+}
+
+// createRaw creates a raw directory for Compress holding a file and a
+// symlink pointing to it.
+func (s *testScenario) createRaw(t *testing.T, dir string) string {
+	raw := filepath.Join(dir, "source")
+
+	err := os.MkdirAll(filepath.Join(raw, "lib"), 0755)
+	if err == nil {
+		err = os.MkdirAll(filepath.Join(raw, "bin"), 0755)
+	}
+
+	if err == nil {
+		err = os.WriteFile(filepath.Join(raw, "lib", fileName),
+			[]byte(fileContent),
+			0644,
+		)
+	}
+
+	if err == nil {
+		target := filepath.Join("..", "lib", fileName)
+		if s.Switches[useDirectorySymlink] {
+			target = filepath.Join("..", "lib")
+		}
+
+		err = os.Symlink(target, filepath.Join(raw, "bin", "link"))
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test raw directory: %s", err)
+	}
+
+	return raw
+}
+
+func (s *testScenario) assertExtract(th *thelper.THelper,
+	dir string, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+
+	// nothing shall ever be written outside of raw
+	for _, name := range []string{fileName, siblingName} {
+		_, statErr := os.Lstat(filepath.Join(dir, name))
+		th.ExpectSameBool("outside raw is written",
+			statErr == nil,
+			"expect outside raw is written",
+			false,
+		)
+	}
+
+	if s.Switches[expectError] {
+		return
+	}
+
+	raw := filepath.Join(dir, rawName)
+	data, _ := os.ReadFile(filepath.Join(raw, "lib", fileName))
+	th.ExpectSameStrings("file content", string(data),
+		"expected file content", fileContent,
+	)
+
+	_, link, target := s.createEntries()
+	if link == "" {
+		return
+	}
+
+	s.assertSymlink(th, filepath.Join(raw, link), target)
+}
+
+func (s *testScenario) assertSymlink(th *thelper.THelper,
+	path string, target string) {
+	out, _ := os.Readlink(path)
+	th.ExpectSameStrings("symlink target", out,
+		"expected symlink target", target,
+	)
+
+	if s.Switches[useDirectorySymlink] {
+		path = filepath.Join(path, fileName)
+	}
+
+	data, _ := os.ReadFile(path)
+	th.ExpectSameStrings("symlink content", string(data),
+		"expected symlink content", fileContent,
+	)
+}
@@ -0,0 +1,219 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarxz
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ulikunitz/xz"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/tarball"
+)
+
+const (
+	EXTENSION = ".tar.xz"
+)
+
+type Archiver struct {
+	// Archive is the filepath to the `.tar.xz` archive file.
+	//
+	// The value is **STRICTLY** a filepath with filename and `.tar.xz`
+	// file extension.
+	Archive string
+
+	// Raw is the directory path for the uncompressed data directory.
+	//
+	// The value **MUST** be a directory holding the archive content for
+	// compression or an empty directory for decompression.
+	Raw string
+
+	// CleanSlate wipes the entire Raw directory before extraction.
+	//
+	// It is only used in `Extract()`.
+	//
+	// Default is merge with existing (`false`).
+	CleanSlate bool
+
+	// CreateDirectory decides on creating missing directory for Raw.
+	//
+	// It is only used in `Extract()`.
+	//
+	// Default is returning an error (`false`).
+	CreateDirectory bool
+
+	// Overwrite decides on overwriting existing Archive or Raw files.
+	//
+	// Default is returning an error (`false`).
+	Overwrite bool
+
+	// ReliefExtension decides to relax file extension checking.
+	//
+	// When set to `true`, Archiver shall not throw an error when checking
+	// Archive for strict `.tar.xz` file extension.
+	//
+	// Default is returning an error (`false`).
+	ReliefExtension bool
+
+	// FollowSymlink decides to resolve symlink and archive package file.
+	//
+	// Default (`false`) is to save a relative symlink to the `Raw`
+	// directory.
+	FollowSymlink bool
+}
+
+// Sanitize initializes and check all input data are correct before executions.
+//
+// This function shall returns error if any data is not compliant.
+func (me *Archiver) Sanitize() (err error) {
+	var extension string
+
+	if !me.ReliefExtension {
+		extension = EXTENSION
+	}
+
+	me.Archive, err = archive.SanitizeArchive(me.Archive, extension)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	me.Raw, err = archive.SanitizeRaw(me.Raw)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// Compress is to compress the Raw data directory into Archive file.
+//
+// It called Archiver.Sanitize() internally.
+func (me *Archiver) Compress() (err error) {
+	var f *os.File
+	var x *xz.Writer
+
+	err = me.Sanitize()
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(me.Raw)
+	if err != nil && os.IsNotExist(err) {
+		return fmt.Errorf(archive.ERROR_RAW_MISSING)
+	}
+
+	// check for overwrite
+	err = archive.Overwrite(me.Archive, me.Overwrite)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// create base directory when requested
+	err = archive.MkdirAll(filepath.Dir(me.Archive),
+		archive.PERMISSION_DIR,
+		true,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// begin archiving
+	f, err = os.Create(me.Archive)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_CREATE,
+			me.Archive,
+		)
+	}
+	defer f.Close()
+
+	x, err = xz.NewWriter(f)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_CREATE,
+			err,
+		)
+	}
+
+	err = tarball.Compress(x, me.Raw, me.FollowSymlink)
+	if err != nil {
+		_ = x.Close()
+		return err //nolint:wrapcheck
+	}
+
+	// xz only writes its index and footer when closed
+	err = x.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_CREATE,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Extract is to extract a `.tar.xz` archived file into the data directory.
+//
+// It called Archiver.Sanitize() internally.
+func (me *Archiver) Extract() (err error) {
+	var f *os.File
+	var x *xz.Reader
+	var bufReader *bufio.Reader
+
+	err = me.Sanitize()
+	if err != nil {
+		return err
+	}
+
+	if me.CleanSlate {
+		_ = os.RemoveAll(me.Raw)
+	}
+
+	// create directory if requested
+	err = archive.MkdirAll(me.Raw,
+		archive.PERMISSION_DIR,
+		me.CleanSlate || me.CreateDirectory,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// open Archive for extractions
+	f, err = os.Open(me.Archive)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_READ,
+			me.Archive,
+		)
+	}
+	defer f.Close()
+
+	// add read buffer to reduce io call swarm
+	bufReader = bufio.NewReaderSize(f, int(3*archive.COPY_SIZE))
+
+	x, err = xz.NewReader(bufReader)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			archive.ERROR_ARCHIVE_READ,
+			err,
+		)
+	}
+
+	return tarball.Extract(x, me.Raw, me.Overwrite) //nolint:wrapcheck
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tarxz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testExtract  = "testExtract"
+	testCompress = "testCompress"
+)
+
+const (
+	useCorruptedArchive = "useCorruptedArchive"
+	useBadExtension     = "useBadExtension"
+
+	expectError = "expectError"
+)
+
+const (
+	fileName    = "file.txt"
+	fileContent = "monteur"
+	linkTarget  = "../lib/" + fileName
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createRaw creates a raw directory holding a file and a symlink pointing to
+// it.
+func (s *testScenario) createRaw(t *testing.T, dir string) string {
+	raw := filepath.Join(dir, "source")
+
+	err := os.MkdirAll(filepath.Join(raw, "lib"), 0755)
+	if err == nil {
+		err = os.MkdirAll(filepath.Join(raw, "bin"), 0755)
+	}
+
+	if err == nil {
+		err = os.WriteFile(filepath.Join(raw, "lib", fileName),
+			[]byte(fileContent),
+			0644,
+		)
+	}
+
+	if err == nil {
+		err = os.Symlink(filepath.FromSlash(linkTarget),
+			filepath.Join(raw, "bin", "link"),
+		)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test raw directory: %s", err)
+	}
+
+	return raw
+}
+
+func (s *testScenario) createArchive(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "archive"+EXTENSION)
+	if s.Switches[useBadExtension] {
+		path = filepath.Join(dir, "archive.tar")
+	}
+
+	if !s.Switches[useCorruptedArchive] {
+		subject := &Archiver{
+			Archive:         path,
+			Raw:             s.createRaw(t, dir),
+			ReliefExtension: true,
+		}
+
+		err := subject.Compress()
+		if err != nil {
+			t.Fatalf("failed to create test archive: %s", err)
+		}
+
+		return path
+	}
+
+	err := os.WriteFile(path, []byte(fileContent), 0644)
+	if err != nil {
+		t.Fatalf("failed to create test archive: %s", err)
+	}
+
+	return path
+}
+
+func (s *testScenario) assertRaw(th *thelper.THelper, raw string, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	if s.Switches[expectError] {
+		return
+	}
+
+	data, _ := os.ReadFile(filepath.Join(raw, "lib", fileName))
+	th.ExpectSameStrings("file content", string(data),
+		"expected file content", fileContent,
+	)
+
+	link := filepath.Join(raw, "bin", "link")
+	out, _ := os.Readlink(link)
+	th.ExpectSameStrings("symlink target", out,
+		"expected symlink target", filepath.FromSlash(linkTarget),
+	)
+
+	data, _ = os.ReadFile(link)
+	th.ExpectSameStrings("symlink content", string(data),
+		"expected symlink content", fileContent,
+	)
+}
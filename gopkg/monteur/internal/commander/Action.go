@@ -17,6 +17,7 @@ package commander
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -57,6 +58,20 @@ type Action struct {
 	// This field is optional.
 	SaveVar interface{}
 
+	// Filter wraps the command output writers before capturing them.
+	//
+	// It is used to redact sensitive data from the command's STDOUT and
+	// STDERR as they are streamed. The returned writer is closed once the
+	// command ends. This field is optional.
+	Filter func(w io.Writer) io.WriteCloser
+
+	// ExitCode is the exit status of the last Run().
+	//
+	// It is the process' exit status for command types, `0` for other
+	// successful types, and `-1` for failures without one. The value shall
+	// be set automatically during Run().
+	ExitCode int
+
 	actionFx func(action *Action) (output interface{}, err error)
 
 	// Type is the action type ID.
@@ -202,7 +217,11 @@ func (action *Action) Run() (err error) {
 		}
 	}
 
+	action.ExitCode = 0
 	output, err := action.actionFx(action)
+	if err != nil && action.ExitCode == 0 {
+		action.ExitCode = -1
+	}
 
 	if action.Location != "" {
 		errPWD = os.Chdir(action.PWD)
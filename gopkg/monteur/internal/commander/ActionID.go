@@ -43,3 +43,37 @@ const (
 	ACTION_SCRIPT                 ActionID = "script"
 	ACTION_SCRIPT_QUIET           ActionID = "script-quiet"
 )
+
+// IsValid checks the given ActionID is a supported action type.
+func (id ActionID) IsValid() bool {
+	switch id {
+	case ACTION_PLACEHOLDER,
+		ACTION_CHMOD,
+		ACTION_CHMOD_QUIET,
+		ACTION_CHOWN,
+		ACTION_CHOWN_QUIET,
+		ACTION_COMMAND,
+		ACTION_COMMAND_QUIET,
+		ACTION_COPY,
+		ACTION_COPY_QUIET,
+		ACTION_CREATE_DIR,
+		ACTION_CREATE_PATH,
+		ACTION_DELETE,
+		ACTION_DELETE_RECURSIVE,
+		ACTION_DELETE_RECURSIVE_QUIET,
+		ACTION_DELETE_QUIET,
+		ACTION_IS_EXISTS,
+		ACTION_IS_EMPTY,
+		ACTION_IS_EQUAL,
+		ACTION_IS_NOT_EMPTY,
+		ACTION_IS_NOT_EQUAL,
+		ACTION_MOVE,
+		ACTION_MOVE_QUIET,
+		ACTION_SCRIPT,
+		ACTION_SCRIPT_QUIET:
+		return true
+	default:
+	}
+
+	return false
+}
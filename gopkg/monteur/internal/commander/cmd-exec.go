@@ -17,7 +17,10 @@ package commander
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"os/exec"
 )
 
 type ExecOutput struct {
@@ -38,7 +41,17 @@ func cmdExec(action *Action) (out interface{}, err error) {
 	t.Stderr = stderr
 	x := &ExecOutput{}
 
+	if action.Filter != nil {
+		t.Stdout = action.Filter(stdout)
+		t.Stderr = action.Filter(stderr)
+	}
+
 	err = t.Exec(action.Source, 0)
+	action.ExitCode = _exitCode(err)
+
+	// flush the filters' held back data
+	_closeFilter(t.Stdout)
+	_closeFilter(t.Stderr)
 
 	// process output
 	x.Stdout = stdout.Bytes()
@@ -50,6 +63,25 @@ func cmdExec(action *Action) (out interface{}, err error) {
 	return x, err
 }
 
+func _exitCode(err error) int {
+	var exitErr *exec.ExitError
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	default:
+		return -1
+	}
+}
+
+func _closeFilter(w io.Writer) {
+	if c, ok := w.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
 func cmdExecQuiet(action *Action) (out interface{}, err error) {
 	out, _ = cmdExec(action)
 	return out, nil
@@ -17,7 +17,10 @@ package conductor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 )
 
 const (
@@ -35,6 +38,9 @@ const (
 type Conductor struct {
 	ctx     context.Context
 	channel chan Message
+	encoder *json.Encoder
+	started map[string]time.Time
+	results map[string]*Result
 
 	stop func()
 
@@ -44,6 +50,23 @@ type Conductor struct {
 	// Runners are the list of Jobs to be executed in parallel
 	Runners map[string]Job
 
+	// Filter is the optional function to sanitize the Jobs' messages
+	//
+	// It is used for redacting sensitive data from the Jobs' status,
+	// output, and error messages before they are logged or returned.
+	Filter func(string) string
+
+	// Events is the optional Writer receiving the Jobs' lifecycle Events
+	//
+	// Each Event is written in JSON Lines format for machine consumption.
+	Events io.Writer
+
+	// Stage is the name tagged into every Event (e.g. `build`)
+	Stage string
+
+	// Display is the optional live renderer receiving the Jobs' Events
+	Display Display
+
 	hasInitialized bool
 }
 
@@ -59,6 +82,13 @@ func (me *Conductor) init() {
 
 	me.channel = make(chan Message, chLength*2)
 	me.ctx, me.stop = context.WithCancel(context.Background())
+	me.started = map[string]time.Time{}
+	me.results = map[string]*Result{}
+
+	if me.Events != nil {
+		me.encoder = json.NewEncoder(me.Events)
+		me.encoder.SetEscapeHTML(false)
+	}
 
 	me.hasInitialized = true
 }
@@ -74,8 +104,15 @@ func (me *Conductor) Run() (err error) {
 
 	me.init()
 
+	for _, program := range me.Runners {
+		me.emitTask(EVENT_QUEUED, program.Name(), "")
+	}
+
 	for _, program := range me.Runners {
 		me.logInfo("Starting Job '%s' in background...", program.Name())
+		me.started[program.Name()] = time.Now()
+		me.recordStart(program.Name())
+		me.emitTask(EVENT_STARTED, program.Name(), "")
 		go program.Run(me.ctx, me.channel)
 		me.logSuccess("➤ OK\n")
 	}
@@ -110,6 +147,14 @@ func (me *Conductor) Coordinate() (err error) {
 				return nil
 			}
 
+			if me.checkStep(msg) {
+				continue
+			}
+
+			if me.checkProgress(msg) {
+				continue
+			}
+
 			switch me.checkDone(msg) {
 			case jobDone:
 				continue
@@ -159,6 +204,8 @@ func (me *Conductor) checkOutput(msg Message) {
 		return
 	}
 
+	output = me.filter(output)
+
 	// log the output
 	if name != "" {
 		me.logOutput("Job '%s' Output ➤ %s", name, output)
@@ -193,6 +240,8 @@ func (me *Conductor) checkStatus(msg Message) {
 		return
 	}
 
+	status = me.filter(status)
+
 	// log the status
 	if name != "" {
 		me.logInfo("Job '%s' Status ➤ %s", name, status)
@@ -226,6 +275,13 @@ func (me *Conductor) checkError(msg Message) (err error) {
 		return nil
 	}
 
+	if me.Filter != nil {
+		err = fmt.Errorf("%s", me.Filter(err.Error()))
+	}
+
+	me.emitTask(EVENT_ERROR, name, err.Error())
+	me.recordEnd(name, RESULT_FAILED, err.Error())
+
 	// log the output before returning error
 	if name != "" {
 		me.logError("Job '%s' Error ➤ %s", name, err)
@@ -271,6 +327,8 @@ func (me *Conductor) checkDone(msg Message) (state uint) {
 	// a job is done
 	state = jobDone
 	delete(me.Runners, name)
+	me.emitTask(EVENT_DONE, name, "")
+	me.recordEnd(name, RESULT_PASSED, "")
 	me.logInfo("Job '%s' ➤ COMPLETED", name)
 
 	if len(me.Runners) == 0 {
@@ -280,6 +338,14 @@ func (me *Conductor) checkDone(msg Message) (state uint) {
 	return state
 }
 
+func (me *Conductor) filter(s string) string {
+	if me.Filter == nil {
+		return s
+	}
+
+	return me.Filter(s)
+}
+
 func (me *Conductor) logError(format string, a ...interface{}) {
 	if !loggerAvailable(me.Log) {
 		return
@@ -0,0 +1,156 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conductor
+
+import (
+	"time"
+)
+
+// Supported Event types in lifecycle order.
+const (
+	EVENT_QUEUED        = "queued"
+	EVENT_STARTED       = "started"
+	EVENT_STEP_STARTED  = "step-started"
+	EVENT_STEP_FINISHED = "step-finished"
+	EVENT_PROGRESS      = "progress"
+	EVENT_DONE          = "done"
+	EVENT_ERROR         = "error"
+)
+
+// Event is a single Job lifecycle entry of the machine-readable events stream.
+//
+// Each Event is written as a single line of JSON (JSON Lines). Duration is in
+// milliseconds and only available for `step-finished`, `done`, and `error`
+// events. Downloaded and Total are in bytes and only available for `progress`
+// events where Total is `0` when unknown.
+type Event struct {
+	Timestamp  string `json:"timestamp"`
+	Type       string `json:"event"`
+	Stage      string `json:"stage"`
+	Task       string `json:"task"`
+	Step       string `json:"step,omitempty"`
+	Index      int    `json:"index,omitempty"`
+	Duration   *int64 `json:"duration_ms,omitempty"`
+	Downloaded int64  `json:"downloaded,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+func (me *Conductor) emit(event *Event) {
+	if me.encoder == nil && me.Display == nil {
+		return
+	}
+
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	event.Stage = me.Stage
+	event.Message = me.filter(event.Message)
+
+	if me.encoder != nil {
+		_ = me.encoder.Encode(event)
+	}
+
+	if me.Display != nil {
+		me.Display.Render(*event)
+	}
+}
+
+func (me *Conductor) emitTask(eventType string, name string, message string) {
+	var duration int64
+
+	event := &Event{
+		Type:    eventType,
+		Task:    name,
+		Message: message,
+	}
+
+	start, ok := me.started[name]
+	if ok && eventType != EVENT_STARTED {
+		duration = time.Since(start).Milliseconds()
+		event.Duration = &duration
+	}
+
+	me.emit(event)
+}
+
+func (me *Conductor) checkStep(msg Message) (ok bool) {
+	var name, step string
+	var index int
+	var duration time.Duration
+	var rmsg interface{}
+
+	rmsg, ok = msg.Get(CHMSG_STEP)
+	if !ok {
+		return false
+	}
+	step, _ = rmsg.(string)
+
+	rmsg, _ = msg.Get(CHMSG_OWNER)
+	name, _ = rmsg.(string)
+
+	rmsg, _ = msg.Get(CHMSG_STEP_INDEX)
+	index, _ = rmsg.(int)
+
+	event := &Event{
+		Type:  EVENT_STEP_STARTED,
+		Task:  name,
+		Step:  step,
+		Index: index,
+	}
+
+	rmsg, ok = msg.Get(CHMSG_DURATION)
+	if ok {
+		duration, _ = rmsg.(time.Duration)
+		ms := duration.Milliseconds()
+		event.Type = EVENT_STEP_FINISHED
+		event.Duration = &ms
+	}
+
+	me.emit(event)
+	me.recordStep(name, msg, &StepResult{
+		Name:  step,
+		Index: index,
+	})
+
+	return true
+}
+
+func (me *Conductor) checkProgress(msg Message) (ok bool) {
+	var name string
+	var rmsg interface{}
+	var progress *Progress
+
+	rmsg, ok = msg.Get(CHMSG_PROGRESS)
+	if !ok {
+		return false
+	}
+
+	progress, ok = rmsg.(*Progress)
+	if !ok || progress == nil {
+		return true
+	}
+
+	rmsg, _ = msg.Get(CHMSG_OWNER)
+	name, _ = rmsg.(string)
+
+	me.emit(&Event{
+		Type:       EVENT_PROGRESS,
+		Task:       name,
+		Downloaded: progress.Downloaded,
+		Total:      progress.Total,
+	})
+
+	return true
+}
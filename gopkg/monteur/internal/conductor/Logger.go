@@ -28,6 +28,14 @@ type Logger interface {
 	IsHealthy() error
 }
 
+// Display is an object interface for Conductor to render the Jobs' lifecycle
+// Events live (e.g. an interactive terminal progress display).
+//
+// Render is called synchronously by Conductor so it shall not block.
+type Display interface {
+	Render(event Event)
+}
+
 func loggerAvailable(log Logger) (verdict bool) {
 	var err error
 
@@ -18,14 +18,22 @@ package conductor
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 const (
-	CHMSG_DONE   = "done"
-	CHMSG_ERROR  = "error"
-	CHMSG_OWNER  = "owner"
-	CHMSG_STATUS = "status"
-	CHMSG_OUTPUT = "output"
+	CHMSG_COMMAND    = "command"
+	CHMSG_DONE       = "done"
+	CHMSG_DURATION   = "duration"
+	CHMSG_ERROR      = "error"
+	CHMSG_EXIT_CODE  = "exit-code"
+	CHMSG_OWNER      = "owner"
+	CHMSG_PROGRESS   = "progress"
+	CHMSG_STATUS     = "status"
+	CHMSG_STEP       = "step"
+	CHMSG_STEP_INDEX = "step-index"
+	CHMSG_STEP_TYPE  = "step-type"
+	CHMSG_OUTPUT     = "output"
 )
 
 // Message is the interface for message payload used in Go channel tramissions.
@@ -140,3 +148,63 @@ func CreateDone(owner string) Message {
 
 	return m
 }
+
+// CreateStep is to create a Message reporting a task's step has started.
+//
+// The `index` is the 1-indexed step number of the task.
+func CreateStep(owner string, index int, name string) Message {
+	m := NewMessage()
+
+	m.Add(CHMSG_OWNER, owner)
+	m.Add(CHMSG_STEP, name)
+	m.Add(CHMSG_STEP_INDEX, index)
+
+	return m
+}
+
+// CreateStepDone is to create a Message reporting a task's step has finished.
+//
+// The `stepType` and `command` describe the step's executed instruction while
+// the `exitCode` is its exit status (`0` for success).
+func CreateStepDone(owner string,
+	index int,
+	name string,
+	duration time.Duration,
+	stepType string,
+	command string,
+	exitCode int) Message {
+	m := CreateStep(owner, index, name)
+
+	m.Add(CHMSG_DURATION, duration)
+	m.Add(CHMSG_STEP_TYPE, stepType)
+	m.Add(CHMSG_COMMAND, command)
+	m.Add(CHMSG_EXIT_CODE, exitCode)
+
+	return m
+}
+
+// Progress is the transfer progress of a Job (e.g. downloading its source).
+type Progress struct {
+	// Downloaded is the transferred size in bytes
+	Downloaded int64
+
+	// Total is the expected size in bytes where `0` means unknown
+	Total int64
+}
+
+// CreateProgress is to create a Message reporting a task's transfer progress.
+func CreateProgress(owner string, downloaded int64, total int64) Message {
+	m := NewMessage()
+
+	if total < 0 {
+		total = 0
+	}
+
+	m.Add(CHMSG_OWNER, owner)
+	m.Add(CHMSG_PROGRESS, &Progress{
+		Downloaded: downloaded,
+		Total:      total,
+	})
+
+	return m
+}
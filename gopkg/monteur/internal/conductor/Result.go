@@ -0,0 +1,131 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conductor
+
+import (
+	"sort"
+	"time"
+)
+
+// Supported Result statuses.
+const (
+	RESULT_CANCELLED = "cancelled"
+	RESULT_FAILED    = "failed"
+	RESULT_PASSED    = "passed"
+	RESULT_RUNNING   = "running"
+)
+
+// Result is the execution record of a Job collected by Conductor.
+type Result struct {
+	Started  time.Time
+	Name     string
+	Status   string
+	Error    string
+	Steps    []*StepResult
+	Duration time.Duration
+}
+
+// StepResult is the execution record of a single step inside a Job.
+//
+// Command, ExitCode, and Duration are only available once the step is
+// Finished.
+type StepResult struct {
+	Name     string
+	Type     string
+	Command  string
+	Index    int
+	ExitCode int
+	Duration time.Duration
+	Finished bool
+}
+
+// Results is to obtain all the Jobs' execution records sorted by name.
+//
+// Jobs that are still running when the orchestra was stopped are marked as
+// `cancelled`.
+func (me *Conductor) Results() (list []*Result) {
+	for _, result := range me.results {
+		if result.Status == RESULT_RUNNING {
+			result.Status = RESULT_CANCELLED
+			result.Duration = time.Since(result.Started)
+		}
+
+		list = append(list, result)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+func (me *Conductor) recordStart(name string) {
+	me.results[name] = &Result{
+		Name:    name,
+		Status:  RESULT_RUNNING,
+		Started: me.started[name],
+	}
+}
+
+func (me *Conductor) recordEnd(name string, status string, message string) {
+	result, ok := me.results[name]
+	if !ok {
+		return
+	}
+
+	result.Status = status
+	result.Error = message
+	result.Duration = time.Since(result.Started)
+}
+
+func (me *Conductor) recordStep(name string, msg Message, step *StepResult) {
+	var rmsg interface{}
+	var ok bool
+
+	result, ok := me.results[name]
+	if !ok {
+		return
+	}
+
+	rmsg, ok = msg.Get(CHMSG_DURATION)
+	if !ok {
+		result.Steps = append(result.Steps, step)
+		return
+	}
+
+	step.Duration, _ = rmsg.(time.Duration)
+	step.Finished = true
+
+	rmsg, _ = msg.Get(CHMSG_STEP_TYPE)
+	step.Type, _ = rmsg.(string)
+
+	rmsg, _ = msg.Get(CHMSG_COMMAND)
+	step.Command, _ = rmsg.(string)
+	step.Command = me.filter(step.Command)
+
+	rmsg, _ = msg.Get(CHMSG_EXIT_CODE)
+	step.ExitCode, _ = rmsg.(int)
+
+	for i, v := range result.Steps {
+		if v.Index == step.Index {
+			result.Steps[i] = step
+			return
+		}
+	}
+
+	result.Steps = append(result.Steps, step)
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conductor
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testEvents,
+			Description: `
+Conductor should emit the queued, started and done events when:
+1. the Job completes without steps.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testEvents,
+			Description: `
+Conductor should emit the step events with index, step and duration when:
+1. the Job reports a started and a finished step before completing.
+`,
+			Switches: map[string]bool{
+				useSteps: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testEvents,
+			Description: `
+Conductor should emit the progress event with downloaded and total when:
+1. the Job reports its transfer progress with a known total.
+`,
+			Switches: map[string]bool{
+				useProgress: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testEvents,
+			Description: `
+Conductor should emit the progress event without total when:
+1. the Job reports its transfer progress with an unknown total.
+`,
+			Switches: map[string]bool{
+				useUnknownTotal: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testEvents,
+			Description: `
+Conductor should emit the error event with the redacted message when:
+1. the Job fails with an error containing a secret.
+`,
+			Switches: map[string]bool{
+				useSteps: true,
+				useError: true,
+			},
+		},
+	}
+}
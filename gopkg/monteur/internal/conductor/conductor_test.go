@@ -0,0 +1,268 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conductor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testEvents = "testEvents"
+)
+
+const (
+	useSteps        = "useSteps"
+	useProgress     = "useProgress"
+	useUnknownTotal = "useUnknownTotal"
+	useError        = "useError"
+)
+
+const (
+	eventStage    = "build"
+	eventTask     = "Build App"
+	eventStep     = "Compile"
+	eventSecret   = "s3cr3t"
+	eventRedacted = "********"
+)
+
+type testScenario thelper.Scenario
+
+type testJob struct {
+	messages []Message
+}
+
+func (me *testJob) Run(_ context.Context, ch chan Message) {
+	for _, msg := range me.messages {
+		ch <- msg
+	}
+}
+
+func (me *testJob) Name() string {
+	return eventTask
+}
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createConductor creates the Conductor with a single Job sending the
+// scenario's messages. It returns the expected fields of each Event.
+func (s *testScenario) createConductor(events *bytes.Buffer) (c *Conductor,
+	expect [][]string) {
+	job := &testJob{}
+	base := []string{"event", "stage", "task", "timestamp"}
+
+	expect = [][]string{
+		append([]string{EVENT_QUEUED}, base...),
+		append([]string{EVENT_STARTED}, base...),
+	}
+
+	if s.Switches[useSteps] {
+		job.messages = append(job.messages,
+			CreateStep(eventTask, 1, eventStep),
+			CreateStepDone(eventTask, 1, eventStep,
+				1500*time.Millisecond, "command", "go build", 0,
+			),
+		)
+		expect = append(expect,
+			append([]string{EVENT_STEP_STARTED, "index", "step"},
+				base...,
+			),
+			append([]string{EVENT_STEP_FINISHED,
+				"duration_ms", "index", "step",
+			}, base...),
+		)
+	}
+
+	switch {
+	case s.Switches[useProgress]:
+		job.messages = append(job.messages,
+			CreateProgress(eventTask, 512, 1024),
+		)
+		expect = append(expect, append([]string{EVENT_PROGRESS,
+			"downloaded", "total",
+		}, base...))
+	case s.Switches[useUnknownTotal]:
+		job.messages = append(job.messages,
+			CreateProgress(eventTask, 512, -1),
+		)
+		expect = append(expect, append([]string{EVENT_PROGRESS,
+			"downloaded",
+		}, base...))
+	}
+
+	if s.Switches[useError] {
+		job.messages = append(job.messages,
+			CreateError(eventTask, "failed with %s", eventSecret),
+		)
+		expect = append(expect, append([]string{EVENT_ERROR,
+			"duration_ms", "message",
+		}, base...))
+	} else {
+		job.messages = append(job.messages, CreateDone(eventTask))
+		expect = append(expect, append([]string{EVENT_DONE,
+			"duration_ms",
+		}, base...))
+	}
+
+	return &Conductor{
+		Runners: map[string]Job{eventTask: job},
+		Events:  events,
+		Stage:   eventStage,
+		Filter: func(text string) string {
+			return strings.ReplaceAll(text,
+				eventSecret,
+				eventRedacted,
+			)
+		},
+	}, expect
+}
+
+// decode is to read all the JSON lines of the events stream.
+func (s *testScenario) decode(events *bytes.Buffer) (
+	list []map[string]interface{}, err error) {
+	scanner := bufio.NewScanner(events)
+	for scanner.Scan() {
+		event := map[string]interface{}{}
+
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		list = append(list, event)
+	}
+
+	return list, scanner.Err() //nolint:wrapcheck
+}
+
+func (s *testScenario) assertEvents(th *thelper.THelper,
+	events *bytes.Buffer,
+	expect [][]string,
+	err error) {
+	th.ExpectError(err, s.Switches[useError])
+
+	list, err := s.decode(events)
+	th.ExpectError(err, false)
+	th.ExpectSameStrings("events", fmt.Sprint(len(list)),
+		"expected events", fmt.Sprint(len(expect)),
+	)
+
+	for i, event := range list {
+		if i >= len(expect) {
+			break
+		}
+
+		fields := append([]string{}, expect[i][1:]...)
+		sort.Strings(fields)
+
+		name, _ := event["event"].(string)
+		th.ExpectSameStrings("event", name,
+			"expected event", expect[i][0],
+		)
+		th.ExpectSameStrings(name+" fields", s.fields(event),
+			"expected "+name+" fields", strings.Join(fields, ","),
+		)
+
+		s.assertTypes(th, event)
+	}
+}
+
+func (s *testScenario) fields(event map[string]interface{}) string {
+	keys := []string{}
+	for key := range event {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
+
+// assertTypes is to check each field's JSON type and value.
+func (s *testScenario) assertTypes(th *thelper.THelper,
+	event map[string]interface{}) {
+	for key, value := range event {
+		switch key {
+		case "index", "duration_ms", "downloaded", "total":
+			number, ok := value.(float64)
+			ok = ok && number >= 0 &&
+				number == float64(int64(number))
+			th.ExpectSameBool(key+" is integer", ok,
+				"expected "+key+" is integer", true,
+			)
+		default:
+			_, ok := value.(string)
+			th.ExpectSameBool(key+" is string", ok,
+				"expected "+key+" is string", true,
+			)
+		}
+	}
+
+	timestamp, _ := event["timestamp"].(string)
+	_, err := time.Parse(time.RFC3339Nano, timestamp)
+	th.ExpectError(err, false)
+
+	stage, _ := event["stage"].(string)
+	th.ExpectSameStrings("stage", stage, "expected stage", eventStage)
+
+	task, _ := event["task"].(string)
+	th.ExpectSameStrings("task", task, "expected task", eventTask)
+
+	s.assertValues(th, event)
+}
+
+func (s *testScenario) assertValues(th *thelper.THelper,
+	event map[string]interface{}) {
+	name, _ := event["event"].(string)
+
+	switch name {
+	case EVENT_STEP_FINISHED:
+		duration, _ := event["duration_ms"].(float64)
+		th.ExpectSameStrings("duration_ms", fmt.Sprint(duration),
+			"expected duration_ms", "1500",
+		)
+		fallthrough
+	case EVENT_STEP_STARTED:
+		step, _ := event["step"].(string)
+		th.ExpectSameStrings("step", step, "expected step", eventStep)
+	case EVENT_PROGRESS:
+		downloaded, _ := event["downloaded"].(float64)
+		th.ExpectSameStrings("downloaded", fmt.Sprint(downloaded),
+			"expected downloaded", "512",
+		)
+	case EVENT_ERROR:
+		message, _ := event["message"].(string)
+		th.ExpectSameStrings("message", message,
+			"expected message", "failed with "+eventRedacted,
+		)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toml
+
+import (
+	"fmt"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// EncodeBytes is to encode data into TOML `[]byte`.
+//
+// This function is to simplify and to warp a third-party TOML endec for simple
+// utilization.
+func EncodeBytes(data interface{}) (out []byte, err error) {
+	out, err = toml.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_FAILED_ENCODE, err)
+	}
+
+	return out, nil
+}
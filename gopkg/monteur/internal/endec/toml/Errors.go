@@ -18,4 +18,6 @@ package toml
 const (
 	ERROR_FAILED_CONFIG = "failed to open TOML file"
 	ERROR_FAILED_DECODE = "failed to decode TOML"
+	ERROR_FAILED_ENCODE = "failed to encode TOML"
+	ERROR_UNKNOWN_KEY   = "unknown key"
 )
@@ -0,0 +1,45 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toml
+
+import (
+	"testing"
+)
+
+func TestValidateFile(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testValidateFile {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		path := s.createFile(t)
+		data := &testData{}
+
+		// test
+		list, err := ValidateFile(path, data)
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertValidateFile(th, list, data, err)
+		s.log(th, map[string]interface{}{
+			"issues": list,
+			"error":  err,
+		})
+		th.Conclude()
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// Issue is a single problem found inside a TOML document.
+//
+// Line and Column are 1-indexed. When the position cannot be determined, both
+// are set to `0`.
+type Issue struct {
+	Key     string
+	Message string
+	Line    int
+	Column  int
+}
+
+// String is to generate the `line:column: message` representation of Issue.
+func (me *Issue) String() string {
+	if me.Key == "" {
+		return fmt.Sprintf("%d:%d: %s", me.Line, me.Column, me.Message)
+	}
+
+	return fmt.Sprintf("%d:%d: %s '%s'",
+		me.Line,
+		me.Column,
+		me.Message,
+		me.Key,
+	)
+}
+
+var (
+	fieldPattern  = regexp.MustCompile(`struct field ([^ ]+) of type`)
+	stringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'[^']*'`)
+)
+
+// ValidateFile strictly decodes a TOML file and reports all its issues.
+//
+// Unlike `DecodeFile`, all unknown keys are reported individually with their
+// positions in the document. Type mismatch stops the decoding process so only
+// the first one is reported, positioned at the first matching key.
+//
+// The `err` is only returned when the file cannot be read.
+func ValidateFile(path string, data interface{}) (list []*Issue, err error) {
+	var doc []byte
+	var strictErr *toml.StrictMissingError
+	var decodeErr *toml.DecodeError
+
+	doc, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_FAILED_CONFIG, path)
+	}
+
+	decoder := toml.NewDecoder(bytes.NewReader(doc))
+	decoder.SetStrict(true)
+
+	err = decoder.Decode(data)
+	switch {
+	case err == nil:
+	case errors.As(err, &strictErr):
+		for i := range strictErr.Errors {
+			list = append(list, _newIssue(&strictErr.Errors[i],
+				ERROR_UNKNOWN_KEY,
+			))
+		}
+	case errors.As(err, &decodeErr):
+		list = append(list, _newIssue(decodeErr, decodeErr.Error()))
+	default:
+		list = append(list, _newTypeIssue(doc, err))
+	}
+
+	return list, nil
+}
+
+func _newIssue(err *toml.DecodeError, message string) *Issue {
+	line, column := err.Position()
+
+	return &Issue{
+		Key:     strings.Join(err.Key(), "."),
+		Message: message,
+		Line:    line,
+		Column:  column,
+	}
+}
+
+func _newTypeIssue(doc []byte, err error) *Issue {
+	var field string
+
+	ret := &Issue{
+		Message: err.Error(),
+	}
+
+	list := fieldPattern.FindStringSubmatch(err.Error())
+	if len(list) < 2 {
+		return ret
+	}
+
+	field = list[1]
+	field = field[strings.LastIndex(field, ".")+1:]
+	ret.Line, ret.Column = __locateKey(doc, field)
+
+	return ret
+}
+
+func __locateKey(doc []byte, key string) (line int, column int) {
+	var s string
+	var i int
+
+	key = strings.ToLower(key)
+
+	for i, s = range strings.Split(string(doc), "\n") {
+		trimmed := strings.TrimLeft(s, " \t")
+		if !strings.HasPrefix(strings.ToLower(trimmed), key) {
+			continue
+		}
+
+		rest := strings.TrimLeft(trimmed[len(key):], " \t")
+		if !strings.HasPrefix(rest, "=") {
+			continue
+		}
+
+		return i + 1, len(s) - len(trimmed) + 1
+	}
+
+	return 0, 0
+}
+
+// Locate is to find the position of a dotted key inside a TOML document.
+//
+// The key is the path of table and key names separated by `.` where an array
+// of tables is indexed by its order of appearance (e.g. `CMD[1].Name`). Names
+// are matched case-insensitively like the decoder. When the key is absent,
+// the position of its closest parent is returned instead. Both line and
+// column are `0` when nothing matches.
+func Locate(doc []byte, key string) (line int, column int) {
+	positions := __positions(doc)
+	key = strings.ToLower(key)
+
+	for key != "" {
+		if pos, ok := positions[key]; ok {
+			return pos[0], pos[1]
+		}
+
+		if strings.HasSuffix(key, "]") {
+			key = key[:strings.LastIndex(key, "[")]
+			continue
+		}
+
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			break
+		}
+
+		key = key[:i]
+	}
+
+	return 0, 0
+}
+
+// __positions maps the path of every table and key to its first position.
+//
+// Multi-line strings and arrays are skipped so their contents are never
+// mistaken as keys or tables.
+func __positions(doc []byte) map[string][2]int {
+	var table, delimiter string
+	var depth int
+
+	out := map[string][2]int{}
+	counts := map[string]int{}
+
+	for i, s := range strings.Split(string(doc), "\n") {
+		trimmed := strings.TrimLeft(s, " \t")
+		pos := [2]int{i + 1, len(s) - len(trimmed) + 1}
+		path := ""
+
+		switch {
+		case delimiter != "":
+			if strings.Count(s, delimiter)%2 == 1 {
+				delimiter = ""
+			}
+
+			continue
+		case depth > 0:
+			depth += __depth(s)
+			continue
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "[["):
+			end := strings.Index(trimmed, "]]")
+			if end < 0 {
+				continue
+			}
+
+			name := __keyPath(trimmed[2:end])
+			table = fmt.Sprintf("%s[%d]", name, counts[name])
+			counts[name]++
+			path = table
+		case strings.HasPrefix(trimmed, "["):
+			end := strings.Index(trimmed, "]")
+			if end < 0 {
+				continue
+			}
+
+			table = __keyPath(trimmed[1:end])
+			path = table
+		default:
+			eq := strings.Index(trimmed, "=")
+			if eq <= 0 {
+				continue
+			}
+
+			path = __keyPath(trimmed[:eq])
+			if table != "" {
+				path = table + "." + path
+			}
+
+			value := trimmed[eq+1:]
+			depth = __depth(value)
+
+			for _, d := range []string{`"""`, "'''"} {
+				if strings.Count(value, d)%2 == 1 {
+					delimiter = d
+				}
+			}
+		}
+
+		if _, ok := out[path]; !ok {
+			out[path] = pos
+		}
+	}
+
+	return out
+}
+
+// __keyPath is to normalize a (dotted) TOML key into its lowercase path.
+func __keyPath(key string) string {
+	list := strings.Split(key, ".")
+
+	for i, v := range list {
+		list[i] = strings.ToLower(strings.Trim(v, " \t\"'"))
+	}
+
+	return strings.Join(list, ".")
+}
+
+// __depth is to get the unclosed array brackets of a value line.
+func __depth(value string) int {
+	value = stringPattern.ReplaceAllString(value, "")
+	if i := strings.Index(value, "#"); i >= 0 {
+		value = value[:i]
+	}
+
+	return strings.Count(value, "[") - strings.Count(value, "]")
+}
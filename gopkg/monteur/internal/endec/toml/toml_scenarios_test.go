@@ -0,0 +1,153 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toml
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testValidateFile,
+			Description: `
+ValidateFile should decode without issues when:
+1. the document is valid and matches the data structure.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testValidateFile,
+			Description: `
+ValidateFile should report each unknown key with its position when:
+1. the document has unknown keys and tables.
+2. the rest of the document is still decoded.
+`,
+			Switches: map[string]bool{
+				useUnknownKeys: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testValidateFile,
+			Description: `
+ValidateFile should report the mismatch at its line when:
+1. a value does not match its field type.
+`,
+			Switches: map[string]bool{
+				useTypeMismatch: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testValidateFile,
+			Description: `
+ValidateFile should report the syntax error at its line when:
+1. the document is not a valid TOML document.
+`,
+			Switches: map[string]bool{
+				useSyntaxError: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testValidateFile,
+			Description: `
+ValidateFile should return an error when:
+1. the file does not exist.
+`,
+			Switches: map[string]bool{
+				useMissingFile: true,
+				expectError:    true,
+			},
+		}, {
+			UID:      6,
+			TestType: testLocate,
+			Description: `
+Locate should find the key when:
+1. the key is inside a table.
+`,
+			Switches: map[string]bool{
+				useTableKey: true,
+			},
+		}, {
+			UID:      7,
+			TestType: testLocate,
+			Description: `
+Locate should find the key of the indexed table case-insensitively when:
+1. the key is inside the second array of tables.
+2. the key is written in lowercase.
+`,
+			Switches: map[string]bool{
+				useIndexedKey: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testLocate,
+			Description: `
+Locate should fall back to the parent table when:
+1. the key is absent from its array of tables.
+`,
+			Switches: map[string]bool{
+				useAbsentKey: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testLocate,
+			Description: `
+Locate should find the indented key when:
+1. the table name is quoted.
+`,
+			Switches: map[string]bool{
+				useQuotedTable: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testLocate,
+			Description: `
+Locate should not find the table when:
+1. the table header is inside a multi-line string.
+`,
+			Switches: map[string]bool{
+				useMultilineString: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testLocate,
+			Description: `
+Locate should fall back to the array key when:
+1. the key is an element of a multi-line array.
+`,
+			Switches: map[string]bool{
+				useMultilineArray: true,
+			},
+		}, {
+			UID:      12,
+			TestType: testLocate,
+			Description: `
+Locate should not mistake a nested array as a table when:
+1. the nested array is inside a multi-line array.
+`,
+			Switches: map[string]bool{
+				useNestedArray: true,
+			},
+		}, {
+			UID:      13,
+			TestType: testLocate,
+			Description: `
+Locate should return 0:0 when:
+1. neither the key nor its parents are in the document.
+`,
+			Switches: map[string]bool{
+				useUnknownTable: true,
+			},
+		},
+	}
+}
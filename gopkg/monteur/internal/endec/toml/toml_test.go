@@ -0,0 +1,229 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testValidateFile = "testValidateFile"
+	testLocate       = "testLocate"
+)
+
+const (
+	useUnknownKeys  = "useUnknownKeys"
+	useTypeMismatch = "useTypeMismatch"
+	useSyntaxError  = "useSyntaxError"
+	useMissingFile  = "useMissingFile"
+
+	useTableKey        = "useTableKey"
+	useIndexedKey      = "useIndexedKey"
+	useAbsentKey       = "useAbsentKey"
+	useQuotedTable     = "useQuotedTable"
+	useMultilineString = "useMultilineString"
+	useMultilineArray  = "useMultilineArray"
+	useNestedArray     = "useNestedArray"
+	useUnknownTable    = "useUnknownTable"
+
+	expectError = "expectError"
+)
+
+// testDocument is the TOML document for all scenarios. Its line numbers are
+// used as the expected positions so keep them in sync when editing.
+const testDocument = `# monteur test document
+[Metadata]
+Name = "Test"
+Description = """
+Name = "not a key"
+[NotATable]
+"""
+
+[Sources.'linux-amd64']
+  URL = "https://example.com"
+  Fallbacks = [
+    [ "nested" ],
+    "https://example.net",
+  ]
+
+[[CMD]]
+Name = "first"
+Condition = [ "all-all" ]
+
+[[CMD]]
+name = "second"
+`
+
+type testData struct {
+	Metadata *struct {
+		Name        string
+		Description string
+	}
+	Sources map[string]*struct {
+		URL       string
+		Fallbacks []interface{}
+	}
+	CMD []*struct {
+		Name      string
+		Condition []string
+	}
+}
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createDocument appends the broken lines of the scenario from line 22
+// onwards.
+func (s *testScenario) createDocument() string {
+	switch {
+	case s.Switches[useUnknownKeys]:
+		return testDocument + "Extra = true\n\n[Unknown]\nKey = 1\n"
+	case s.Switches[useTypeMismatch]:
+		return testDocument + "Condition = \"all-all\"\n"
+	case s.Switches[useSyntaxError]:
+		return testDocument + "Broken = \n"
+	default:
+		return testDocument
+	}
+}
+
+func (s *testScenario) createFile(t *testing.T) (path string) {
+	path = filepath.Join(t.TempDir(), "config.toml")
+	if s.Switches[useMissingFile] {
+		return path
+	}
+
+	err := os.WriteFile(path, []byte(s.createDocument()), 0600)
+	if err != nil {
+		t.Fatalf("failed to create test file: %s", err)
+	}
+
+	return path
+}
+
+func (s *testScenario) assertValidateFile(th *thelper.THelper,
+	list []*Issue,
+	data *testData,
+	err error) {
+	if s.Switches[expectError] {
+		th.ExpectError(err, true)
+		return
+	}
+
+	th.ExpectError(err, false)
+
+	switch {
+	case s.Switches[useUnknownKeys]:
+		th.ExpectSameBool("issues", len(list) == 2,
+			"expected issues", true,
+		)
+
+		if len(list) == 2 {
+			s.assertIssue(th, list[0], ERROR_UNKNOWN_KEY, 22, 1)
+			s.assertIssue(th, list[1], ERROR_UNKNOWN_KEY, 24, 2)
+			th.ExpectSameBool("key", strings.HasSuffix(list[0].Key,
+				"Extra"),
+				"expected key", true,
+			)
+		}
+
+		// unknown keys do not stop the decoding
+		th.ExpectSameBool("decoded", len(data.CMD) == 2,
+			"expected decoded", true,
+		)
+	case s.Switches[useTypeMismatch], s.Switches[useSyntaxError]:
+		th.ExpectSameBool("issues", len(list) == 1,
+			"expected issues", true,
+		)
+
+		if len(list) == 1 {
+			th.ExpectSameBool("line", list[0].Line == 22,
+				"expected line", true,
+			)
+		}
+	default:
+		th.ExpectSameBool("issues", len(list) == 0,
+			"expected issues", true,
+		)
+		th.ExpectSameBool("decoded", len(data.CMD) == 2 &&
+			data.CMD[1].Name == "second" &&
+			data.Sources["linux-amd64"] != nil,
+			"expected decoded", true,
+		)
+	}
+}
+
+func (s *testScenario) assertIssue(th *thelper.THelper,
+	issue *Issue,
+	message string,
+	line int,
+	column int) {
+	th.ExpectSameStrings("message", issue.Message,
+		"expected message", message,
+	)
+	th.ExpectSameBool("position", issue.Line == line &&
+		issue.Column == column,
+		"expected position", true,
+	)
+}
+
+// createKey is to get the key to locate alongside its expected position.
+func (s *testScenario) createKey() (key string, line int, column int) {
+	switch {
+	case s.Switches[useTableKey]:
+		return "Metadata.Name", 3, 1
+	case s.Switches[useIndexedKey]:
+		return "CMD[1].Name", 21, 1
+	case s.Switches[useAbsentKey]:
+		return "CMD[1].Condition", 20, 1
+	case s.Switches[useQuotedTable]:
+		return "Sources.linux-amd64.URL", 10, 3
+	case s.Switches[useMultilineString]:
+		return "NotATable", 0, 0
+	case s.Switches[useMultilineArray]:
+		return "Sources.linux-amd64.Fallbacks[1]", 11, 3
+	case s.Switches[useNestedArray]:
+		return "nested", 0, 0
+	default:
+		return "Packages.unknown.Source", 0, 0
+	}
+}
+
+func (s *testScenario) assertLocate(th *thelper.THelper,
+	line int,
+	column int) {
+	_, expectLine, expectColumn := s.createKey()
+
+	th.ExpectSameBool("line", line == expectLine,
+		"expected line", true,
+	)
+	th.ExpectSameBool("column", column == expectColumn,
+		"expected column", true,
+	)
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitclient
+
+// Error messages are the package standardized messages
+const (
+	ERROR_COMMIT_BAD        = "given Commit is not a full commit hash"
+	ERROR_COMMIT_MISMATCHED = "resolved commit mismatched"
+	ERROR_COMMAND_FAILED    = "git command failed"
+	ERROR_DIRECTORY_BAD     = "given Directory pathing is invalid"
+	ERROR_GIT_MISSING       = "git is missing from local system"
+	ERROR_OFFLINE           = "repository is not available offline"
+	ERROR_REF_MISSING       = "failed to resolve Ref in repository"
+	ERROR_URL_MISSING       = "given URL is missing"
+)
@@ -0,0 +1,276 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// REMOTE_REFS is where the fetched branches are kept locally.
+	REMOTE_REFS = "refs/remotes/origin/"
+
+	// TAG_REFS is where the fetched tags are kept locally.
+	TAG_REFS = "refs/tags/"
+)
+
+// Repository is a local git clone pinned to a specific commit.
+//
+// All operations are done by the `git` program available on the local
+// system.
+type Repository struct {
+	// Directory is the pathing of the local clone.
+	//
+	// It is created with `git init` when it is not a clone yet. An
+	// existing clone is fetched incrementally.
+	Directory string
+
+	// URL is the remote repository location (any git supported URL or
+	// local pathing).
+	URL string
+
+	// Ref is the branch, tag or commit to checkout.
+	//
+	// Default (empty) is to use Commit.
+	Ref string
+
+	// Commit is the full commit hash Ref **MUST** resolve to.
+	Commit string
+
+	// Proxy is the HTTP(S) proxy URL for fetching.
+	Proxy string
+
+	// Offline decides on not fetching the remote repository.
+	//
+	// When set to `true`, only the existing local clone is used.
+	Offline bool
+}
+
+// Checkout fetches the repository and checks out the pinned commit.
+//
+// The resolved commit hash is returned. Should Ref resolves to a different
+// commit from Commit, an error is returned and nothing is checked out.
+func (me *Repository) Checkout(ctx context.Context) (commit string, err error) {
+	err = me.sanitize()
+	if err != nil {
+		return "", err
+	}
+
+	err = me.init(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if !me.Offline {
+		err = me.fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	commit, err = me.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.EqualFold(commit, me.Commit) {
+		return "", fmt.Errorf("%s: '%s' resolved to '%s' instead of '%s'",
+			ERROR_COMMIT_MISMATCHED,
+			me.Ref,
+			commit,
+			me.Commit,
+		)
+	}
+
+	_, err = me.git(ctx, "-c", "advice.detachedHead=false",
+		"checkout", "--quiet", "--force", "--detach", commit,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	// drop any leftover from the previous builds
+	_, err = me.git(ctx, "clean", "-ffdxq")
+	if err != nil {
+		return "", err
+	}
+
+	return commit, nil
+}
+
+func (me *Repository) sanitize() (err error) {
+	_, err = exec.LookPath("git")
+	if err != nil {
+		return fmt.Errorf(ERROR_GIT_MISSING)
+	}
+
+	if me.URL == "" {
+		return fmt.Errorf(ERROR_URL_MISSING)
+	}
+
+	me.Directory, err = filepath.Abs(me.Directory)
+	if err != nil || me.Directory == "" {
+		return fmt.Errorf("%s: '%s'", ERROR_DIRECTORY_BAD, me.Directory)
+	}
+
+	me.Commit = strings.ToLower(me.Commit)
+	if !_isCommit(me.Commit) {
+		return fmt.Errorf("%s: '%s'", ERROR_COMMIT_BAD, me.Commit)
+	}
+
+	if me.Ref == "" {
+		me.Ref = me.Commit
+	}
+
+	return nil
+}
+
+func (me *Repository) init(ctx context.Context) (err error) {
+	var info os.FileInfo
+
+	// only Directory's own clone is usable, not any parent's repository
+	info, err = os.Stat(filepath.Join(me.Directory, ".git"))
+	switch {
+	case err == nil && info.IsDir():
+		return nil
+	case me.Offline:
+		return fmt.Errorf("%s: %s", ERROR_OFFLINE, me.Directory)
+	}
+
+	err = os.RemoveAll(me.Directory)
+	if err == nil {
+		err = os.MkdirAll(me.Directory, 0755)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %s", ERROR_DIRECTORY_BAD, err)
+	}
+
+	_, err = me.git(ctx, "init", "--quiet")
+	return err
+}
+
+func (me *Repository) fetch(ctx context.Context) (err error) {
+	args := []string{}
+
+	if me.Proxy != "" {
+		args = append(args, "-c", "http.proxy="+me.Proxy)
+	}
+
+	args = append(args, "fetch", "--quiet", "--force", "--tags", "--prune",
+		"--",
+		me.URL,
+		"+refs/heads/*:"+REMOTE_REFS+"*",
+	)
+
+	_, err = me.git(ctx, args...)
+	if err != nil {
+		return err
+	}
+
+	// a commit outside of any branch or tag is fetched directly
+	if me.Ref == me.Commit && !me.has(ctx, me.Commit) {
+		args = args[:len(args)-1]
+		args = append(args, me.Commit)
+		_, err = me.git(ctx, args...)
+	}
+
+	return err
+}
+
+func (me *Repository) resolve(ctx context.Context) (commit string, err error) {
+	candidates := []string{
+		REMOTE_REFS + me.Ref,
+		TAG_REFS + me.Ref,
+		me.Ref,
+	}
+
+	for _, ref := range candidates {
+		commit, err = me.git(ctx, "rev-parse", "--verify", "--quiet",
+			"--end-of-options", ref+"^{commit}",
+		)
+		if err == nil {
+			return commit, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: '%s'", ERROR_REF_MISSING, me.Ref)
+}
+
+func (me *Repository) has(ctx context.Context, commit string) bool {
+	_, err := me.git(ctx, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+func (me *Repository) git(ctx context.Context,
+	args ...string) (out string, err error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = me.Directory
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_DIR="+filepath.Join(me.Directory, ".git"),
+		"GIT_WORK_TREE="+me.Directory,
+	)
+
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("%s (git %s): %s",
+			ERROR_COMMAND_FAILED,
+			_subcommand(args),
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func _subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-c":
+			i++
+		case !strings.HasPrefix(args[i], "-"):
+			return args[i]
+		}
+	}
+
+	return ""
+}
+
+func _isCommit(s string) bool {
+	// SHA-1 and SHA-256 object formats
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitclient
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should checkout the pinned commit when:
+1. Ref is a branch.
+2. Commit is the branch's head commit.
+`,
+			Switches: map[string]bool{
+				useBranchRef: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should checkout the pinned commit when:
+1. Ref is an annotated tag.
+2. Commit is the tagged commit.
+`,
+			Switches: map[string]bool{
+				useTagRef: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should checkout the pinned commit when:
+1. Ref is empty.
+2. Commit is a commit in the remote history.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      4,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should checkout the pinned commit when:
+1. Ref is a branch other than the default branch.
+2. Commit is the branch's head commit.
+`,
+			Switches: map[string]bool{
+				useOtherBranch: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should reject the checkout when:
+1. Ref is a branch.
+2. Commit is not the branch's head commit.
+`,
+			Switches: map[string]bool{
+				useBranchRef:   true,
+				useWrongCommit: true,
+				expectError:    true,
+			},
+		}, {
+			UID:      6,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should reject the checkout when:
+1. Ref is a branch.
+2. Commit is an abbreviated commit hash.
+`,
+			Switches: map[string]bool{
+				useBranchRef: true,
+				useBadCommit: true,
+				expectError:  true,
+			},
+		}, {
+			UID:      7,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should reject the checkout when:
+1. Ref is missing from the remote repository.
+`,
+			Switches: map[string]bool{
+				useMissingRef: true,
+				expectError:   true,
+			},
+		}, {
+			UID:      8,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should reject the checkout when:
+1. URL is pointing to a missing repository.
+`,
+			Switches: map[string]bool{
+				useBranchRef:     true,
+				useMissingRemote: true,
+				expectError:      true,
+			},
+		}, {
+			UID:      9,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should reject the checkout when:
+1. URL is empty.
+`,
+			Switches: map[string]bool{
+				useBranchRef: true,
+				useEmptyURL:  true,
+				expectError:  true,
+			},
+		}, {
+			UID:      10,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should fetch the new commit when:
+1. Directory is an existing clone.
+2. Ref is a branch updated in the remote repository.
+3. Commit is the branch's new head commit.
+`,
+			Switches: map[string]bool{
+				useBranchRef:     true,
+				useExistingClone: true,
+				useUpdatedRemote: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should clean the checkout when:
+1. Directory is an existing clone with leftover files.
+2. Ref is a tag.
+`,
+			Switches: map[string]bool{
+				useTagRef:        true,
+				useExistingClone: true,
+				useDirtyClone:    true,
+			},
+		}, {
+			UID:      12,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should checkout the pinned commit when:
+1. Directory is an existing clone.
+2. Offline is set.
+`,
+			Switches: map[string]bool{
+				useBranchRef:     true,
+				useExistingClone: true,
+				useOffline:       true,
+			},
+		}, {
+			UID:      13,
+			TestType: testCheckout,
+			Description: `
+Repository.Checkout should reject the checkout when:
+1. Directory is not a clone.
+2. Offline is set.
+`,
+			Switches: map[string]bool{
+				useBranchRef: true,
+				useOffline:   true,
+				expectError:  true,
+			},
+		},
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitclient
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testCheckout = "testCheckout"
+)
+
+const (
+	useBranchRef     = "useBranchRef"
+	useTagRef        = "useTagRef"
+	useOtherBranch   = "useOtherBranch"
+	useMissingRef    = "useMissingRef"
+	useWrongCommit   = "useWrongCommit"
+	useBadCommit     = "useBadCommit"
+	useMissingRemote = "useMissingRemote"
+	useEmptyURL      = "useEmptyURL"
+	useExistingClone = "useExistingClone"
+	useDirtyClone    = "useDirtyClone"
+	useUpdatedRemote = "useUpdatedRemote"
+	useOffline       = "useOffline"
+
+	expectError = "expectError"
+)
+
+const (
+	fileVersion  = "VERSION"
+	fileLeftover = "leftover.o"
+	branchMain   = "main"
+	branchDev    = "dev"
+	tagRelease   = "v1.0.0"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// testRemote is a local bare repository with the following history:
+//
+//	main: 1 (tag v1.0.0) -- 2
+//	dev:                     \-- 3
+//
+// where each commit writes its number into the VERSION file.
+type testRemote struct {
+	t       *testing.T
+	work    string
+	URL     string
+	commits map[string]string
+}
+
+func (s *testScenario) createRemote(t *testing.T) *testRemote {
+	r := &testRemote{
+		t:       t,
+		work:    filepath.Join(t.TempDir(), "work"),
+		URL:     filepath.Join(t.TempDir(), "remote.git"),
+		commits: map[string]string{},
+	}
+
+	r.git("init", "--quiet", "--initial-branch="+branchMain, r.work)
+	r.commit("1")
+	r.git("-C", r.work, "tag", "--annotate", "--message=release",
+		tagRelease,
+	)
+	r.commit("2")
+	r.git("-C", r.work, "checkout", "--quiet", "-b", branchDev)
+	r.commit("3")
+	r.git("-C", r.work, "checkout", "--quiet", branchMain)
+	r.git("clone", "--quiet", "--bare", r.work, r.URL)
+
+	return r
+}
+
+func (r *testRemote) git(args ...string) string {
+	args = append([]string{
+		"-c", "user.name=Monteur",
+		"-c", "user.email=monteur@example.com",
+		"-c", "commit.gpgsign=false",
+		"-c", "tag.gpgsign=false",
+	}, args...)
+
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("failed to prepare test repository: %s: %s", err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func (r *testRemote) commit(version string) {
+	err := os.WriteFile(filepath.Join(r.work, fileVersion),
+		[]byte(version),
+		0644,
+	)
+	if err != nil {
+		r.t.Fatalf("failed to write test file: %s", err)
+	}
+
+	r.git("-C", r.work, "add", fileVersion)
+	r.git("-C", r.work, "commit", "--quiet", "--message="+version)
+	r.commits[version] = r.git("-C", r.work, "rev-parse", "HEAD")
+}
+
+func (r *testRemote) update() {
+	r.commit("4")
+	r.git("-C", r.work, "push", "--quiet", r.URL, branchMain)
+}
+
+func (s *testScenario) createRepository(t *testing.T,
+	remote *testRemote) (subject *Repository, version string) {
+	subject = &Repository{
+		Directory: filepath.Join(t.TempDir(), "clone"),
+		URL:       remote.URL,
+	}
+
+	// select the pinned Ref and its expected version
+	switch {
+	case s.Switches[useBranchRef]:
+		subject.Ref = branchMain
+		version = "2"
+	case s.Switches[useTagRef]:
+		subject.Ref = tagRelease
+		version = "1"
+	case s.Switches[useOtherBranch]:
+		subject.Ref = branchDev
+		version = "3"
+	case s.Switches[useMissingRef]:
+		subject.Ref = "missing"
+		version = "2"
+	default:
+		version = "1"
+	}
+
+	subject.Commit = remote.commits[version]
+
+	if s.Switches[useExistingClone] {
+		s.prepareClone(t, remote, subject)
+	}
+
+	if s.Switches[useUpdatedRemote] {
+		remote.update()
+		version = "4"
+		subject.Commit = remote.commits[version]
+	}
+
+	switch {
+	case s.Switches[useWrongCommit]:
+		subject.Commit = remote.commits["1"]
+	case s.Switches[useBadCommit]:
+		subject.Commit = subject.Commit[:7]
+	}
+
+	switch {
+	case s.Switches[useMissingRemote]:
+		subject.URL = filepath.Join(t.TempDir(), "missing.git")
+	case s.Switches[useEmptyURL]:
+		subject.URL = ""
+	}
+
+	subject.Offline = s.Switches[useOffline]
+
+	return subject, version
+}
+
+func (s *testScenario) prepareClone(t *testing.T,
+	remote *testRemote, subject *Repository) {
+	clone := &Repository{
+		Directory: subject.Directory,
+		URL:       remote.URL,
+		Ref:       branchMain,
+		Commit:    remote.commits["2"],
+	}
+
+	_, err := clone.Checkout(context.Background())
+	if err != nil {
+		t.Fatalf("failed to prepare test clone: %s", err)
+	}
+
+	if !s.Switches[useDirtyClone] {
+		return
+	}
+
+	err = os.WriteFile(filepath.Join(subject.Directory, fileLeftover),
+		[]byte("leftover"),
+		0644,
+	)
+	if err != nil {
+		t.Fatalf("failed to write test leftover: %s", err)
+	}
+}
+
+func (s *testScenario) assertCheckout(th *thelper.THelper,
+	subject *Repository, remote *testRemote, version string,
+	commit string, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+
+	if s.Switches[expectError] {
+		th.ExpectSameStrings("commit", commit, "expected commit", "")
+		return
+	}
+
+	th.ExpectSameStrings("commit", commit,
+		"expected commit", remote.commits[version],
+	)
+
+	data, _ := os.ReadFile(filepath.Join(subject.Directory, fileVersion))
+	th.ExpectSameStrings("checked out", string(data),
+		"expected checked out", version,
+	)
+
+	_, statErr := os.Stat(filepath.Join(subject.Directory, fileLeftover))
+	th.ExpectSameBool("cleaned", os.IsNotExist(statErr),
+		"expected cleaned", true,
+	)
+}
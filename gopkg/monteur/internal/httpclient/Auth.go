@@ -0,0 +1,233 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Auth is the credentials for authenticating the requests.
+//
+// The credentials are only sent to the Downloader's Origin host (the given
+// URL's host by default). A mirror, fallback URL, or redirect leaving that
+// host gets neither them nor any sensitive Headers, except a netrc entry of
+// its own machine.
+type Auth struct {
+	netrc map[string]*netrcEntry
+
+	// Username and Password are for the `Basic` authentication.
+	Username string
+	Password string
+
+	// Token is for the `Bearer` authentication.
+	Token string
+
+	// Netrc is the netrc file path for looking up the `Basic`
+	// authentication credentials of the attempted URL's host.
+	//
+	// It is only used when both Username and Token are empty.
+	Netrc string
+}
+
+type netrcEntry struct {
+	login    string
+	password string
+}
+
+// IsSensitiveHeader checks the header carries credentials.
+//
+// Such headers are stripped from a redirect leaving the original host and
+// shall never be logged.
+func IsSensitiveHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Authorization", "Cookie", "Private-Token", "Job-Token":
+		return true
+	}
+
+	return false
+}
+
+// NetrcPath is to get the default netrc file path of the current user.
+//
+// The `NETRC` environment variable takes precedence when set.
+func NetrcPath() string {
+	if path := os.Getenv(ENV_NETRC); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+
+	return filepath.Join(home, ".netrc")
+}
+
+func (a *Auth) init() (err error) {
+	var data []byte
+
+	if a == nil || a.Username != "" || a.Token != "" || a.Netrc == "" {
+		return nil
+	}
+
+	data, err = os.ReadFile(a.Netrc)
+	if err != nil {
+		return fmt.Errorf("%s: %s", ERROR_NETRC_BAD, err)
+	}
+
+	a.netrc = _parseNetrc(string(data))
+
+	return nil
+}
+
+func (a *Auth) apply(req *http.Request, trusted bool) {
+	if a == nil {
+		return
+	}
+
+	switch {
+	case a.Token != "":
+		if trusted {
+			req.Header.Set("Authorization", "Bearer "+a.Token)
+		}
+	case a.Username != "":
+		if trusted {
+			req.SetBasicAuth(a.Username, a.Password)
+		}
+	case a.netrc != nil:
+		entry, ok := a.netrc[strings.ToLower(req.URL.Hostname())]
+		if !ok && trusted {
+			entry, ok = a.netrc[""]
+		}
+
+		if ok && entry.login != "" {
+			req.SetBasicAuth(entry.login, entry.password)
+		}
+	}
+}
+
+// checkRedirect strips the credentials from a redirect leaving the Origin
+// host before handing it over to HandleRedirect.
+func (d *Downloader) checkRedirect(req *http.Request,
+	via []*http.Request) error {
+	if len(via) >= REDIRECT_LIMIT {
+		return fmt.Errorf("%s: %d", ERROR_REDIRECT_LIMIT, len(via))
+	}
+
+	if !_sameHost(d.origin, req) {
+		for key := range req.Header {
+			if IsSensitiveHeader(key) {
+				req.Header.Del(key)
+			}
+		}
+	}
+
+	if d.HandleRedirect != nil {
+		return d.HandleRedirect(req, via)
+	}
+
+	return nil
+}
+
+// _sameHost checks the redirect stays on the original host without
+// downgrading from HTTPS.
+func _sameHost(from *http.Request, to *http.Request) bool {
+	if !strings.EqualFold(from.URL.Hostname(), to.URL.Hostname()) {
+		return false
+	}
+
+	return from.URL.Scheme != "https" || to.URL.Scheme == "https"
+}
+
+// _parseNetrc parses the netrc data into credentials keyed by the lowercase
+// machine name where the `default` entry is keyed by an empty string.
+func _parseNetrc(data string) (out map[string]*netrcEntry) {
+	var entry *netrcEntry
+	var fields []string
+	var macro bool
+
+	out = map[string]*netrcEntry{}
+
+	for _, line := range strings.Split(data, "\n") {
+		fields = strings.Fields(line)
+
+		// macro definition runs until an empty line
+		if macro {
+			macro = len(fields) > 0
+			continue
+		}
+
+		macro = _parseNetrcLine(out, &entry, fields)
+	}
+
+	return out
+}
+
+// _parseNetrcLine parses the line's tokens into the list and reports whether
+// a macro definition begins.
+func _parseNetrcLine(list map[string]*netrcEntry,
+	entry **netrcEntry,
+	fields []string) (macro bool) {
+	var key, value string
+
+	for i := 0; i < len(fields); i += 2 {
+		key = fields[i]
+		value = ""
+		if i+1 < len(fields) {
+			value = fields[i+1]
+		}
+
+		switch {
+		case strings.HasPrefix(key, "#"):
+			return false
+		case key == "macdef":
+			return true
+		case key == "default":
+			*entry = _netrcEntry(list, "")
+			i-- // default has no value
+		case key == "machine":
+			*entry = _netrcEntry(list, strings.ToLower(value))
+		case *entry == nil:
+		case key == "login":
+			(*entry).login = value
+		case key == "password":
+			(*entry).password = value
+		}
+	}
+
+	return false
+}
+
+// _netrcEntry creates the machine's entry where only its first occurrence
+// counts. A repeated machine gets a discarded entry instead.
+func _netrcEntry(list map[string]*netrcEntry, machine string) *netrcEntry {
+	if _, ok := list[machine]; ok {
+		return &netrcEntry{}
+	}
+
+	list[machine] = &netrcEntry{}
+
+	return list[machine]
+}
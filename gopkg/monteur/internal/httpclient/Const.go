@@ -15,6 +15,10 @@
 
 package httpclient
 
+import (
+	"time"
+)
+
 const (
 	// TIMEOUT is the default timing for timeout a download in seconds.
 	TIMEOUT = 120
@@ -26,10 +30,40 @@ const (
 	DIR_PERMISSION = 0700
 )
 
+const (
+	// RETRY_ATTEMPTS is the default maximum attempts per URL.
+	RETRY_ATTEMPTS = 4
+
+	// RETRY_DELAY is the default initial backoff delay between attempts.
+	RETRY_DELAY = 1 * time.Second
+
+	// RETRY_MAX_DELAY is the default maximum backoff delay between
+	// attempts, including the server's requested `Retry-After`.
+	RETRY_MAX_DELAY = 30 * time.Second
+)
+
+const (
+	// SEGMENT_MIN_SIZE is the minimum size of a segment in bytes for a
+	// segmented download.
+	SEGMENT_MIN_SIZE = 1024 * 1024
+)
+
+const (
+	// REDIRECT_LIMIT is the maximum redirects followed per request.
+	REDIRECT_LIMIT = 10
+
+	// ENV_NETRC is the environment variable overriding the netrc file path.
+	ENV_NETRC = "NETRC"
+)
+
 const (
 	// EXTENSION_DOWNLOAD is the common downloding status extension
 	EXTENSION_DOWNLOAD = ".download"
 
+	// EXTENSION_SEGMENT is the part file extension prefix of a segment
+	// where its index is appended (e.g. `.download.part0`)
+	EXTENSION_SEGMENT = EXTENSION_DOWNLOAD + ".part"
+
 	// EXTENSION_ERROR is the error tag prefix to an extension
 	EXTENSION_ERROR = "-error"
 
@@ -17,6 +17,7 @@ package httpclient
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"mime"
@@ -26,18 +27,62 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 )
 
 type Downloader struct {
+	origin    *http.Request
 	request   *http.Request
 	indicator *indicator
 	checksum  Checksum
+	located   bool
+	attempted bool
+	ranges    bool
+	single    bool
+
+	// Retry is the retry policy for failed attempts.
+	//
+	// If `nil`, the default Retry policy is used.
+	Retry *Retry
+
+	// Segments is the maximum concurrent `Range` requests for a single
+	// download.
+	//
+	// It only applies when the server advertises `Accept-Ranges: bytes` and
+	// each segment has at least SEGMENT_MIN_SIZE bytes. Otherwise, or when
+	// it is less than 2, the download is a single stream.
+	Segments uint
+
+	// Fallbacks are the alternative URLs tried in order when the given URL
+	// still fails after all its retry attempts.
+	Fallbacks []string
 
 	// Headers are the additional headers to add into the request.
 	Headers map[string]string
 
+	// Auth is the credentials for authenticating the requests.
+	//
+	// If `nil`, only the given Headers are sent.
+	Auth *Auth
+
+	// Origin is the URL whose host the Auth credentials and the sensitive
+	// Headers are meant for (e.g. the URL before a mirror rewrote it).
+	//
+	// If empty, the given URL's host is used.
+	Origin string
+
+	// Proxy is the HTTP(S) proxy URL for all the requests.
+	//
+	// If empty, the proxy is determined from the environment variables
+	// (e.g. `HTTPS_PROXY`) instead.
+	Proxy string
+
+	// TLS is the TLS configuration for the HTTPS connections (e.g. custom
+	// root CAs or client certificates).
+	//
+	// If `nil`, the default TLS configuration is used.
+	TLS *tls.Config
+
 	// HandleError is a function handler for handling error the user way.
 	HandleError func(err error)
 
@@ -48,8 +93,16 @@ type Downloader struct {
 	// is completed.
 	HandleSuccess func()
 
+	// HandleRetry is a function handler to execute before waiting for the
+	// next attempt of a failed URL.
+	HandleRetry func(url string, err error, delay time.Duration)
+
 	// HandleRedirect is a function handler to execute upon receiving a
 	// redirect instruction from the server.
+	//
+	// The credentials are already stripped from the redirect request when
+	// it leaves the original host. If `nil`, the redirects are followed up
+	// to REDIRECT_LIMIT times.
 	HandleRedirect func(req *http.Request, via []*http.Request) error
 
 	// Destination is the directory + (optionally) filename for file saving.
@@ -96,6 +149,11 @@ type Downloader struct {
 // Download accepts a context for cancellation or timeout controls over it. The
 // `ctx` shall not be nil or by minimum, provide context.Background() instead.
 //
+// Each URL (the given one followed by Downloader.Fallbacks) is attempted
+// according to Downloader.Retry. A failed attempt leaves its partial download
+// behind so that the next attempt of the same URL resumes it using a `Range`
+// request. The partial download is discarded when moving to the next URL.
+//
 // Checksum is the data structure for checking the file integrity.
 //
 // This data is optional. When provided, Downloader will perform checksum before
@@ -106,12 +164,9 @@ func (d *Downloader) Download(ctx context.Context,
 	method string,
 	urlstr string,
 	hasher Checksum) {
-	var response *http.Response
 	var client *http.Client
-	var inReader io.Reader
 	var err error
-	var destination, ext, contentType, errorFile string
-	var extList []string
+	var ok bool
 
 	client, err = d.init(ctx, method, urlstr, hasher)
 	if err != nil {
@@ -119,95 +174,219 @@ func (d *Downloader) Download(ctx context.Context,
 		return
 	}
 
-	err = d.obtainMetadata(client)
+	for i, target := range append([]string{urlstr}, d.Fallbacks...) {
+		if i > 0 {
+			// never resume a partial download from another source
+			_ = os.Remove(d.Destination + EXTENSION_DOWNLOAD)
+			d.removeSegments()
+			d.single = false
+		}
+
+		err = d.tryURL(ctx, client, method, target)
+		if _, ok = err.(*remoteError); !ok {
+			break
+		}
+	}
+
+	if re, isRemote := err.(*remoteError); isRemote {
+		err = re.err
+	}
+
 	if err != nil {
 		d.handleError(err)
 		return
 	}
 
-	err = d.tryResume()
+	err = d.checksumArtifact()
 	if err != nil {
 		d.handleError(err)
 		return
 	}
 
-	// open the destination file for download
-	destination = d.Destination + EXTENSION_DOWNLOAD
-	f, err := os.OpenFile(destination,
-		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
-		FILE_PERMISSION,
-	)
+	err = d.renameArtifact()
 	if err != nil {
 		d.handleError(err)
 		return
 	}
 
-	defer func() {
-		f.Close()
+	d.handleSuccess()
+}
 
-		if response.StatusCode >= 400 {
-			// get or make error extension from response
-			contentType = response.Header.Get("Content-Type")
-			extList, _ = mime.ExtensionsByType(contentType)
-			ext = EXTENSION_HTML
-			if len(extList) > 0 {
-				ext = extList[len(extList)-1]
-			}
-			ext = EXTENSION_ERROR + ext
-
-			// rename the downloaded content into error response
-			// file
-			errorFile = strings.TrimSuffix(destination,
-				EXTENSION_DOWNLOAD,
-			)
-			errorFile += ext
+func (d *Downloader) tryURL(ctx context.Context,
+	client *http.Client,
+	method string,
+	target string) (err error) {
+	var re *remoteError
+	var ok bool
 
-			err = os.Rename(destination, errorFile)
+	for attempt := uint(0); ; attempt++ {
+		err = d.attempt(ctx, client, method, target)
 
-			// handle error and exit
-			d.handleError(fmt.Errorf("%s: status code %d",
-				ERROR_RESPONSE_BAD,
-				response.StatusCode,
-			))
-			return
+		re, ok = err.(*remoteError)
+		if !ok || !re.retry || attempt+1 >= d.Retry.attempts() {
+			return err
 		}
 
-		if err != nil {
-			return
+		delay := d.Retry.backoff(attempt, re.wait)
+		if d.HandleRetry != nil {
+			d.HandleRetry(target, re.err, delay)
 		}
 
-		err = d.checksumArtifact()
-		if err != nil {
-			d.handleError(err)
-			return
+		if _sleep(ctx, delay) != nil {
+			return re.err
 		}
+	}
+}
 
-		err = d.renameArtifact()
-		if err != nil {
-			d.handleError(err)
-			return
-		}
+func (d *Downloader) attempt(ctx context.Context,
+	client *http.Client,
+	method string,
+	target string) (err error) {
+	var response *http.Response
+	var f *os.File
+	var flags int
 
-		d.handleSuccess()
-	}()
+	d.request, err = d.newRequest(ctx, method, target)
+	if err != nil {
+		return &remoteError{err: err}
+	}
+
+	err = d.obtainMetadata(client)
+	if err != nil {
+		return err
+	}
+
+	err = d.tryResume()
+	d.attempted = true
+	if err != nil {
+		return err
+	}
+
+	if list := d.segments(); list != nil {
+		return d.attemptSegments(ctx, client, list)
+	}
 
 	// make the download request
 	response, err = client.Do(d.request)
 	if err != nil {
-		d.handleError(err)
-		return
+		return &remoteError{
+			err:   fmt.Errorf("%s: %s", ERROR_REQUEST_FAILED, err),
+			retry: ctx.Err() == nil,
+		}
 	}
+	defer response.Body.Close()
 
-	defer func() {
-		response.Body.Close()
-	}()
+	flags = os.O_APPEND | os.O_CREATE | os.O_WRONLY
+
+	switch {
+	case response.StatusCode >= http.StatusBadRequest:
+		return d.saveErrorResponse(response)
+	case response.StatusCode == http.StatusPartialContent:
+		err = d.checkRange(response.Header.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+	default:
+		// server sent the whole content so start over
+		d.indicator.downloaded = 0
+		flags |= os.O_TRUNC
+	}
+
+	// open the destination file for download
+	f, err = os.OpenFile(d.Destination+EXTENSION_DOWNLOAD,
+		flags,
+		FILE_PERMISSION,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
 
 	// read the content
-	inReader = io.TeeReader(response.Body, d.indicator)
+	_, err = io.Copy(f, io.TeeReader(response.Body, d.indicator))
+	f.Close()
 
-	_, err = io.Copy(f, inReader)
 	if err != nil {
-		d.handleError(err)
+		return &remoteError{
+			err:   fmt.Errorf("%s: %s", ERROR_REQUEST_FAILED, err),
+			retry: ctx.Err() == nil,
+		}
+	}
+
+	if d.indicator.downloaded < d.indicator.total {
+		return &remoteError{
+			err: fmt.Errorf("%s: %d/%d bytes",
+				ERROR_DOWNLOAD_TRUNCATED,
+				d.indicator.downloaded,
+				d.indicator.total,
+			),
+			retry: true,
+		}
+	}
+
+	return nil
+}
+
+func (d *Downloader) saveErrorResponse(response *http.Response) (err error) {
+	var ext, errorFile string
+	var extList []string
+
+	// get or make error extension from response
+	extList, _ = mime.ExtensionsByType(response.Header.Get("Content-Type"))
+	ext = EXTENSION_HTML
+	if len(extList) > 0 {
+		ext = extList[len(extList)-1]
+	}
+
+	// save the error response without touching the partial download
+	errorFile = d.Destination + EXTENSION_ERROR + ext
+	f, err := os.OpenFile(errorFile,
+		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
+		FILE_PERMISSION,
+	)
+	if err == nil {
+		_, _ = io.Copy(f, response.Body)
+		f.Close()
+	}
+
+	if response.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// the partial download is unusable so start over
+		_ = os.Remove(d.Destination + EXTENSION_DOWNLOAD)
+	}
+
+	return _statusError(response)
+}
+
+// _statusError is the remote error of the response's bad status code.
+func _statusError(response *http.Response) *remoteError {
+	return &remoteError{
+		err: fmt.Errorf("%s: status code %d",
+			ERROR_RESPONSE_BAD,
+			response.StatusCode,
+		),
+		retry: _retryable(response.StatusCode) ||
+			response.StatusCode == http.StatusRequestedRangeNotSatisfiable,
+		wait: _retryAfter(response.Header.Get("Retry-After")),
+	}
+}
+
+// checkRange ensures the partial content continues from the partial download.
+func (d *Downloader) checkRange(contentRange string) (err error) {
+	var start int64
+
+	_, err = fmt.Sscanf(contentRange, "bytes %d-", &start)
+	if err == nil && start == d.indicator.downloaded {
+		return nil
+	}
+
+	_ = os.Remove(d.Destination + EXTENSION_DOWNLOAD)
+
+	return &remoteError{
+		err: fmt.Errorf("%s: '%s' for %d",
+			ERROR_RANGE_MISMATCHED,
+			contentRange,
+			d.indicator.downloaded,
+		),
+		retry: true,
 	}
 }
 
@@ -253,21 +432,33 @@ func (d *Downloader) init(ctx context.Context,
 		d.checksum = hasher
 	}
 
-	// configure new http request for the downloader
-	d.request, err = http.NewRequest(method, urlStr, nil)
+	// load the credentials before any request
+	err = d.Auth.init()
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", ERROR_REQUEST_INIT_FAILED, err)
+		return nil, err
 	}
 
-	// add headers if available
-	if d.Headers != nil {
-		for k, v := range d.Headers {
-			d.request.Header.Set(k, v)
+	// credentials are only meant for the Origin host
+	d.origin = nil
+	if d.Origin != "" {
+		d.origin, err = http.NewRequest(method, d.Origin, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s",
+				ERROR_REQUEST_INIT_FAILED,
+				err,
+			)
 		}
 	}
 
-	// add given context into request
-	d.request = d.request.WithContext(ctx)
+	// validate the request is constructible
+	d.request, err = d.newRequest(ctx, method, urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.origin == nil {
+		d.origin = d.request
+	}
 
 	// set timeout to default TIMEOUT seconds for bad or 0 value
 	if d.Timeout <= 0 {
@@ -276,17 +467,68 @@ func (d *Downloader) init(ctx context.Context,
 
 	// setup the http client
 	client = &http.Client{
-		Timeout: d.Timeout,
+		Timeout:       d.Timeout,
+		CheckRedirect: d.checkRedirect,
 	}
 
-	// insert redirect function if available
-	if d.HandleRedirect != nil {
-		client.CheckRedirect = d.HandleRedirect
+	client.Transport, err = d.newTransport()
+	if err != nil {
+		return nil, err
 	}
 
 	return client, nil
 }
 
+func (d *Downloader) newTransport() (transport *http.Transport, err error) {
+	var proxy *url.URL
+
+	transport = http.DefaultTransport.(*http.Transport).Clone()
+
+	if d.Proxy != "" {
+		proxy, err = url.Parse(d.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			// never print the given value as it may carry credentials
+			return nil, fmt.Errorf(ERROR_PROXY_BAD)
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if d.TLS != nil {
+		transport.TLSClientConfig = d.TLS
+	}
+
+	return transport, nil
+}
+
+func (d *Downloader) newRequest(ctx context.Context,
+	method string,
+	urlStr string) (req *http.Request, err error) {
+	// configure new http request for the downloader
+	req, err = http.NewRequest(method, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_REQUEST_INIT_FAILED, err)
+	}
+
+	// credentials are only meant for the given URL's host
+	trusted := d.origin == nil || _sameHost(d.origin, req)
+
+	// add headers if available
+	for k, v := range d.Headers {
+		if !trusted && IsSensitiveHeader(k) {
+			continue
+		}
+
+		req.Header.Set(k, v)
+	}
+
+	// add credentials if available
+	d.Auth.apply(req, trusted)
+
+	// add given context into request
+	return req.WithContext(ctx), nil
+}
+
 func (d *Downloader) checksumArtifact() (err error) {
 	var f *os.File
 	var ok bool
@@ -347,6 +589,12 @@ func (d *Downloader) renameArtifact() (err error) {
 func (d *Downloader) tryResume() (err error) {
 	var fi os.FileInfo
 
+	d.indicator.downloaded = 0
+
+	if d.Overwrite && !d.attempted {
+		d.removeSegments()
+	}
+
 	// check for any existing download artifacts
 	fi, err = os.Stat(d.Destination + EXTENSION_DOWNLOAD)
 
@@ -359,8 +607,9 @@ func (d *Downloader) tryResume() (err error) {
 		return nil
 	}
 
-	// if overwrite, execute overwrite and let go
-	if d.Overwrite {
+	// if overwrite, execute overwrite for the first attempt and let go.
+	// The later attempts always resume their own partial download.
+	if d.Overwrite && !d.attempted {
 		err = os.Remove(d.Destination + EXTENSION_DOWNLOAD)
 		if err != nil {
 			return fmt.Errorf("%s: %s",
@@ -378,6 +627,7 @@ func (d *Downloader) tryResume() (err error) {
 	// reject invalid data
 	if d.indicator.downloaded >= d.indicator.total {
 		d.indicator.downloaded = 0
+		_ = os.Remove(d.Destination + EXTENSION_DOWNLOAD)
 		return nil
 	}
 
@@ -394,18 +644,40 @@ func (d *Downloader) obtainMetadata(client *http.Client) (err error) {
 	var length, disposition string
 
 	// request header and extract all target metadata
-	response, err = client.Head(d.request.URL.String())
+	head := d.request.Clone(d.request.Context())
+	head.Method = http.MethodHead
+
+	response, err = client.Do(head)
 	if err != nil {
-		return fmt.Errorf("%s: %s", ERROR_REQUEST_FAILED, err)
+		return &remoteError{
+			err:   fmt.Errorf("%s: %s", ERROR_REQUEST_FAILED, err),
+			retry: d.request.Context().Err() == nil,
+		}
 	}
 
 	length = response.Header.Get("content-length")
 	disposition = response.Header.Get("content-Disposition")
+	d.ranges = response.Header.Get("Accept-Ranges") == "bytes"
 	response.Body.Close()
 
+	if _retryable(response.StatusCode) {
+		return &remoteError{
+			err: fmt.Errorf("%s: status code %d",
+				ERROR_RESPONSE_BAD,
+				response.StatusCode,
+			),
+			retry: true,
+			wait:  _retryAfter(response.Header.Get("Retry-After")),
+		}
+	}
+
 	err = d.processSize(length)
 	if err != nil {
-		return err
+		return &remoteError{err: err}
+	}
+
+	if d.located {
+		return nil
 	}
 
 	err = d.processFilepath(disposition)
@@ -413,6 +685,11 @@ func (d *Downloader) obtainMetadata(client *http.Client) (err error) {
 		return err
 	}
 
+	d.located = true
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
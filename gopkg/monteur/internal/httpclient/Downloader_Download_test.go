@@ -0,0 +1,61 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDownload(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testDownload {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		content := s.createContent()
+		server := s.createServer(content)
+		ts := s.startServer(server)
+		fallbacks, fallback, stop := s.createFallbacks(content)
+		d, errs, progress := s.createDownloader(t, content)
+		d.Fallbacks = fallbacks
+		target, stopProxy := s.configureNetwork(d, ts, server)
+		target, stopRedirect := s.configureAuth(t, d, target)
+
+		// test
+		d.Download(Context(), http.MethodGet, target, nil)
+		ts.Close()
+		stop()
+		stopProxy()
+		stopRedirect()
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertDownload(th, d, server, fallback, content, *errs, *progress)
+		s.log(th, map[string]interface{}{
+			"gets":     server.gets,
+			"proxied":  server.proxied,
+			"ranges":   server.ranges,
+			"progress": *progress,
+			"creds":    server.credentials,
+			"fallback": fallback.credentials,
+			"errors":   *errs,
+		})
+		th.Conclude()
+	}
+}
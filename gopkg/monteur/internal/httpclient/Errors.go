@@ -21,6 +21,7 @@ const (
 	ERROR_CHECKSUM_BAD_FILE      = "failed to open file for checksum"
 	ERROR_CHECKSUM_DELETE_FAILED = "failed to remove bad checksum file"
 	ERROR_CHECKSUM_MISMATCHED    = "checksum mismatched"
+	ERROR_DOWNLOAD_TRUNCATED     = "download ended before completion"
 	ERROR_FILE_EXISTS            = "destination file already exists"
 	ERROR_FILE_OVERWRITE_FAILED  = "failed to overwrite destination file"
 	ERROR_FILE_STAT              = "failed to obtain file stat locally"
@@ -29,9 +30,15 @@ const (
 	ERROR_FILESIZE_MISSING       = "failed to obtain filesize remotely"
 	ERROR_HASHER_UNHEALTHY       = "given checksum hasher is not healthy"
 	ERROR_METHOD_MISSING         = "request method is missing"
+	ERROR_NETRC_BAD              = "failed to read netrc file"
 	ERROR_PATH_INVALID           = "given Destination pathing is invalid"
 	ERROR_PATH_MISSING           = "given Destination pathing is missing"
+	ERROR_PROXY_BAD              = "given Proxy is not a valid URL"
 	ERROR_REQUEST_FAILED         = "failed to perform request remotely"
 	ERROR_REQUEST_INIT_FAILED    = "failed to initialize request"
+	ERROR_RANGE_IGNORED          = "server ignored the segment range"
+	ERROR_RANGE_MISMATCHED       = "server resumed from a different offset"
+	ERROR_REDIRECT_LIMIT         = "stopped after too many redirects"
 	ERROR_RESPONSE_BAD           = "bad response"
+	ERROR_SEGMENT_JOIN_FAILED    = "failed to join downloaded segments"
 )
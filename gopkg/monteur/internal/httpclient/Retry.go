@@ -0,0 +1,147 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry is the retry policy for the failed download attempts.
+//
+// Connection errors, truncated bodies, and `5xx`, `408` or `429` responses are
+// retried with exponential backoff and jitter. The server's `Retry-After`
+// header is honored when given.
+//
+// Retry is safe to be created using the standard `&struct{}` method.
+type Retry struct {
+	// Attempts is the maximum attempts per URL (default: RETRY_ATTEMPTS).
+	Attempts uint
+
+	// Delay is the initial backoff delay (default: RETRY_DELAY).
+	Delay time.Duration
+
+	// MaxDelay is the maximum backoff delay (default: RETRY_MAX_DELAY).
+	MaxDelay time.Duration
+}
+
+func (r *Retry) attempts() uint {
+	if r == nil || r.Attempts == 0 {
+		return RETRY_ATTEMPTS
+	}
+
+	return r.Attempts
+}
+
+// backoff calculates the delay before the next attempt. The server requested
+// `wait` is used when given. Otherwise, it is the exponential delay with equal
+// jitter. Both are capped by the maximum delay.
+func (r *Retry) backoff(attempt uint, wait time.Duration) time.Duration {
+	delay := RETRY_DELAY
+	max := RETRY_MAX_DELAY
+
+	if r != nil && r.Delay > 0 {
+		delay = r.Delay
+	}
+
+	if r != nil && r.MaxDelay > 0 {
+		max = r.MaxDelay
+	}
+
+	if wait > 0 {
+		if wait > max {
+			return max
+		}
+
+		return wait
+	}
+
+	for i := uint(0); i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		delay = max
+	}
+
+	half := delay / 2
+
+	//nolint:gosec
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// remoteError is the failure from the remote server instead of local system.
+//
+// Retrying the same URL is only permitted when `retry` is set while another
+// URL (e.g. fallback) is always permitted.
+type remoteError struct {
+	err   error
+	retry bool
+	wait  time.Duration
+}
+
+func (e *remoteError) Error() string {
+	return e.err.Error()
+}
+
+func _retryable(status int) bool {
+	return status >= http.StatusInternalServerError ||
+		status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests
+}
+
+// _retryAfter parses the `Retry-After` header in either seconds or HTTP date.
+func _retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0
+	}
+
+	wait := time.Until(date)
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+func _sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
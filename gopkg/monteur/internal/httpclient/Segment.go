@@ -0,0 +1,279 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// segment is a byte range of the artifact downloaded into its own part file.
+type segment struct {
+	path    string
+	start   int64
+	end     int64
+	ignored bool
+}
+
+func (s *segment) size() int64 {
+	return s.end - s.start + 1
+}
+
+// segments is to get the byte ranges for a segmented download.
+//
+// It returns nil when the download shall be a single stream instead.
+func (d *Downloader) segments() (list []*segment) {
+	var count, size int64
+
+	if d.Segments < 2 || !d.ranges || d.single ||
+		d.indicator.downloaded != 0 {
+		return nil
+	}
+
+	count = d.indicator.total / SEGMENT_MIN_SIZE
+	if count > int64(d.Segments) {
+		count = int64(d.Segments)
+	}
+
+	if count < 2 {
+		return nil
+	}
+
+	size = d.indicator.total / count
+	list = make([]*segment, count)
+
+	for i := range list {
+		list[i] = &segment{
+			path:  d.segmentPath(i),
+			start: int64(i) * size,
+			end:   int64(i+1)*size - 1,
+		}
+	}
+
+	list[count-1].end = d.indicator.total - 1
+
+	return list
+}
+
+// attemptSegments downloads all the segments concurrently and joins them into
+// the download artifact.
+//
+// The part files are retained on error so the next attempt resumes each of
+// them.
+func (d *Downloader) attemptSegments(ctx context.Context,
+	client *http.Client,
+	list []*segment) (err error) {
+	var wg sync.WaitGroup
+	var once sync.Once
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// resume from the existing part files
+	for _, s := range list {
+		d.indicator.downloaded += s.resume()
+	}
+
+	for _, s := range list {
+		wg.Add(1)
+
+		go func(s *segment) {
+			defer wg.Done()
+
+			e := d.fetchSegment(ctx, client, s)
+			if e != nil {
+				// only the first error counts as it cancels the rest
+				once.Do(func() {
+					err = e
+					cancel()
+				})
+			}
+		}(s)
+	}
+
+	wg.Wait()
+
+	for _, s := range list {
+		if s.ignored {
+			// fall back to a single stream for the next attempts
+			d.single = true
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return d.joinSegments(list)
+}
+
+// resume is to get the downloaded size of the segment's part file.
+func (s *segment) resume() int64 {
+	fi, err := os.Stat(s.path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return 0
+	}
+
+	if fi.Size() > s.size() {
+		_ = os.Remove(s.path)
+		return 0
+	}
+
+	return fi.Size()
+}
+
+func (d *Downloader) fetchSegment(ctx context.Context,
+	client *http.Client,
+	s *segment) (err error) {
+	var response *http.Response
+	var req *http.Request
+	var f *os.File
+	var start, n int64
+
+	start = s.start + s.resume()
+	if start > s.end {
+		return nil
+	}
+
+	req, err = d.newRequest(ctx, d.request.Method, d.request.URL.String())
+	if err != nil {
+		return &remoteError{err: err}
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, s.end))
+
+	response, err = client.Do(req)
+	if err != nil {
+		return &remoteError{
+			err:   fmt.Errorf("%s: %s", ERROR_REQUEST_FAILED, err),
+			retry: ctx.Err() == nil,
+		}
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		_ = os.Remove(s.path)
+		return _statusError(response)
+	case response.StatusCode >= http.StatusBadRequest:
+		return _statusError(response)
+	case response.StatusCode != http.StatusPartialContent:
+		s.ignored = true
+		return &remoteError{
+			err: fmt.Errorf("%s: status code %d",
+				ERROR_RANGE_IGNORED,
+				response.StatusCode,
+			),
+			retry: true,
+		}
+	}
+
+	_, err = fmt.Sscanf(response.Header.Get("Content-Range"),
+		"bytes %d-",
+		&n,
+	)
+	if err != nil || n != start {
+		_ = os.Remove(s.path)
+		return &remoteError{
+			err: fmt.Errorf("%s: '%s' for %d",
+				ERROR_RANGE_MISMATCHED,
+				response.Header.Get("Content-Range"),
+				start,
+			),
+			retry: true,
+		}
+	}
+
+	f, err = os.OpenFile(s.path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		FILE_PERMISSION,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	n, err = io.Copy(f, io.TeeReader(
+		io.LimitReader(response.Body, s.end-start+1),
+		d.indicator,
+	))
+	f.Close()
+
+	switch {
+	case err != nil:
+		return &remoteError{
+			err:   fmt.Errorf("%s: %s", ERROR_REQUEST_FAILED, err),
+			retry: ctx.Err() == nil,
+		}
+	case n < s.end-start+1:
+		return &remoteError{
+			err: fmt.Errorf("%s: %d/%d bytes",
+				ERROR_DOWNLOAD_TRUNCATED,
+				n,
+				s.end-start+1,
+			),
+			retry: true,
+		}
+	}
+
+	return nil
+}
+
+// joinSegments reassembles the part files in order into the download artifact.
+func (d *Downloader) joinSegments(list []*segment) (err error) {
+	var f, part *os.File
+
+	f, err = os.OpenFile(d.Destination+EXTENSION_DOWNLOAD,
+		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
+		FILE_PERMISSION,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	for _, s := range list {
+		part, err = os.Open(s.path)
+		if err != nil {
+			return fmt.Errorf("%s: %s", ERROR_SEGMENT_JOIN_FAILED, err)
+		}
+
+		_, err = io.Copy(f, part)
+		part.Close()
+
+		if err != nil {
+			return fmt.Errorf("%s: %s", ERROR_SEGMENT_JOIN_FAILED, err)
+		}
+	}
+
+	d.removeSegments()
+
+	return nil
+}
+
+// removeSegments deletes all the part files of any segmented download.
+func (d *Downloader) removeSegments() {
+	for i := 0; i < int(d.Segments); i++ {
+		_ = os.Remove(d.segmentPath(i))
+	}
+}
+
+func (d *Downloader) segmentPath(index int) string {
+	return fmt.Sprintf("%s%s%d", d.Destination, EXTENSION_SEGMENT, index)
+}
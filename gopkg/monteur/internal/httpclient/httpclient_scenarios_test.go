@@ -0,0 +1,392 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content when:
+1. the server responds normally.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should retry and download the content when:
+1. the server responds with 503 and 502 before responding normally.
+`,
+			Switches: map[string]bool{
+				useServerError: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should retry and download the content when:
+1. the server responds with 429 and Retry-After before responding normally.
+`,
+			Switches: map[string]bool{
+				useTooManyRequests: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should resume and download the content when:
+1. the server drops the connection halfway the first time.
+`,
+			Switches: map[string]bool{
+				useTruncatedBody: true,
+				expectResumed:    true,
+			},
+		}, {
+			UID:      5,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should start over and download the content when:
+1. the server drops the connection halfway the first time.
+2. the server ignores the Range request.
+`,
+			Switches: map[string]bool{
+				useTruncatedBody: true,
+				useIgnoredRange:  true,
+				expectResumed:    true,
+			},
+		}, {
+			UID:      6,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should resume and download the content when:
+1. a previous run left a partial download behind.
+`,
+			Switches: map[string]bool{
+				useStaleArtifact: true,
+				expectResumed:    true,
+			},
+		}, {
+			UID:      7,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should report error after all attempts when:
+1. the server always responds with 500.
+2. no fallback URL is given.
+`,
+			Switches: map[string]bool{
+				useServerErrorAll: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      8,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content from fallback when:
+1. the server always responds with 500.
+2. a working fallback URL is given.
+`,
+			Switches: map[string]bool{
+				useServerErrorAll: true,
+				useFallback:       true,
+			},
+		}, {
+			UID:      9,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should report error without retrying when:
+1. the server responds with 404.
+2. no fallback URL is given.
+`,
+			Switches: map[string]bool{
+				useNotFound:         true,
+				expectError:         true,
+				expectSingleAttempt: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content from fallback when:
+1. the server responds with 404.
+2. a working fallback URL is given.
+`,
+			Switches: map[string]bool{
+				useNotFound:         true,
+				useFallback:         true,
+				expectSingleAttempt: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content when:
+1. the server is using HTTPS with its own certificate.
+2. the certificate is trusted using TLS.RootCAs.
+`,
+			Switches: map[string]bool{
+				useTLS:       true,
+				useTrustedCA: true,
+			},
+		}, {
+			UID:      12,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should report error when:
+1. the server is using HTTPS with its own certificate.
+2. the certificate is not trusted.
+`,
+			Switches: map[string]bool{
+				useTLS:      true,
+				expectError: true,
+			},
+		}, {
+			UID:      13,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content when:
+1. the server is using HTTPS with its own certificate.
+2. the certificate is not trusted.
+3. TLS verification is disabled.
+`,
+			Switches: map[string]bool{
+				useTLS:      true,
+				useInsecure: true,
+			},
+		}, {
+			UID:      14,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content through the proxy when:
+1. Proxy is given.
+2. the URL's host is only reachable through the proxy.
+`,
+			Switches: map[string]bool{
+				useProxy:      true,
+				expectProxied: true,
+			},
+		}, {
+			UID:      15,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should report error without any request when:
+1. Proxy is not a valid URL.
+`,
+			Switches: map[string]bool{
+				useBadProxy: true,
+				expectError: true,
+			},
+		}, {
+			UID:      16,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should send Basic authorization when:
+1. Auth.Username and Auth.Password are given.
+`,
+			Switches: map[string]bool{
+				useBasicAuth: true,
+			},
+		}, {
+			UID:      17,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should send Bearer authorization when:
+1. Auth.Token is given.
+`,
+			Switches: map[string]bool{
+				useBearerAuth: true,
+			},
+		}, {
+			UID:      18,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should send the host's netrc credentials when:
+1. Auth.Netrc is given.
+2. the netrc has other machines, a macro and a default entry.
+`,
+			Switches: map[string]bool{
+				useNetrc: true,
+			},
+		}, {
+			UID:      19,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should keep the credential headers when:
+1. the URL redirects to the same host.
+`,
+			Switches: map[string]bool{
+				useRedirectSameHost: true,
+			},
+		}, {
+			UID:      20,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should strip the credential headers when:
+1. the URL redirects to a different host.
+`,
+			Switches: map[string]bool{
+				useRedirectNewHost: true,
+			},
+		}, {
+			UID:      21,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content in segments when:
+1. Segments is given.
+2. the server advertises Accept-Ranges.
+3. the content is large enough for all segments.
+`,
+			Switches: map[string]bool{
+				useSegments:     true,
+				useAcceptRanges: true,
+				useLargeContent: true,
+				expectSegmented: true,
+			},
+		}, {
+			UID:      22,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should resume the segment and download the content when:
+1. Segments is given.
+2. the server advertises Accept-Ranges.
+3. the content is large enough for all segments.
+4. the server drops the connection halfway the first time.
+`,
+			Switches: map[string]bool{
+				useSegments:      true,
+				useAcceptRanges:  true,
+				useLargeContent:  true,
+				useTruncatedBody: true,
+				expectSegmented:  true,
+				expectResumed:    true,
+			},
+		}, {
+			UID:      23,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should fall back to a single stream when:
+1. Segments is given.
+2. the server advertises Accept-Ranges but ignores the Range request.
+3. the content is large enough for all segments.
+`,
+			Switches: map[string]bool{
+				useSegments:     true,
+				useAcceptRanges: true,
+				useLargeContent: true,
+				useIgnoredRange: true,
+				expectSegmented: true,
+			},
+		}, {
+			UID:      24,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content in a single stream when:
+1. Segments is given.
+2. the server does not advertise Accept-Ranges.
+3. the content is large enough for all segments.
+`,
+			Switches: map[string]bool{
+				useSegments:         true,
+				useLargeContent:     true,
+				expectSingleAttempt: true,
+			},
+		}, {
+			UID:      25,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should download the content in a single stream when:
+1. Segments is given.
+2. the server advertises Accept-Ranges.
+3. the content is too small for segments.
+`,
+			Switches: map[string]bool{
+				useSegments:         true,
+				useAcceptRanges:     true,
+				expectSingleAttempt: true,
+			},
+		}, {
+			UID:      26,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should not send any credentials to the fallback when:
+1. the server always responds with 500.
+2. Auth.Token and credential headers are given.
+3. the fallback URL is on a different host.
+`,
+			Switches: map[string]bool{
+				useServerErrorAll:  true,
+				useFallback:        true,
+				useFallbackNewHost: true,
+				useBearerAuth:      true,
+			},
+		}, {
+			UID:      27,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should not send the netrc default entry to the fallback
+when:
+1. the server always responds with 500.
+2. Auth.Netrc has no machine entry for the fallback's host.
+3. the fallback URL is on a different host.
+`,
+			Switches: map[string]bool{
+				useServerErrorAll:  true,
+				useFallback:        true,
+				useFallbackNewHost: true,
+				useNetrc:           true,
+			},
+		}, {
+			UID:      28,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should send the credentials to the fallback when:
+1. the server always responds with 500.
+2. Auth.Token is given.
+3. the fallback URL is on the same host.
+`,
+			Switches: map[string]bool{
+				useServerErrorAll: true,
+				useFallback:       true,
+				useBearerAuth:     true,
+			},
+		}, {
+			UID:      29,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should not send the credentials when:
+1. Auth.Token and sensitive Headers are given.
+2. the given URL is a mirror on another host than the Origin.
+`,
+			Switches: map[string]bool{
+				useBearerAuth:     true,
+				useMirroredOrigin: true,
+			},
+		}, {
+			UID:      30,
+			TestType: testDownload,
+			Description: `
+Downloader.Download should not send the credentials when:
+1. Auth.Username and Auth.Password are given.
+2. the given URL is a mirror on another host than the Origin.
+`,
+			Switches: map[string]bool{
+				useBasicAuth:      true,
+				useMirroredOrigin: true,
+			},
+		},
+	}
+}
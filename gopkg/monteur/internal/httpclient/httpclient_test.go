@@ -0,0 +1,540 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testDownload = "testDownload"
+)
+
+const (
+	useServerError      = "useServerError"
+	useServerErrorAll   = "useServerErrorAll"
+	useTooManyRequests  = "useTooManyRequests"
+	useTruncatedBody    = "useTruncatedBody"
+	useIgnoredRange     = "useIgnoredRange"
+	useNotFound         = "useNotFound"
+	useFallback         = "useFallback"
+	useFallbackNewHost  = "useFallbackNewHost"
+	useStaleArtifact    = "useStaleArtifact"
+	useTLS              = "useTLS"
+	useTrustedCA        = "useTrustedCA"
+	useInsecure         = "useInsecure"
+	useProxy            = "useProxy"
+	useBadProxy         = "useBadProxy"
+	useBasicAuth        = "useBasicAuth"
+	useBearerAuth       = "useBearerAuth"
+	useNetrc            = "useNetrc"
+	useRedirectSameHost = "useRedirectSameHost"
+	useRedirectNewHost  = "useRedirectNewHost"
+	useMirroredOrigin   = "useMirroredOrigin"
+	useSegments         = "useSegments"
+	useAcceptRanges     = "useAcceptRanges"
+	useLargeContent     = "useLargeContent"
+	expectError         = "expectError"
+	expectResumed       = "expectResumed"
+	expectSingleAttempt = "expectSingleAttempt"
+	expectProxied       = "expectProxied"
+	expectSegmented     = "expectSegmented"
+)
+
+const (
+	testAttempts = 3
+	testSegments = 4
+	testFilename = "artifact.bin"
+	testBadProxy = "://proxy.invalid"
+	testProxied  = "http://monteur.invalid"
+	testOrigin   = "https://origin.invalid"
+	testUsername = "monteur"
+	testPassword = "s3cr3t"
+	testToken    = "t0k3n"
+	testNetrc    = `# private mirror
+machine example.com login other password wrong
+machine 127.0.0.1
+	login monteur
+	password s3cr3t
+macdef init
+login evil password evil
+
+default login anonymous password guest
+`
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// testServer is the HTTP server injecting failures into its GET responses.
+type testServer struct {
+	mutex   sync.Mutex
+	content []byte
+
+	// failures are the status codes for the first GET requests
+	failures []int
+
+	// failAll is the status code for all GET requests
+	failAll int
+
+	// truncate is the number of first GET requests ending halfway
+	truncate int
+
+	// ignoreRange is to always respond with the whole content
+	ignoreRange bool
+
+	// acceptRanges is to advertise `Accept-Ranges: bytes`
+	acceptRanges bool
+
+	gets        int
+	proxied     int
+	ranges      []string
+	credentials []string
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+
+	s.credentials = append(s.credentials,
+		r.Header.Get("Authorization")+"|"+r.Header.Get("Private-Token"),
+	)
+
+	if r.Method == http.MethodHead {
+		if s.acceptRanges {
+			w.Header().Set("Accept-Ranges", "bytes")
+		}
+
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(s.content)))
+		s.mutex.Unlock()
+		return
+	}
+
+	s.gets++
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+
+	switch {
+	case s.failAll != 0:
+		w.WriteHeader(s.failAll)
+		s.mutex.Unlock()
+		return
+	case len(s.failures) > 0:
+		if s.failures[0] == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+
+		w.WriteHeader(s.failures[0])
+		s.failures = s.failures[1:]
+		s.mutex.Unlock()
+		return
+	}
+
+	data := s.content
+	start := 0
+	end := len(s.content) - 1
+
+	if r.Header.Get("Range") != "" && !s.ignoreRange {
+		_, _ = fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end)
+		data = s.content[start : end+1]
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d",
+			start,
+			end,
+			len(s.content),
+		))
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	}
+
+	truncate := s.truncate > 0
+	if truncate {
+		s.truncate--
+	}
+
+	// concurrent requests are served in parallel
+	s.mutex.Unlock()
+
+	if !truncate {
+		_, _ = w.Write(data)
+		return
+	}
+
+	// send half of the content then drop the connection
+	_, _ = w.Write(data[:len(data)/2])
+	w.(http.Flusher).Flush()
+
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func (s *testScenario) createContent() []byte {
+	if s.Switches[useLargeContent] {
+		// every segment gets SEGMENT_MIN_SIZE bytes and a bit more
+		data := make([]byte, testSegments*SEGMENT_MIN_SIZE+testSegments)
+		for i := range data {
+			data[i] = byte(i % 251)
+		}
+
+		return data
+	}
+
+	return bytes.Repeat([]byte("monteur resumable download "), 4096)
+}
+
+func (s *testScenario) createServer(content []byte) *testServer {
+	server := &testServer{content: content}
+
+	switch {
+	case s.Switches[useServerError]:
+		server.failures = []int{
+			http.StatusServiceUnavailable,
+			http.StatusBadGateway,
+		}
+	case s.Switches[useTooManyRequests]:
+		server.failures = []int{http.StatusTooManyRequests}
+	case s.Switches[useServerErrorAll]:
+		server.failAll = http.StatusInternalServerError
+	case s.Switches[useNotFound]:
+		server.failAll = http.StatusNotFound
+	}
+
+	if s.Switches[useTruncatedBody] {
+		server.truncate = 1
+	}
+
+	server.ignoreRange = s.Switches[useIgnoredRange]
+	server.acceptRanges = s.Switches[useAcceptRanges]
+
+	return server
+}
+
+func (s *testScenario) startServer(server *testServer) *httptest.Server {
+	if s.Switches[useTLS] {
+		return httptest.NewTLSServer(server)
+	}
+
+	return httptest.NewServer(server)
+}
+
+// configureNetwork sets the Downloader's network settings against the server
+// and returns the URL to download from.
+func (s *testScenario) configureNetwork(d *Downloader,
+	ts *httptest.Server,
+	server *testServer) (target string, stop func()) {
+	target = ts.URL + "/" + testFilename
+	stop = func() {}
+
+	switch {
+	case s.Switches[useTrustedCA]:
+		pool := x509.NewCertPool()
+		pool.AddCert(ts.Certificate())
+		d.TLS = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	case s.Switches[useInsecure]:
+		d.TLS = &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec
+			MinVersion:         tls.VersionTLS12,
+		}
+	}
+
+	switch {
+	case s.Switches[useBadProxy]:
+		d.Proxy = testBadProxy
+	case s.Switches[useProxy]:
+		// the proxy serves the content for a host that does not exist
+		proxy := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				server.mutex.Lock()
+				server.proxied++
+				server.mutex.Unlock()
+
+				server.ServeHTTP(w, r)
+			},
+		))
+
+		d.Proxy = proxy.URL
+		target = testProxied + "/" + testFilename
+		stop = proxy.Close
+	}
+
+	return target, stop
+}
+
+// configureAuth sets the Downloader's credentials and redirects the target
+// through another server when requested. It returns the URL to download from.
+func (s *testScenario) configureAuth(t *testing.T,
+	d *Downloader,
+	target string) (redirect string, stop func()) {
+	switch {
+	case s.Switches[useBasicAuth]:
+		d.Auth = &Auth{Username: testUsername, Password: testPassword}
+	case s.Switches[useBearerAuth]:
+		d.Auth = &Auth{Token: testToken}
+	case s.Switches[useNetrc]:
+		path := filepath.Join(t.TempDir(), "netrc")
+		_ = os.WriteFile(path, []byte(testNetrc), FILE_PERMISSION)
+		d.Auth = &Auth{Netrc: path}
+	}
+
+	switch {
+	case s.Switches[useRedirectNewHost]:
+		// same server under a different hostname
+		target = strings.Replace(target, "127.0.0.1", "localhost", 1)
+	case s.Switches[useRedirectSameHost]:
+	case s.Switches[useMirroredOrigin]:
+		// target is a mirror of the origin holding the credentials
+		d.Origin = testOrigin + "/" + testFilename
+		d.Headers = testHeaders()
+
+		return target, func() {}
+	case s.Switches[useFallbackNewHost]:
+		d.Headers = testHeaders()
+		return target, func() {}
+	default:
+		return target, func() {}
+	}
+
+	d.Headers = testHeaders()
+
+	server := httptest.NewServer(http.RedirectHandler(target,
+		http.StatusFound,
+	))
+
+	return server.URL + "/" + testFilename, server.Close
+}
+
+func testHeaders() map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + testToken,
+		"Private-Token": testToken,
+	}
+}
+
+func (s *testScenario) expectedCredentials() string {
+	var auth, token string
+
+	if s.Switches[useMirroredOrigin] {
+		return "|"
+	}
+
+	basic := base64.StdEncoding.EncodeToString(
+		[]byte(testUsername + ":" + testPassword),
+	)
+
+	if s.Switches[useRedirectSameHost] || s.Switches[useFallbackNewHost] {
+		auth = "Bearer " + testToken
+		token = testToken
+	}
+
+	switch {
+	case s.Switches[useBasicAuth], s.Switches[useNetrc]:
+		auth = "Basic " + basic
+	case s.Switches[useBearerAuth]:
+		auth = "Bearer " + testToken
+	}
+
+	return auth + "|" + token
+}
+
+// expectedFallbackCredentials is the credentials for the fallback server,
+// which only gets them when it shares the given URL's host.
+func (s *testScenario) expectedFallbackCredentials() string {
+	if s.Switches[useFallbackNewHost] {
+		return "|"
+	}
+
+	return s.expectedCredentials()
+}
+
+func (s *testScenario) createDownloader(t *testing.T,
+	content []byte) (d *Downloader, errs *[]error, progress *int64) {
+	errs = &[]error{}
+	progress = new(int64)
+
+	d = &Downloader{
+		Destination:     filepath.Join(t.TempDir(), testFilename),
+		CreateDirectory: true,
+		Retry: &Retry{
+			Attempts: testAttempts,
+			Delay:    time.Millisecond,
+			MaxDelay: 5 * time.Millisecond,
+		},
+		HandleError: func(err error) {
+			*errs = append(*errs, err)
+		},
+		HandleProgress: func(downloaded int64, total int64) {
+			*progress = downloaded
+		},
+	}
+
+	if s.Switches[useSegments] {
+		d.Segments = testSegments
+	}
+
+	if s.Switches[useStaleArtifact] {
+		// a previous run left a partial download behind
+		_ = os.WriteFile(d.Destination+EXTENSION_DOWNLOAD,
+			content[:len(content)/3],
+			FILE_PERMISSION,
+		)
+	}
+
+	return d, errs, progress
+}
+
+func (s *testScenario) createFallbacks(content []byte) (list []string,
+	fallback *testServer, stop func()) {
+	fallback = &testServer{content: content}
+	if !s.Switches[useFallback] {
+		return nil, fallback, func() {}
+	}
+
+	server := httptest.NewServer(fallback)
+	target := server.URL + "/" + testFilename
+
+	if s.Switches[useFallbackNewHost] {
+		// same server under a different hostname
+		target = strings.Replace(target, "127.0.0.1", "localhost", 1)
+	}
+
+	return []string{target}, fallback, server.Close
+}
+
+func (s *testScenario) assertDownload(th *thelper.THelper,
+	d *Downloader,
+	server *testServer,
+	fallback *testServer,
+	content []byte,
+	errs []error,
+	progress int64) {
+	th.ExpectSameBool("error", len(errs) > 0,
+		"expected error", s.Switches[expectError],
+	)
+
+	if !s.Switches[expectError] {
+		th.ExpectSameBool("progress", progress == int64(len(content)),
+			"expected progress", true,
+		)
+	}
+
+	segments := 0
+	for _, r := range server.ranges {
+		if strings.HasPrefix(r, "bytes=") && !strings.HasSuffix(r, "-") {
+			segments++
+		}
+	}
+
+	th.ExpectSameBool("segmented", segments >= testSegments,
+		"expected segmented", s.Switches[expectSegmented],
+	)
+
+	matches, _ := filepath.Glob(d.Destination + EXTENSION_SEGMENT + "*")
+	th.ExpectSameBool("part files", len(matches) > 0,
+		"expected part files", false,
+	)
+
+	data, _ := os.ReadFile(d.Destination)
+	if !s.Switches[expectError] {
+		th.ExpectSameBool("content", bytes.Equal(data, content),
+			"expected content", true,
+		)
+	}
+
+	// a segment only resumes when it does not start at its boundary
+	resumed := false
+	for _, r := range server.ranges {
+		start, end := 0, 0
+		n, _ := fmt.Sscanf(r, "bytes=%d-%d", &start, &end)
+
+		switch {
+		case n == 1 && start != 0:
+			resumed = true
+		case n == 2 && start%(len(content)/testSegments) != 0:
+			resumed = true
+		}
+	}
+
+	th.ExpectSameBool("resumed", resumed,
+		"expected resumed", s.Switches[expectResumed],
+	)
+
+	if s.Switches[expectSingleAttempt] {
+		th.ExpectSameBool("single attempt", server.gets == 1,
+			"expected single attempt", true,
+		)
+	}
+
+	for _, c := range server.credentials {
+		th.ExpectSameStrings("credentials", c,
+			"expected credentials", s.expectedCredentials(),
+		)
+	}
+
+	for _, c := range fallback.credentials {
+		th.ExpectSameStrings("fallback credentials", c,
+			"expected fallback credentials",
+			s.expectedFallbackCredentials(),
+		)
+	}
+
+	if s.Switches[useFallbackNewHost] {
+		th.ExpectSameBool("fallback used", fallback.gets > 0,
+			"expected fallback used", true,
+		)
+	}
+
+	th.ExpectSameBool("proxied", server.proxied > 0,
+		"expected proxied", s.Switches[expectProxied],
+	)
+
+	if s.Switches[useBadProxy] {
+		th.ExpectSameBool("no request", server.gets == 0,
+			"expected no request", true,
+		)
+	}
+
+	if s.Switches[useServerErrorAll] {
+		th.ExpectSameBool("all attempts", server.gets == testAttempts,
+			"expected all attempts", true,
+		)
+	}
+}
@@ -15,13 +15,21 @@
 
 package httpclient
 
+import (
+	"sync"
+)
+
 type indicator struct {
+	mutex          sync.Mutex
 	handleProgress func(downloaded int64, total int64)
 	total          int64
 	downloaded     int64
 }
 
 func (p *indicator) Write(data []byte) (n int, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
 	n = len(data)
 	p.downloaded += int64(n)
 	p.handleProgress(p.downloaded, p.total)
@@ -19,6 +19,7 @@ import (
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libchecksum"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/release/archiver"
 )
 
@@ -26,6 +27,7 @@ func Init(logger *liblog.Logger,
 	release *libmonteur.TOMLRelease,
 	variables map[string]interface{}) (out *archiver.Manager, err error) {
 	var app *libmonteur.Software
+	var secrets *libsecrets.Secrets
 	var ok bool
 
 	app, ok = variables[libmonteur.VAR_APP].(*libmonteur.Software)
@@ -33,8 +35,14 @@ func Init(logger *liblog.Logger,
 		panic("MONTEUR DEV: why is VAR_APP not assigned?")
 	}
 
+	secrets, ok = variables[libmonteur.VAR_SECRETS].(*libsecrets.Secrets)
+	if !ok {
+		panic("MONTEUR DEV: why is VAR_SECRETS not assigned?")
+	}
+
 	out = &archiver.Manager{
 		Log:      logger,
+		Filter:   secrets.Filter,
 		Path:     release.Target,
 		DataPath: release.Data.Path,
 		Version:  app.Version,
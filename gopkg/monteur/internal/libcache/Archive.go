@@ -0,0 +1,141 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// pack archives the outputs into a `.tar.gz` file with their paths relative
+// to the root directory. Symlinks must point inside the root directory.
+func pack(file string, root string, outputs []string) (err error) {
+	var f *os.File
+	var gz *gzip.Writer
+
+	f, err = os.Create(file)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	gz = gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	for _, output := range outputs {
+		err = _within(root, output)
+		if err != nil {
+			return err
+		}
+
+		err = filepath.Walk(output, func(path string,
+			info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			return _packFile(tw, root, path, info)
+		})
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return f.Close() //nolint:wrapcheck
+}
+
+func _packFile(tw *tar.Writer,
+	root string,
+	path string,
+	info os.FileInfo) (err error) {
+	var header *tar.Header
+	var link string
+	var f *os.File
+
+	mode := info.Mode()
+	switch {
+	case mode.IsRegular(), mode.IsDir():
+	case mode&os.ModeSymlink != 0:
+		link, _, err = archive.EvalSymlink(root, path)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		// save the target relative to the symlink's directory
+		link, err = archive.RelPath(filepath.Dir(path), link)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	default:
+		return fmt.Errorf("%s: %s", archive.ERROR_FILE_UNSUPPORTED, path)
+	}
+
+	header, err = tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	header.Name, err = archive.RelPath(root, path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = tw.WriteHeader(header)
+	if err != nil || !mode.IsRegular() {
+		return err //nolint:wrapcheck
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	_, err = io.Copy(tw, f)
+
+	return err //nolint:wrapcheck
+}
+
+func _within(root string, path string) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || rel == "." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_CACHE_OUTSIDE,
+			path,
+		)
+	}
+
+	return nil
+}
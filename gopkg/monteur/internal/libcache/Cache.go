@@ -0,0 +1,267 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package libcache is the content-addressable cache of tasks' outputs.
+//
+// Each entry is a `.tar.gz` archive of the outputs keyed by the task's inputs
+// digest together with its SHA256 checksum file. The entries are verified
+// before being restored into the workspace.
+package libcache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/targz"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libchecksum"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	// EXTENSION_CHECKSUM is the file extension of the entry's checksum.
+	EXTENSION_CHECKSUM = libmonteur.EXTENSION_SHA256
+)
+
+// Backend is the storage of the cache entries.
+type Backend interface {
+	// Get writes the object of the given key into `w`. A missing object
+	// returns `false` without error.
+	Get(key string, w io.Writer) (found bool, err error)
+
+	// Put stores the content from `r` as the object of the given key.
+	Put(key string, r io.Reader) (err error)
+}
+
+// Cache is the tasks' outputs cache of a workspace.
+//
+// Cache is safe to be created using the standard `&struct{}` method.
+type Cache struct {
+	// Backend is the entries storage
+	Backend Backend
+
+	// Root is the workspace root directory where all outputs must reside
+	Root string
+}
+
+// New is to create the Cache from the workspace's `[Cache]` settings.
+//
+// The HTTP backend is used when `URL` is set. Otherwise, the local directory
+// backend is used with `Dir` resolved against the given `root`.
+func New(settings *libmonteur.TOMLCache, root string) (c *Cache, err error) {
+	c = &Cache{Root: root}
+
+	switch {
+	case settings == nil:
+		return nil, fmt.Errorf("%s: missing settings",
+			libmonteur.ERROR_CACHE_BAD,
+		)
+	case settings.URL != "":
+		if !strings.HasPrefix(settings.URL, "http://") &&
+			!strings.HasPrefix(settings.URL, "https://") {
+			return nil, fmt.Errorf("%s: URL '%s'",
+				libmonteur.ERROR_CACHE_BAD,
+				settings.URL,
+			)
+		}
+
+		c.Backend = &HTTP{URL: settings.URL}
+	default:
+		dir := settings.Dir
+		if dir == "" {
+			dir = libmonteur.DIRECTORY_CACHE
+		}
+
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+
+		c.Backend = &Local{Dir: dir}
+	}
+
+	return c, nil
+}
+
+// Store is to archive the outputs into the cache entry of the given key.
+func (me *Cache) Store(key string, outputs []string) (err error) {
+	var sum string
+	var f *os.File
+
+	path, err := me._tempFile()
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_STORE, err)
+	}
+	defer os.Remove(path)
+
+	err = pack(path, me.Root, outputs)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_STORE, err)
+	}
+
+	sum, err = _checksum(path)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_STORE, err)
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_STORE, err)
+	}
+	defer f.Close()
+
+	err = me.Backend.Put(_entry(key), f)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_STORE, err)
+	}
+
+	// checksum is stored last so that an entry is never half available
+	err = me.Backend.Put(_entry(key)+EXTENSION_CHECKSUM,
+		strings.NewReader(sum+"\n"),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_STORE, err)
+	}
+
+	return nil
+}
+
+// Restore is to extract the cache entry of the given key into Root.
+//
+// The existing outputs are removed before extraction. A missing entry returns
+// `false` without error while a corrupted entry returns an error without
+// touching the outputs.
+func (me *Cache) Restore(key string, outputs []string) (found bool, err error) {
+	var sum strings.Builder
+	var f *os.File
+
+	found, err = me.Backend.Get(_entry(key)+EXTENSION_CHECKSUM, &sum)
+	if err != nil || !found {
+		return false, me._restoreError(err)
+	}
+
+	path, err := me._tempFile()
+	if err != nil {
+		return false, me._restoreError(err)
+	}
+	defer os.Remove(path)
+
+	f, err = os.Create(path)
+	if err != nil {
+		return false, me._restoreError(err)
+	}
+
+	found, err = me.Backend.Get(_entry(key), f)
+	f.Close()
+	if err != nil || !found {
+		return false, me._restoreError(err)
+	}
+
+	err = _verify(path, strings.TrimSpace(sum.String()))
+	if err != nil {
+		return false, me._restoreError(err)
+	}
+
+	for _, output := range outputs {
+		err = os.RemoveAll(output)
+		if err != nil {
+			return false, me._restoreError(err)
+		}
+	}
+
+	archiver := &targz.Archiver{
+		Archive:   path,
+		Raw:       me.Root,
+		Overwrite: true,
+	}
+
+	err = archiver.Extract()
+	if err != nil {
+		return false, me._restoreError(err)
+	}
+
+	return true, nil
+}
+
+func (me *Cache) _restoreError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_RESTORE, err)
+}
+
+func (me *Cache) _tempFile() (path string, err error) {
+	f, err := os.CreateTemp("", "monteur-cache-*"+targz.EXTENSION)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	path = f.Name()
+	f.Close()
+
+	return path, nil
+}
+
+func _entry(key string) string {
+	return key + targz.EXTENSION
+}
+
+func _checksum(path string) (sum string, err error) {
+	hasher, err := libchecksum.CreateChecksum(libmonteur.CHECKSUM_ALGO_SHA256)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	err = hasher.HashFile(path)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return hasher.ToHex() //nolint:wrapcheck
+}
+
+func _verify(path string, sum string) (err error) {
+	var ok bool
+	var f *os.File
+
+	hasher, err := libchecksum.CreateChecksum(libmonteur.CHECKSUM_ALGO_SHA256)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = hasher.ParseHex(sum)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_CORRUPTED, err)
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	ok, err = hasher.Compare(f)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if !ok {
+		return fmt.Errorf(libmonteur.ERROR_CACHE_CORRUPTED)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestore(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testRestore {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		backend, stop := s.createBackend(t)
+		source := t.TempDir()
+		s.createOutputs(t, source)
+
+		err := (&Cache{Backend: backend, Root: source}).Store(key,
+			s.createOutputList(source),
+		)
+		if s.Switches[useOutsideOutput] || s.Switches[useEscapeSymlink] {
+			stop()
+			th.ExpectUIDCorrectness(i, s.UID, false)
+			th.ExpectError(err, s.Switches[expectError])
+			s.log(th, map[string]interface{}{
+				"error": err,
+			})
+			th.Conclude()
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("failed to store test outputs: %s", err)
+		}
+
+		s.corrupt(backend)
+
+		target := t.TempDir()
+		stale := filepath.Join(target, staleFile)
+		_ = os.MkdirAll(filepath.Dir(stale), 0755)
+		_ = os.WriteFile(stale, []byte("stale"), 0644)
+
+		// test
+		subject := &Cache{Backend: backend, Root: target}
+		found, err := subject.Restore(s.createKey(),
+			s.createOutputList(target),
+		)
+		stop()
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertRestore(th, target, found, err)
+		s.log(th, map[string]interface{}{
+			"found": found,
+			"error": err,
+		})
+		th.Conclude()
+	}
+}
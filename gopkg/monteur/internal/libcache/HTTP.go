@@ -0,0 +1,120 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// HTTP is the cache Backend using a HTTP cache server.
+//
+// The protocol is a simple `GET` and `PUT` of `URL/key` where a `404` response
+// is a cache miss. Any `2xx` response is accepted for `PUT`.
+//
+// HTTP is safe to be created using the standard `&struct{}` method.
+type HTTP struct {
+	// Client is the HTTP client. Default is `http.DefaultClient`.
+	Client *http.Client
+
+	// Headers are the additional headers for all requests
+	Headers map[string]string
+
+	// URL is the base URL of the cache server
+	URL string
+}
+
+// Get is to download the object of the given key into `w`.
+func (me *HTTP) Get(key string, w io.Writer) (found bool, err error) {
+	response, err := me._do(http.MethodGet, key, nil)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return false, nil
+	case response.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("%s: GET %s: status code %d",
+			libmonteur.ERROR_CACHE_SERVER,
+			key,
+			response.StatusCode,
+		)
+	}
+
+	_, err = io.Copy(w, response.Body)
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+
+	return true, nil
+}
+
+// Put is to upload the content from `r` as the object of the given key.
+func (me *HTTP) Put(key string, r io.Reader) (err error) {
+	response, err := me._do(http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s: PUT %s: status code %d",
+			libmonteur.ERROR_CACHE_SERVER,
+			key,
+			response.StatusCode,
+		)
+	}
+
+	return nil
+}
+
+func (me *HTTP) _do(method string,
+	key string,
+	body io.Reader) (response *http.Response, err error) {
+	var request *http.Request
+
+	client := me.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	request, err = http.NewRequest(method,
+		strings.TrimSuffix(me.URL, "/")+"/"+key,
+		body,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_SERVER, err)
+	}
+
+	for k, v := range me.Headers {
+		request.Header.Set(k, v)
+	}
+
+	response, err = client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_CACHE_SERVER, err)
+	}
+
+	return response, nil
+}
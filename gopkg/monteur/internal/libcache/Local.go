@@ -0,0 +1,84 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcache
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// Local is the cache Backend storing the objects inside a local directory.
+//
+// Local is safe to be created using the standard `&struct{}` method.
+type Local struct {
+	// Dir is the cache directory
+	Dir string
+}
+
+// Get is to copy the object of the given key into `w`.
+func (me *Local) Get(key string, w io.Writer) (found bool, err error) {
+	f, err := os.Open(filepath.Join(me.Dir, key))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+
+	return true, nil
+}
+
+// Put is to store the content from `r` as the object of the given key.
+//
+// The object is written into a temporary file before being renamed so that a
+// partially written object is never visible.
+func (me *Local) Put(key string, r io.Reader) (err error) {
+	var f *os.File
+
+	err = os.MkdirAll(me.Dir, libmonteur.PERMISSION_DIRECTORY)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	f, err = os.CreateTemp(me.Dir, "."+key+".*")
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer os.Remove(f.Name())
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err //nolint:wrapcheck
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return os.Rename(f.Name(), filepath.Join(me.Dir, key)) //nolint:wrapcheck
+}
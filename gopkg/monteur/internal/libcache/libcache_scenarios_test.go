@@ -0,0 +1,123 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcache
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testRestore,
+			Description: `
+Cache.Restore should restore the stored outputs when:
+1. the backend is a local directory.
+2. the entry was stored with the same key.
+`,
+			Switches: map[string]bool{
+				useLocalBackend: true,
+				expectFound:     true,
+			},
+		}, {
+			UID:      2,
+			TestType: testRestore,
+			Description: `
+Cache.Restore should restore the stored outputs when:
+1. the backend is a HTTP cache server.
+2. the entry was stored with the same key.
+`,
+			Switches: map[string]bool{
+				useHTTPBackend: true,
+				expectFound:    true,
+			},
+		}, {
+			UID:      3,
+			TestType: testRestore,
+			Description: `
+Cache.Restore should report a cache miss without error when:
+1. the backend is a HTTP cache server.
+2. no entry was stored with the given key.
+`,
+			Switches: map[string]bool{
+				useHTTPBackend:  true,
+				useMissingEntry: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testRestore,
+			Description: `
+Cache.Restore should reject the entry without touching outputs when:
+1. the backend is a HTTP cache server.
+2. the archive no longer matches its checksum.
+`,
+			Switches: map[string]bool{
+				useHTTPBackend:    true,
+				useCorruptedEntry: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      5,
+			TestType: testRestore,
+			Description: `
+Cache.Restore should reject the entry without touching outputs when:
+1. the backend is a local directory.
+2. the archive no longer matches its checksum.
+`,
+			Switches: map[string]bool{
+				useLocalBackend:   true,
+				useCorruptedEntry: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      6,
+			TestType: testRestore,
+			Description: `
+Cache.Store should fail when:
+1. the backend is a local directory.
+2. an output is outside of the root directory.
+`,
+			Switches: map[string]bool{
+				useLocalBackend:  true,
+				useOutsideOutput: true,
+				expectError:      true,
+			},
+		}, {
+			UID:      7,
+			TestType: testRestore,
+			Description: `
+Cache.Restore should restore the stored outputs when:
+1. the backend is a local directory.
+2. the outputs hold a symlink relative to its own directory.
+`,
+			Switches: map[string]bool{
+				useLocalBackend: true,
+				useSymlink:      true,
+				expectFound:     true,
+			},
+		}, {
+			UID:      8,
+			TestType: testRestore,
+			Description: `
+Cache.Store should fail when:
+1. the backend is a local directory.
+2. the outputs hold a symlink escaping the root directory.
+`,
+			Switches: map[string]bool{
+				useLocalBackend:  true,
+				useEscapeSymlink: true,
+				expectError:      true,
+			},
+		},
+	}
+}
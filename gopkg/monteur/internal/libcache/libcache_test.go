@@ -0,0 +1,204 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testRestore = "testRestore"
+)
+
+const (
+	useLocalBackend = "useLocalBackend"
+	useHTTPBackend  = "useHTTPBackend"
+
+	useMissingEntry   = "useMissingEntry"
+	useCorruptedEntry = "useCorruptedEntry"
+	useOutsideOutput  = "useOutsideOutput"
+	useSymlink        = "useSymlink"
+	useEscapeSymlink  = "useEscapeSymlink"
+
+	expectError = "expectError"
+	expectFound = "expectFound"
+)
+
+const (
+	key         = "0123456789abcdef"
+	missingKey  = "fedcba9876543210"
+	outputDir   = "build"
+	outputFile  = "build/bin/app"
+	outputData  = "build/data.txt"
+	staleFile   = "build/stale.txt"
+	outputLink  = "build/bin/data.txt"
+	linkTarget  = "../data.txt"
+	fileContent = "monteur cached artifact"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// testServer is the in-memory HTTP cache server.
+type testServer struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/cache/")
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := s.objects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.objects[name] = data
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *testScenario) createBackend(t *testing.T) (backend Backend,
+	stop func()) {
+	if s.Switches[useHTTPBackend] {
+		server := httptest.NewServer(&testServer{
+			objects: map[string][]byte{},
+		})
+
+		return &HTTP{URL: server.URL + "/cache"}, server.Close
+	}
+
+	return &Local{Dir: t.TempDir()}, func() {}
+}
+
+func (s *testScenario) createOutputs(t *testing.T, root string) {
+	for _, path := range []string{outputFile, outputData} {
+		path = filepath.Join(root, path)
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(fileContent), 0644)
+		}
+
+		if err != nil {
+			t.Fatalf("failed to create test output: %s", err)
+		}
+	}
+
+	target := filepath.FromSlash(linkTarget)
+	switch {
+	case s.Switches[useEscapeSymlink]:
+		target = filepath.Join("..", "..", "..")
+	case !s.Switches[useSymlink]:
+		return
+	}
+
+	err := os.Symlink(target, filepath.Join(root, outputLink))
+	if err != nil {
+		t.Fatalf("failed to create test symlink: %s", err)
+	}
+}
+
+func (s *testScenario) createOutputList(root string) []string {
+	if s.Switches[useOutsideOutput] {
+		return []string{filepath.Join(root, "..")}
+	}
+
+	return []string{filepath.Join(root, outputDir)}
+}
+
+func (s *testScenario) createKey() string {
+	if s.Switches[useMissingEntry] {
+		return missingKey
+	}
+
+	return key
+}
+
+func (s *testScenario) corrupt(backend Backend) {
+	if !s.Switches[useCorruptedEntry] {
+		return
+	}
+
+	_ = backend.Put(_entry(key), bytes.NewReader([]byte("tampered")))
+}
+
+func (s *testScenario) assertRestore(th *thelper.THelper,
+	root string, found bool, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	th.ExpectSameBool("found", found,
+		"expected found", s.Switches[expectFound],
+	)
+
+	_, staleErr := os.Stat(filepath.Join(root, staleFile))
+	th.ExpectSameBool("stale removed", os.IsNotExist(staleErr),
+		"expected stale removed", s.Switches[expectFound],
+	)
+
+	if !s.Switches[expectFound] {
+		return
+	}
+
+	for _, path := range []string{outputFile, outputData} {
+		data, _ := os.ReadFile(filepath.Join(root, path))
+		th.ExpectSameStrings("restored "+path, string(data),
+			"expected", fileContent,
+		)
+	}
+
+	if !s.Switches[useSymlink] {
+		return
+	}
+
+	target, _ := os.Readlink(filepath.Join(root, outputLink))
+	th.ExpectSameStrings("restored symlink", target,
+		"expected", filepath.FromSlash(linkTarget),
+	)
+
+	data, _ := os.ReadFile(filepath.Join(root, outputLink))
+	th.ExpectSameStrings("restored symlink content", string(data),
+		"expected", fileContent,
+	)
+}
@@ -88,3 +88,41 @@ func ArchiverID(checksumType string) (out checksum.HashType, err error) {
 		)
 	}
 }
+
+// Parse creates a checksum hasher holding the value of the given TOMLChecksum.
+//
+// If the given checksum is `nil`, both out and err shall be `nil`. If the type,
+// format, or value is bad, err shall be returned.
+func Parse(cs *libmonteur.TOMLChecksum) (out Hasher, err error) {
+	if cs == nil {
+		return nil, nil
+	}
+
+	out, err = CreateChecksum(cs.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(cs.Format) {
+	case libmonteur.CHECKSUM_FORMAT_BASE64:
+		err = out.ParseBase64(cs.Value)
+	case libmonteur.CHECKSUM_FORMAT_BASE64_URL:
+		err = out.ParseBase64URL(cs.Value)
+	case libmonteur.CHECKSUM_FORMAT_HEX:
+		err = out.ParseHex(cs.Value)
+	default:
+		return nil, fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_CHECKSUM_FORMAT_UNKNOWN,
+			cs.Format,
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_CHECKSUM_BAD,
+			err,
+		)
+	}
+
+	return out, nil
+}
@@ -29,14 +29,17 @@ type Run struct {
 func (fx *Run) Parse(path string, varList *map[string]interface{}) (err error) {
 	// initiate working variables
 	fmtVar := &map[string]interface{}{}
+	downloads := &libmonteur.TOMLDownloads{}
 
 	// construct TOML file data structure
 	s := struct {
 		Variables    *map[string]interface{}
 		FMTVariables *map[string]interface{}
+		Downloads    *libmonteur.TOMLDownloads
 	}{
 		Variables:    varList,
 		FMTVariables: fmtVar,
+		Downloads:    downloads,
 	}
 
 	// decode
@@ -54,5 +57,7 @@ func (fx *Run) Parse(path string, varList *map[string]interface{}) (err error) {
 		return err //nolint:wrapcheck
 	}
 
+	(*varList)[libmonteur.VAR_DOWNLOADS] = downloads
+
 	return nil
 }
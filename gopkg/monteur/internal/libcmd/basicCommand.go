@@ -115,16 +115,52 @@ func (me *basicCMD) Parse(path string,
 // Run is the universal interface for Manager to execute its run.
 func (me *basicCMD) Run(ctx context.Context, ch chan conductor.Message) {
 	var err error
+	var upToDate, restored bool
 
 	me.log.Info(libmonteur.LOG_JOB_START + "\n\n")
 	me.reportUp = ch
 
+	state := &incremental{
+		metadata:  me.metadata,
+		variables: me.variables,
+		orders:    me.cmd,
+	}
+
+	upToDate, err = state.IsUpToDate()
+	if err != nil {
+		me.reportError("%s", err)
+		return
+	}
+
+	if upToDate {
+		me.reportSkip(libmonteur.LOG_JOB_UP_TO_DATE)
+		return
+	}
+
+	restored, err = state.Restore()
+	if err != nil {
+		me.log.Warning("%s", err)
+	}
+
+	if restored {
+		err = state.Save()
+		if err != nil {
+			me.reportError("%s", err)
+			return
+		}
+
+		me.reportSkip(libmonteur.LOG_JOB_RESTORED)
+		return
+	}
+
 	task := &executive{
 		log:       me.log,
 		variables: me.variables,
 		orders:    me.cmd,
 		fxSTDOUT:  me.reportOutput,
 		fxSTDERR:  me.reportStatus,
+		reportUp:  me.reportUp,
+		name:      me.metadata.Name,
 	}
 
 	err = task.Exec()
@@ -133,6 +169,17 @@ func (me *basicCMD) Run(ctx context.Context, ch chan conductor.Message) {
 		return
 	}
 
+	err = state.Save()
+	if err != nil {
+		me.reportError("%s", err)
+		return
+	}
+
+	err = state.Store()
+	if err != nil {
+		me.log.Warning("%s", err)
+	}
+
 	me.reportDone()
 }
 
@@ -156,3 +203,11 @@ func (me *basicCMD) reportOutput(format string, args ...interface{}) {
 func (me *basicCMD) reportDone() {
 	reportDone(me.log, me.reportUp, me.metadata.Name)
 }
+
+// reportSkip reports the status of a skipped task before reporting it done.
+// Unlike reportStatus, it keeps the log open for reportDone.
+func (me *basicCMD) reportSkip(status string) {
+	me.log.Info(status)
+	reportStatus(nil, me.reportUp, me.metadata.Name, status)
+	me.reportDone()
+}
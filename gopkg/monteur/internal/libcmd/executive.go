@@ -17,101 +17,155 @@ package libcmd
 
 import (
 	"fmt"
+	"time"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/commander"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libtemplater"
 )
 
 type executive struct {
 	fxSTDOUT  func(string, ...interface{})
 	fxSTDERR  func(string, ...interface{})
+	reportUp  chan conductor.Message
 	variables map[string]interface{}
 	log       *liblog.Logger
+	name      string
 	orders    []*libmonteur.TOMLAction
 }
 
 // Exec instructs the executive to run all the given commands.
 func (me *executive) Exec() (err error) {
+	var start time.Time
+
+	defer me.log.SetStep("")
+
 	for i, order := range me.orders {
 		cmd := me.create(order)
 		if cmd.Type == commander.ACTION_PLACEHOLDER {
 			continue
 		}
 
-		me.log.Info("Executing Command...")
-		me.log.Info("Name: '%s'", cmd.Name)
-		me.log.Info("Type: '%v'", cmd.Type)
+		me.log.SetStep(cmd.Name)
+		me.reportStep(conductor.CreateStep(me.name, i+1, cmd.Name))
+		start = time.Now()
 
-		me.log.Info("Formatting cmd.Location...")
-		cmd.Location, err = libtemplater.Template(order.Location,
-			me.variables,
-		)
-		if err != nil {
-			return err //nolint:wrapcheck
+		err = me.run(cmd, order, i+1)
+		elapsed := time.Since(start)
+		if err != nil && cmd.ExitCode == 0 {
+			cmd.ExitCode = -1
 		}
-		me.log.Info("Got: '%s'", cmd.Location)
 
-		me.log.Info("Formatting cmd.Source...")
-		cmd.Source, err = libtemplater.Template(order.Source,
-			me.variables,
-		)
-		if err != nil {
-			return err //nolint:wrapcheck
-		}
-		me.log.Info("Got: '%s'", cmd.Source)
+		me.reportStep(conductor.CreateStepDone(me.name,
+			i+1,
+			cmd.Name,
+			elapsed,
+			string(cmd.Type),
+			me.describe(cmd),
+			cmd.ExitCode,
+		))
+		me.log.Debug("Step %d '%s' took %s", i+1, cmd.Name, elapsed)
 
-		me.log.Info("Formatting cmd.Target...")
-		cmd.Target, err = libtemplater.Template(order.Target,
-			me.variables,
-		)
 		if err != nil {
-			return err //nolint:wrapcheck
+			return err
 		}
-		me.log.Info("Got: '%s'", cmd.Target)
+	}
 
-		me.log.Info("Processing cmd.Save...")
-		me.processSave(cmd, order)
-		me.log.Info("Got cmd.Save   : '%s'", cmd.Save)
-		me.log.Info("Got cmd.SaveFx : '%s'", cmd.SaveFx)
-		me.log.Info("Got cmd.SaveVar: '%s'", cmd.SaveVar)
+	return nil
+}
 
-		me.log.Info("Initialize cmd...")
-		err = me.initCMD(cmd)
-		if err != nil {
-			return err
-		}
-		me.log.Info(libmonteur.LOG_OK)
+func (me *executive) describe(cmd *commander.Action) string {
+	if cmd.Target == "" {
+		return cmd.Source
+	}
 
-		me.log.Info("Run cmd...")
-		err = me.exec(cmd, i+1)
-		if err != nil {
-			return err
-		}
+	return cmd.Source + " ➤ " + cmd.Target
+}
 
-		me.log.Info("formatting ToSTDOUT...")
-		order.ToSTDOUT, err = libtemplater.Template(order.ToSTDOUT,
-			me.variables,
-		)
-		if err != nil {
-			return err //nolint:wrapcheck
-		}
-		me.log.Info("Got: '%s'", order.ToSTDOUT)
-		me.report(me.fxSTDOUT, "STDOUT", order.ToSTDOUT)
+func (me *executive) reportStep(msg conductor.Message) {
+	if me.reportUp == nil {
+		return
+	}
 
-		me.log.Info("formatting ToSTDERR...")
-		order.ToSTDERR, err = libtemplater.Template(order.ToSTDERR,
-			me.variables,
-		)
-		if err != nil {
-			return err //nolint:wrapcheck
-		}
-		me.log.Info("Got: '%s'", order.ToSTDERR)
-		me.report(me.fxSTDERR, "STDERR", order.ToSTDERR)
+	me.reportUp <- msg
+}
+
+func (me *executive) run(cmd *commander.Action,
+	order *libmonteur.TOMLAction, step int) (err error) {
+	me.log.Info("Executing Command...")
+	me.log.Info("Name: '%s'", cmd.Name)
+	me.log.Info("Type: '%v'", cmd.Type)
+
+	me.log.Info("Formatting cmd.Location...")
+	cmd.Location, err = libtemplater.Template(order.Location,
+		me.variables,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	me.log.Info("Got: '%s'", cmd.Location)
+
+	me.log.Info("Formatting cmd.Source...")
+	cmd.Source, err = libtemplater.Template(order.Source,
+		me.variables,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	me.log.Info("Got: '%s'", cmd.Source)
 
-		me.log.Info("Execute Command ➤ DONE\n\n")
+	me.log.Info("Formatting cmd.Target...")
+	cmd.Target, err = libtemplater.Template(order.Target,
+		me.variables,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
+	me.log.Info("Got: '%s'", cmd.Target)
+
+	me.log.Info("Processing cmd.Save...")
+	me.processSave(cmd, order)
+	me.log.Info("Got cmd.Save   : '%s'", cmd.Save)
+	me.log.Info("Got cmd.SaveFx : '%s'", cmd.SaveFx)
+	me.log.Info("Got cmd.SaveVar: '%s'", cmd.SaveVar)
+
+	me.log.Info("Initialize cmd...")
+	err = me.initCMD(cmd)
+	if err != nil {
+		return err
+	}
+	me.log.Info(libmonteur.LOG_OK)
+
+	me.log.Info("Run cmd...")
+	err = me.exec(cmd, step)
+	if err != nil {
+		return err
+	}
+
+	me.log.Info("formatting ToSTDOUT...")
+	order.ToSTDOUT, err = libtemplater.Template(order.ToSTDOUT,
+		me.variables,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	me.log.Info("Got: '%s'", order.ToSTDOUT)
+	me.report(me.fxSTDOUT, "STDOUT", order.ToSTDOUT)
+
+	me.log.Info("formatting ToSTDERR...")
+	order.ToSTDERR, err = libtemplater.Template(order.ToSTDERR,
+		me.variables,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	me.log.Info("Got: '%s'", order.ToSTDERR)
+	me.report(me.fxSTDERR, "STDERR", order.ToSTDERR)
+
+	me.log.Info("Execute Command ➤ DONE\n\n")
 
 	return nil
 }
@@ -168,10 +222,17 @@ func (me *executive) processSave(cmd *commander.Action,
 }
 
 func (me *executive) create(cmd *libmonteur.TOMLAction) *commander.Action {
-	return &commander.Action{
+	action := &commander.Action{
 		Name: cmd.Name,
 		Type: cmd.Type,
 	}
+
+	sec, ok := me.variables[libmonteur.VAR_SECRETS].(*libsecrets.Secrets)
+	if ok && sec != nil {
+		action.Filter = sec.Writer
+	}
+
+	return action
 }
 
 func (me *executive) fxSave(key string, variable, output interface{}) {
@@ -0,0 +1,379 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libcache"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libtemplater"
+)
+
+// incremental is the content-hash based up-to-date check of a task.
+//
+// The digest covers the task's input files, its rendered commands, and its
+// variables. It is recorded into the StateDir only after a successful run and
+// is also the key of the task's outputs in the workspace's cache.
+//
+// Paths are hashed relative to the RootDir so that the same commit produces
+// the same digest from any checkout location and invocation directory.
+//
+// Only the basic jobs (test, build, compose, publish, and clean) run this
+// check. The prepare, package, release, and setup jobs always run.
+type incremental struct {
+	metadata  *libmonteur.TOMLMetadata
+	variables map[string]interface{}
+	orders    []*libmonteur.TOMLAction
+	digest    string
+}
+
+// IsUpToDate is to check the task can be skipped.
+//
+// Any previously recorded state is discarded when the task has to run so that
+// a failed run is never mistaken as up to date.
+func (me *incremental) IsUpToDate() (ok bool, err error) {
+	var data []byte
+
+	if len(me.metadata.Inputs) == 0 {
+		return false, nil
+	}
+
+	me.digest, err = me.hash()
+	if err != nil {
+		return false, err
+	}
+
+	data, err = os.ReadFile(me.path())
+	ok = err == nil && strings.TrimSpace(string(data)) == me.digest
+
+	if me._isForced() {
+		ok = false
+	}
+
+	for _, output := range me.metadata.Outputs {
+		if !ok {
+			break
+		}
+
+		_, err = os.Stat(me.resolve(output))
+		ok = err == nil
+	}
+
+	if ok {
+		return true, nil
+	}
+
+	err = os.Remove(me.path())
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("%s: %s", libmonteur.ERROR_STATE_WRITE, err)
+	}
+
+	return false, nil
+}
+
+// Save is to record the digest after a successful run.
+func (me *incremental) Save() (err error) {
+	if me.digest == "" {
+		return nil
+	}
+
+	path := me.path()
+
+	err = os.MkdirAll(filepath.Dir(path), libmonteur.PERMISSION_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_STATE_WRITE, err)
+	}
+
+	err = os.WriteFile(path,
+		[]byte(me.digest+"\n"),
+		libmonteur.PERMISSION_FILE,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_STATE_WRITE, err)
+	}
+
+	return nil
+}
+
+// Restore is to restore the outputs from the cache before running the task.
+func (me *incremental) Restore() (ok bool, err error) {
+	c, _ := me.variables[libmonteur.VAR_CACHE].(*libcache.Cache)
+
+	if c == nil || me.digest == "" || len(me.metadata.Outputs) == 0 ||
+		me._isForced() {
+		return false, nil
+	}
+
+	return c.Restore(me.digest, me._outputs()) //nolint:wrapcheck
+}
+
+// Store is to save the outputs into the cache after a successful run.
+func (me *incremental) Store() (err error) {
+	c, _ := me.variables[libmonteur.VAR_CACHE].(*libcache.Cache)
+
+	if c == nil || me.digest == "" || len(me.metadata.Outputs) == 0 {
+		return nil
+	}
+
+	return c.Store(me.digest, me._outputs()) //nolint:wrapcheck
+}
+
+func (me *incremental) _isForced() bool {
+	force, _ := me.variables[libmonteur.VAR_FORCE].(bool)
+	return force
+}
+
+func (me *incremental) _outputs() (list []string) {
+	for _, output := range me.metadata.Outputs {
+		list = append(list, me.resolve(output))
+	}
+
+	return list
+}
+
+func (me *incremental) path() string {
+	dir, _ := me.variables[libmonteur.VAR_STATE].(string)
+	job, _ := me.variables[libmonteur.VAR_JOB].(string)
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, me.metadata.Name)
+
+	return filepath.Join(dir, job, name+libmonteur.EXTENSION_SHA256)
+}
+
+func (me *incremental) hash() (digest string, err error) {
+	var files []string
+
+	h := sha256.New()
+
+	for _, pattern := range me.metadata.Inputs {
+		pattern = me.resolve(pattern)
+		fmt.Fprintf(h, "input %s\n", me.relative(pattern))
+
+		files, err = _glob(pattern)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", libmonteur.ERROR_STATE_INPUT, err)
+		}
+
+		for _, path := range files {
+			err = _hashFile(h, path, me.relative(path))
+			if err != nil {
+				return "", fmt.Errorf("%s: %s",
+					libmonteur.ERROR_STATE_INPUT,
+					err,
+				)
+			}
+		}
+	}
+
+	for _, order := range me.orders {
+		fmt.Fprintf(h, "cmd %s|%s|%s|%s|%s|%s|%s|%s\n",
+			order.Name,
+			order.Type,
+			strings.Join(order.Condition, ","),
+			me.relative(me.render(order.Location)),
+			me.relative(me.render(order.Source)),
+			me.relative(me.render(order.Target)),
+			order.Save,
+			order.SaveRegex,
+		)
+	}
+
+	me.hashVariables(h)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashVariables hashes the plain variables in sorted order. Run-specific ones
+// (e.g. timestamp, log and scratch directories), invocation-dependent ones
+// (e.g. the current directory and the user's download cache), and complex
+// objects are excluded.
+func (me *incremental) hashVariables(w io.Writer) {
+	keys := []string{}
+
+	for key := range me.variables {
+		switch key {
+		case libmonteur.VAR_DOWNLOADS,
+			libmonteur.VAR_FORCE,
+			libmonteur.VAR_HOME,
+			libmonteur.VAR_LOG,
+			libmonteur.VAR_LOG_FORMAT,
+			libmonteur.VAR_LOG_VERBOSITY,
+			libmonteur.VAR_OFFLINE,
+			libmonteur.VAR_SECRETS,
+			libmonteur.VAR_TIMESTAMP,
+			libmonteur.VAR_TMP:
+			continue
+		default:
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch v := me.variables[key].(type) {
+		case string, bool, int, int64, uint, uint64, float64,
+			[]string, []interface{}, map[string]interface{}:
+			fmt.Fprintf(w, "var %s=%s\n", key,
+				me.relative(fmt.Sprintf("%v", v)),
+			)
+		default:
+		}
+	}
+}
+
+// relative replaces every RootDir occurrence in the given text with its
+// template variable so the hash does not depend on the checkout location.
+func (me *incremental) relative(in string) string {
+	root, _ := me.variables[libmonteur.VAR_ROOT].(string)
+	if root == "" {
+		return in
+	}
+
+	return strings.ReplaceAll(in, root, "{{ ."+libmonteur.VAR_ROOT+" }}")
+}
+
+// render templates a command field. The raw value is used when it depends on
+// variables only available during the run (e.g. saved outputs).
+func (me *incremental) render(in string) string {
+	out, err := libtemplater.Template(in, me.variables)
+	if err != nil {
+		return in
+	}
+
+	return out
+}
+
+func (me *incremental) resolve(path string) string {
+	path = me.render(path)
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	root, _ := me.variables[libmonteur.VAR_ROOT].(string)
+
+	return filepath.Join(root, path)
+}
+
+// _hashFile hashes the file at path and records it under name.
+func _hashFile(w io.Writer, path string, name string) (err error) {
+	var f *os.File
+
+	h := sha256.New()
+
+	f, err = os.Open(path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	fmt.Fprintf(w, "file %s %x\n", name, h.Sum(nil))
+
+	return nil
+}
+
+// _glob expands the pattern into its sorted matching regular files where
+// `**` matches any number of directories.
+func _glob(pattern string) (list []string, err error) {
+	var matches []string
+
+	i := strings.Index(pattern, "**")
+	if i < 0 {
+		matches, err = filepath.Glob(pattern)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return _regularFiles(matches), nil
+	}
+
+	base := filepath.Clean(pattern[:i])
+	rest := strings.TrimLeft(pattern[i+2:], string(filepath.Separator))
+	if rest == "" {
+		rest = "*"
+	}
+
+	depth := len(strings.Split(rest, string(filepath.Separator)))
+
+	err = filepath.Walk(base, func(path string,
+		info os.FileInfo, walkErr error) (err error) {
+		var ok bool
+
+		switch {
+		case os.IsNotExist(walkErr):
+			return nil
+		case walkErr != nil:
+			return walkErr
+		case info.IsDir():
+			return nil
+		}
+
+		rel, _ := filepath.Rel(base, path)
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) < depth {
+			return nil
+		}
+
+		ok, err = filepath.Match(rest,
+			filepath.Join(parts[len(parts)-depth:]...),
+		)
+		if ok {
+			matches = append(matches, path)
+		}
+
+		return err //nolint:wrapcheck
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return _regularFiles(matches), nil
+}
+
+func _regularFiles(matches []string) (list []string) {
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().IsRegular() {
+			list = append(list, path)
+		}
+	}
+
+	sort.Strings(list)
+
+	return list
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcmd
+
+import (
+	"testing"
+)
+
+func TestIsUpToDate(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testIsUpToDate {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		root := s.createTree(t)
+		subject := s.createIncremental(t, root)
+
+		_, err := subject.IsUpToDate()
+		if err == nil {
+			s.createOutputs(t, root)
+			err = subject.Save()
+		}
+
+		if err != nil {
+			t.Fatalf("failed to run test task: %s", err)
+		}
+
+		s.change(t, subject)
+
+		// test
+		ok, err := subject.IsUpToDate()
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertIsUpToDate(th, subject, ok, err)
+		s.log(th, map[string]interface{}{
+			"digest": subject.digest,
+			"state":  subject.path(),
+			"error":  err,
+		})
+		th.Conclude()
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcmd
+
+import (
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testHash {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		subject := s.createIncremental(t, s.createTree(t))
+
+		before, err := subject.hash()
+		if err != nil {
+			t.Fatalf("failed to hash test task: %s", err)
+		}
+
+		s.change(t, subject)
+
+		// test
+		after, err := subject.hash()
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertHash(th, before, after, err)
+		s.log(th, map[string]interface{}{
+			"before": before,
+			"after":  after,
+			"error":  err,
+		})
+		th.Conclude()
+	}
+}
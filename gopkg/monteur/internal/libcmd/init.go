@@ -39,29 +39,27 @@ func initializeLogger(logger **liblog.Logger,
 		panic("MONTEUR DEV: please assign VAR_LOG before Parse()!")
 	}
 
-	name = strings.ToLower(name)
-	name = strings.ReplaceAll(name, " ", "-")
-	name = strings.ReplaceAll(name, "_", "-")
-	name = strings.ReplaceAll(name, "+", "-")
-	name = strings.ReplaceAll(name, "!", "")
-	name = strings.ReplaceAll(name, "$", "")
+	statusLog, outputLog := LogFilenames(name)
 
 	// initialize logger
-	*logger = &liblog.Logger{}
+	*logger = &liblog.Logger{Task: name}
 	(*logger).Init(secrets)
+	(*logger).Stage, _ = variables[libmonteur.VAR_JOB].(string)
+	(*logger).Format, _ = variables[libmonteur.VAR_LOG_FORMAT].(string)
+	(*logger).Verbosity, _ = variables[libmonteur.VAR_LOG_VERBOSITY].(string)
+
+	// full step traces are only for the terminal in debug mode
+	if (*logger).Verbosity == libmonteur.LOG_VERBOSITY_DEBUG {
+		(*logger).DebugMode = true
+		(*logger).ToTerminal = true
+	}
 
-	err = (*logger).Add(liblog.TYPE_STATUS, filepath.Join(
-		sRet,
-		name+"-"+libmonteur.FILE_LOG_STATUS,
-	))
+	err = (*logger).Add(liblog.TYPE_STATUS, filepath.Join(sRet, statusLog))
 	if err != nil {
 		return err //nolint:wrapcheck
 	}
 
-	err = (*logger).Add(liblog.TYPE_OUTPUT, filepath.Join(
-		sRet,
-		name+"-"+libmonteur.FILE_LOG_OUTPUT,
-	))
+	err = (*logger).Add(liblog.TYPE_OUTPUT, filepath.Join(sRet, outputLog))
 	if err != nil {
 		(*logger).Close()
 		return err //nolint:wrapcheck
@@ -72,6 +70,19 @@ func initializeLogger(logger **liblog.Logger,
 	return nil
 }
 
+// LogFilenames is to generate the status and output log filenames of a task.
+func LogFilenames(name string) (status string, output string) {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, " ", "-")
+	name = strings.ReplaceAll(name, "_", "-")
+	name = strings.ReplaceAll(name, "+", "-")
+	name = strings.ReplaceAll(name, "!", "")
+	name = strings.ReplaceAll(name, "$", "")
+
+	return name + "-" + libmonteur.FILE_LOG_STATUS,
+		name + "-" + libmonteur.FILE_LOG_OUTPUT
+}
+
 func initializeMonteurFS(variables map[string]interface{}) (err error) {
 	var list []string
 	var data []byte
@@ -0,0 +1,233 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcmd
+
+import (
+	"fmt"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/endec/toml"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libtemplater"
+)
+
+// Inspection is the merged variables list of a stage or a task.
+//
+// Each variable's origin layer is recorded in Origins using the same key.
+type Inspection struct {
+	Variables map[string]interface{}
+	Origins   map[string]string
+	Packages  map[string]*Inspection
+	Name      string
+	Path      string
+}
+
+// Inspector computes the merged variables list layer by layer.
+//
+// It mirrors the variables processing of Manager without initializing any
+// task (e.g. no log file or directory creation) so it is safe for read-only
+// inspection.
+type Inspector struct {
+	stage *Inspection
+
+	Job string
+}
+
+// Init sets the workspace variables as the first layer.
+func (me *Inspector) Init(variables map[string]interface{}) {
+	me.stage = &Inspection{
+		Variables: map[string]interface{}{},
+		Origins:   map[string]string{},
+	}
+
+	for k, v := range variables {
+		me.stage.Variables[k] = v
+		me.stage.Origins[k] = libmonteur.ORIGIN_WORKSPACE
+	}
+}
+
+// Settings layers the job's settings file (e.g. `build/config.toml`).
+func (me *Inspector) Settings(path string) (out *Inspection, err error) {
+	varList := map[string]interface{}{}
+	fmtVar := map[string]interface{}{}
+
+	s := struct {
+		Variables    *map[string]interface{}
+		FMTVariables *map[string]interface{}
+	}{
+		Variables:    &varList,
+		FMTVariables: &fmtVar,
+	}
+
+	err = toml.DecodeFile(path, &s, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_TOML_PARSE_FAILED,
+			err,
+		)
+	}
+
+	me.stage.Path = path
+	err = _inspectLayer(me.stage, varList, fmtVar,
+		libmonteur.ORIGIN_STAGE,
+		libmonteur.ORIGIN_STAGE_FMT,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return me.stage, nil
+}
+
+// Stage returns the stage-level Inspection.
+func (me *Inspector) Stage() *Inspection {
+	return me.stage
+}
+
+// Task layers a task file on top of the stage-level Inspection.
+func (me *Inspector) Task(path string) (out *Inspection, err error) {
+	metadata := &libmonteur.TOMLMetadata{}
+	varList := map[string]interface{}{}
+	fmtVar := map[string]interface{}{}
+	packages := map[string]*libmonteur.TOMLPackage{}
+	releases := &libmonteur.TOMLRelease{
+		Packages: map[string]*libmonteur.TOMLPackage{},
+	}
+
+	s := struct {
+		Metadata     *libmonteur.TOMLMetadata
+		Variables    *map[string]interface{}
+		FMTVariables *map[string]interface{}
+		Packages     *map[string]*libmonteur.TOMLPackage
+		Releases     *libmonteur.TOMLRelease
+	}{
+		Metadata:     metadata,
+		Variables:    &varList,
+		FMTVariables: &fmtVar,
+		Packages:     &packages,
+		Releases:     releases,
+	}
+
+	err = toml.DecodeFile(path, &s, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_TOML_PARSE_FAILED,
+			err,
+		)
+	}
+
+	out = me.stage.copy()
+	out.Name = metadata.Name
+	out.Path = path
+
+	err = _inspectLayer(out, varList, fmtVar,
+		libmonteur.ORIGIN_TASK,
+		libmonteur.ORIGIN_TASK_FMT,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	switch me.Job {
+	case libmonteur.JOB_PREPARE, libmonteur.JOB_PACKAGE:
+	case libmonteur.JOB_RELEASE:
+		packages = releases.Packages
+	default:
+		return out, nil
+	}
+
+	for k, pkg := range packages {
+		if pkg == nil || len(pkg.OS) == 0 || len(pkg.Arch) == 0 {
+			continue
+		}
+
+		out.Packages[k], err = out._inspectPackage(k, pkg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func (me *Inspection) copy() (out *Inspection) {
+	out = &Inspection{
+		Variables: map[string]interface{}{},
+		Origins:   map[string]string{},
+		Packages:  map[string]*Inspection{},
+		Name:      me.Name,
+		Path:      me.Path,
+	}
+
+	for k, v := range me.Variables {
+		out.Variables[k] = v
+	}
+
+	for k, v := range me.Origins {
+		out.Origins[k] = v
+	}
+
+	return out
+}
+
+func (me *Inspection) _inspectPackage(name string,
+	pkg *libmonteur.TOMLPackage) (out *Inspection, err error) {
+	out = me.copy()
+	out.Name = me.Name + " / " + name
+
+	err = processPackageVariables(pkg, &out.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range []string{
+		libmonteur.VAR_PACKAGE,
+		libmonteur.VAR_PACKAGE_ARCH,
+		libmonteur.VAR_PACKAGE_NAME,
+		libmonteur.VAR_PACKAGE_OS,
+		libmonteur.VAR_PACKAGE_VERSION,
+		libmonteur.VAR_PACKAGE_VERSION_DIGIT_LED,
+	} {
+		if _, ok := me.Variables[k]; ok && k == libmonteur.VAR_PACKAGE {
+			continue
+		}
+
+		out.Origins[k] = libmonteur.ORIGIN_PACKAGE + " " + name
+	}
+
+	return out, nil
+}
+
+func _inspectLayer(subject *Inspection,
+	varList map[string]interface{},
+	fmtVar map[string]interface{},
+	originVar string,
+	originFMT string) (err error) {
+	for k, v := range varList {
+		subject.Variables[k] = v
+		subject.Origins[k] = originVar
+	}
+
+	err = libtemplater.TemplateVariables(&subject.Variables, &fmtVar)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for k := range fmtVar {
+		subject.Origins[k] = originFMT
+	}
+
+	return nil
+}
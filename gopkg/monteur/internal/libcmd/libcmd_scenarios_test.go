@@ -0,0 +1,495 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcmd
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testSanitizeSources,
+			Description: `
+sanitizeSources should derive the signature URL from the URL when:
+1. no mirror is configured.
+2. the signature has neither URL nor Value.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testSanitizeSources,
+			Description: `
+sanitizeSources should mirror the derived signature URL when:
+1. a mirror matches the URL.
+2. the signature has neither URL nor Value.
+`,
+			Switches: map[string]bool{
+				useMirror: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testSanitizeSources,
+			Description: `
+sanitizeSources should mirror the given signature URL when:
+1. a mirror matches the URL.
+2. the signature URL is given explicitly.
+`,
+			Switches: map[string]bool{
+				useMirror:       true,
+				useSignatureURL: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testSanitizeSources,
+			Description: `
+sanitizeSources should leave the signature URL empty when:
+1. a mirror matches the URL.
+2. the signature is given as Value.
+`,
+			Switches: map[string]bool{
+				useMirror:         true,
+				useSignatureValue: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testSanitizeSources,
+			Description: `
+sanitizeSources should only mirror the matching URLs when:
+1. a mirror matches the URL.
+2. the fallback is hosted elsewhere.
+`,
+			Switches: map[string]bool{
+				useMirror:          true,
+				useForeignFallback: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should report no issues when:
+1. the task file is valid.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      7,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should report the missing name at its table when:
+1. the second CMD has no Name.
+`,
+			Switches: map[string]bool{
+				useMissingName: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should report the unresolved variable at its key when:
+1. a CMD Source uses an undefined variable.
+`,
+			Switches: map[string]bool{
+				useUnresolvedVariable: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should report the unknown key at its position when:
+1. the Metadata table has an unknown key.
+`,
+			Switches: map[string]bool{
+				useUnknownKey: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should only report the decoding issue when:
+1. a CMD value does not match its field type.
+`,
+			Switches: map[string]bool{
+				useTypeMismatch: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testValidatorSettings,
+			Description: `
+Validator.Settings should merge the variables without issues when:
+1. the settings file is valid.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      12,
+			TestType: testValidatorSettings,
+			Description: `
+Validator.Settings should report the unresolved variable at its key when:
+1. a FMTVariables value uses an undefined variable.
+`,
+			Switches: map[string]bool{
+				useUnresolvedVariable: true,
+			},
+		}, {
+			UID:      13,
+			TestType: testGlob,
+			Description: `
+_glob should match the regular files at any depth when:
+1. the pattern has '**' followed by a file pattern.
+2. the tree has a directory and a dangling symlink named like the files.
+`,
+			Switches: map[string]bool{
+				useNestedGlob: true,
+			},
+		}, {
+			UID:      14,
+			TestType: testGlob,
+			Description: `
+_glob should only match the files inside the named directory when:
+1. the pattern has '**' followed by a directory and file pattern.
+`,
+			Switches: map[string]bool{
+				useDirectoryGlob: true,
+			},
+		}, {
+			UID:      15,
+			TestType: testGlob,
+			Description: `
+_glob should match all the regular files below the base when:
+1. the pattern ends with '**'.
+`,
+			Switches: map[string]bool{
+				useTrailingGlob: true,
+			},
+		}, {
+			UID:      16,
+			TestType: testGlob,
+			Description: `
+_glob should only match the regular files when:
+1. the pattern has no '**'.
+2. a directory and a dangling symlink match the pattern too.
+`,
+			Switches: map[string]bool{
+				useFlatGlob: true,
+			},
+		}, {
+			UID:      17,
+			TestType: testGlob,
+			Description: `
+_glob should match nothing without error when:
+1. no file matches the pattern.
+`,
+			Switches: map[string]bool{
+				useUnmatchedGlob: true,
+			},
+		}, {
+			UID:      18,
+			TestType: testGlob,
+			Description: `
+_glob should match nothing without error when:
+1. the base directory of '**' does not exist.
+`,
+			Switches: map[string]bool{
+				useMissingBaseGlob: true,
+			},
+		}, {
+			UID:      19,
+			TestType: testHash,
+			Description: `
+incremental.hash should be stable when:
+1. nothing is changed between both digests.
+`,
+			Switches: map[string]bool{
+				expectSameDigest: true,
+			},
+		}, {
+			UID:      20,
+			TestType: testHash,
+			Description: `
+incremental.hash should change when:
+1. an input file is edited.
+`,
+			Switches: map[string]bool{
+				useInputEdit: true,
+			},
+		}, {
+			UID:      21,
+			TestType: testHash,
+			Description: `
+incremental.hash should change when:
+1. a CMD is changed.
+`,
+			Switches: map[string]bool{
+				useCMDChange: true,
+			},
+		}, {
+			UID:      22,
+			TestType: testHash,
+			Description: `
+incremental.hash should change when:
+1. a variable is changed.
+`,
+			Switches: map[string]bool{
+				useVariableChange: true,
+			},
+		}, {
+			UID:      23,
+			TestType: testHash,
+			Description: `
+incremental.hash should be stable when:
+1. only the run-specific timestamp, log and scratch variables change.
+`,
+			Switches: map[string]bool{
+				useRunVariables:  true,
+				expectSameDigest: true,
+			},
+		}, {
+			UID:      24,
+			TestType: testIsUpToDate,
+			Description: `
+incremental.IsUpToDate should skip the task when:
+1. the digest was saved after a successful run.
+2. nothing has changed and all outputs exist.
+`,
+			Switches: map[string]bool{
+				expectUpToDate: true,
+			},
+		}, {
+			UID:      25,
+			TestType: testIsUpToDate,
+			Description: `
+incremental.IsUpToDate should run the task and discard the state when:
+1. the digest was saved after a successful run.
+2. an output is missing.
+`,
+			Switches: map[string]bool{
+				useMissingOutput: true,
+			},
+		}, {
+			UID:      26,
+			TestType: testIsUpToDate,
+			Description: `
+incremental.IsUpToDate should run the task and discard the state when:
+1. the digest was saved after a successful run.
+2. the run is forced.
+`,
+			Switches: map[string]bool{
+				useForce: true,
+			},
+		}, {
+			UID:      27,
+			TestType: testIsUpToDate,
+			Description: `
+incremental.IsUpToDate should run the task and discard the state when:
+1. the digest was saved after a successful run.
+2. an input file is edited.
+`,
+			Switches: map[string]bool{
+				useInputEdit: true,
+			},
+		}, {
+			UID:      28,
+			TestType: testIsUpToDate,
+			Description: `
+incremental.IsUpToDate should always run the task when:
+1. the task has no Inputs.
+`,
+			Switches: map[string]bool{
+				useNoInputs: true,
+			},
+		}, {
+			UID:      29,
+			TestType: testHash,
+			Description: `
+incremental.hash should be stable when:
+1. the same tree is checked out at another location.
+`,
+			Switches: map[string]bool{
+				useMovedRoot:     true,
+				expectSameDigest: true,
+			},
+		}, {
+			UID:      30,
+			TestType: testHash,
+			Description: `
+incremental.hash should be stable when:
+1. monteur is invoked from another directory.
+`,
+			Switches: map[string]bool{
+				useOtherHomeDir:  true,
+				expectSameDigest: true,
+			},
+		}, {
+			UID:      31,
+			TestType: testMirrorURL,
+			Description: `
+_mirrorURL should rewrite the URL when:
+1. a mirror's Prefix matches the URL.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      32,
+			TestType: testMirrorURL,
+			Description: `
+_mirrorURL should rewrite the URL with the longest matching Prefix when:
+1. multiple mirrors' Prefix match the URL.
+`,
+			Switches: map[string]bool{
+				useLongerPrefix: true,
+			},
+		}, {
+			UID:      33,
+			TestType: testMirrorURL,
+			Description: `
+_mirrorURL should keep the URL when:
+1. no mirror's Prefix matches the URL.
+`,
+			Switches: map[string]bool{
+				useForeignURL: true,
+			},
+		}, {
+			UID:      34,
+			TestType: testMirrorURL,
+			Description: `
+_mirrorURL should keep the URL when:
+1. no mirror's Prefix matches the URL.
+2. multiple mirrors are given.
+`,
+			Switches: map[string]bool{
+				useForeignURL:   true,
+				useLongerPrefix: true,
+			},
+		}, {
+			UID:      35,
+			TestType: testInspectorTask,
+			Description: `
+Inspector.Task should record every variable's origin layer when:
+1. the job has no packages.
+2. the stage settings file is given.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      36,
+			TestType: testInspectorTask,
+			Description: `
+Inspector.Task should record every variable's origin layer when:
+1. the job has no packages.
+2. the stage settings file is absent.
+`,
+			Switches: map[string]bool{
+				useNoSettings: true,
+			},
+		}, {
+			UID:      37,
+			TestType: testInspectorTask,
+			Description: `
+Inspector.Task should layer the package variables when:
+1. the job is package.
+`,
+			Switches: map[string]bool{
+				usePackageJob: true,
+			},
+		}, {
+			UID:      38,
+			TestType: testInspectorTask,
+			Description: `
+Inspector.Task should layer the release package variables when:
+1. the job is release.
+`,
+			Switches: map[string]bool{
+				useReleaseJob: true,
+			},
+		}, {
+			UID:      39,
+			TestType: testInspectorTask,
+			Description: `
+Inspector.Task should fail when:
+1. the task file is not a valid TOML.
+`,
+			Switches: map[string]bool{
+				useBadTask:  true,
+				expectError: true,
+			},
+		}, {
+			UID:      40,
+			TestType: testInitializeLogger,
+			Description: `
+initializeLogger should leave the terminal to Conductor when:
+1. the Verbosity is quiet.
+`,
+			Switches: map[string]bool{
+				useQuiet: true,
+			},
+		}, {
+			UID:      41,
+			TestType: testInitializeLogger,
+			Description: `
+initializeLogger should leave the terminal to Conductor when:
+1. the Verbosity is normal.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      42,
+			TestType: testInitializeLogger,
+			Description: `
+initializeLogger should leave the terminal to Conductor when:
+1. the Verbosity is verbose.
+`,
+			Switches: map[string]bool{
+				useVerbose: true,
+			},
+		}, {
+			UID:      43,
+			TestType: testInitializeLogger,
+			Description: `
+initializeLogger should print the task's full traces to the terminal when:
+1. the Verbosity is debug.
+`,
+			Switches: map[string]bool{
+				useDebug:         true,
+				expectToTerminal: true,
+			},
+		}, {
+			UID:      44,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should report the undefined secret at its key when:
+1. a CMD Source gets a secret absent from the secrets files.
+2. no secret provider is configured.
+`,
+			Switches: map[string]bool{
+				useUndefinedSecret: true,
+			},
+		}, {
+			UID:      45,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should report no issues without reading the secret when:
+1. a CMD Source gets a secret defined in the secrets files.
+`,
+			Switches: map[string]bool{
+				useDefinedSecret: true,
+			},
+		}, {
+			UID:      46,
+			TestType: testValidatorTask,
+			Description: `
+Validator.Task should fail without panicking when:
+1. the job is unknown.
+`,
+			Switches: map[string]bool{
+				useUnknownJob: true,
+				expectError:   true,
+			},
+		},
+	}
+}
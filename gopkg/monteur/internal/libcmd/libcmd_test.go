@@ -0,0 +1,857 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+)
+
+const (
+	testSanitizeSources   = "testSanitizeSources"
+	testValidatorTask     = "testValidatorTask"
+	testValidatorSettings = "testValidatorSettings"
+	testGlob              = "testGlob"
+	testHash              = "testHash"
+	testIsUpToDate        = "testIsUpToDate"
+	testMirrorURL         = "testMirrorURL"
+	testInspectorTask     = "testInspectorTask"
+	testInitializeLogger  = "testInitializeLogger"
+)
+
+const (
+	useMirror          = "useMirror"
+	useSignatureURL    = "useSignatureURL"
+	useSignatureValue  = "useSignatureValue"
+	useForeignFallback = "useForeignFallback"
+	useForeignURL      = "useForeignURL"
+	useLongerPrefix    = "useLongerPrefix"
+
+	useMissingName        = "useMissingName"
+	useUnresolvedVariable = "useUnresolvedVariable"
+	useUnknownKey         = "useUnknownKey"
+	useTypeMismatch       = "useTypeMismatch"
+	useUndefinedSecret    = "useUndefinedSecret"
+	useDefinedSecret      = "useDefinedSecret"
+	useUnknownJob         = "useUnknownJob"
+
+	useNestedGlob      = "useNestedGlob"
+	useDirectoryGlob   = "useDirectoryGlob"
+	useTrailingGlob    = "useTrailingGlob"
+	useFlatGlob        = "useFlatGlob"
+	useUnmatchedGlob   = "useUnmatchedGlob"
+	useMissingBaseGlob = "useMissingBaseGlob"
+
+	useInputEdit      = "useInputEdit"
+	useCMDChange      = "useCMDChange"
+	useVariableChange = "useVariableChange"
+	useRunVariables   = "useRunVariables"
+	useMovedRoot      = "useMovedRoot"
+	useOtherHomeDir   = "useOtherHomeDir"
+	useMissingOutput  = "useMissingOutput"
+	useForce          = "useForce"
+	useNoInputs       = "useNoInputs"
+	expectSameDigest  = "expectSameDigest"
+	expectUpToDate    = "expectUpToDate"
+
+	useNoSettings = "useNoSettings"
+	usePackageJob = "usePackageJob"
+	useReleaseJob = "useReleaseJob"
+	useBadTask    = "useBadTask"
+	expectError   = "expectError"
+
+	useQuiet         = "useQuiet"
+	useVerbose       = "useVerbose"
+	useDebug         = "useDebug"
+	expectToTerminal = "expectToTerminal"
+)
+
+const (
+	testSystem    = "linux-amd64"
+	testOrigin    = "https://example.com/"
+	testMirror    = "https://mirror.example.net/cache/"
+	testForeign   = "https://foreign.example.org/"
+	testBackup    = "https://backup.example.net/"
+	testArchive   = "tool.tar.gz"
+	testSignature = "sigs/tool.tar.gz.minisig"
+	testKey       = "keys/minisign.pub"
+)
+
+// testTask is the build task file for the validator scenarios. Its line
+// numbers are used as the expected positions so keep them in sync when editing.
+const testTask = `[Metadata]
+Name = "Build"
+Description = "test build"
+
+[Variables]
+Output = "bin"
+
+[[CMD]]
+Name = "Compile"
+Type = "command"
+Condition = [ "all-all" ]
+Source = "go build -o {{ .Output }}"
+`
+
+// testSettings is the build settings file for the validator scenarios.
+const testSettings = `[Variables]
+Output = "bin"
+
+[FMTVariables]
+Target = "{{ .Output }}/app"
+`
+
+// testInspectSettings is the stage settings file for the inspector scenarios.
+const testInspectSettings = `[Variables]
+Stage = "stage"
+Task = "stage"
+
+[FMTVariables]
+StageFMT = "{{ .Stage }}/fmt"
+`
+
+// testInspectTask is the task file for the inspector scenarios. Its package is
+// declared for both the package and release jobs.
+const testInspectTask = `[Metadata]
+Name = "Inspect"
+
+[Variables]
+Task = "task"
+
+[FMTVariables]
+TaskFMT = "{{ .Task }}/{{ .StageFMT }}"
+
+[Packages.linux-amd64]
+OS = [ "linux" ]
+Arch = [ "amd64" ]
+
+[Releases.Packages.linux-amd64]
+OS = [ "linux" ]
+Arch = [ "amd64" ]
+`
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createSources() map[string]*libmonteur.TOMLSource {
+	source := &libmonteur.TOMLSource{
+		Archive:   testArchive,
+		Method:    "GET",
+		URL:       testOrigin + testArchive,
+		Fallbacks: []string{testOrigin + "backup/" + testArchive},
+		Signature: &libmonteur.TOMLSignature{
+			Type: libmonteur.PROGRAM_SIGNATURE_MINISIGN,
+			Keys: []string{testKey},
+		},
+	}
+
+	if s.Switches[useForeignFallback] {
+		source.Fallbacks = []string{testForeign + testArchive}
+	}
+
+	switch {
+	case s.Switches[useSignatureURL]:
+		source.Signature.URL = testOrigin + testSignature
+	case s.Switches[useSignatureValue]:
+		source.Signature.Value = "untrusted comment: signature"
+	}
+
+	return map[string]*libmonteur.TOMLSource{
+		libmonteur.COMPUTE_SYSTEM_OMNI: source,
+	}
+}
+
+func (s *testScenario) createVariables(t *testing.T) map[string]interface{} {
+	secrets := &libsecrets.Secrets{}
+
+	err := secrets.Parse(nil)
+	if err != nil {
+		t.Fatalf("failed to create test secrets: %s", err)
+	}
+
+	variables := map[string]interface{}{
+		libmonteur.VAR_ROOT:    "/repo",
+		libmonteur.VAR_SECRETS: secrets,
+	}
+
+	if s.Switches[useMirror] {
+		variables[libmonteur.VAR_MIRRORS] = []*libmonteur.TOMLMirror{
+			{Prefix: testOrigin, URL: testMirror},
+		}
+	}
+
+	return variables
+}
+
+// expectURL is to get the expected URL after mirroring the given origin URL.
+func (s *testScenario) expectURL(url string) string {
+	if s.Switches[useMirror] && len(url) >= len(testOrigin) &&
+		url[:len(testOrigin)] == testOrigin {
+		return testMirror + url[len(testOrigin):]
+	}
+
+	return url
+}
+
+func (s *testScenario) assertSanitizeSources(th *thelper.THelper,
+	sources map[string]*libmonteur.TOMLSource,
+	out *libmonteur.TOMLSource,
+	err error) {
+	th.ExpectError(err, false)
+	if out == nil {
+		return
+	}
+
+	source := sources[libmonteur.COMPUTE_SYSTEM_OMNI]
+	origin := testOrigin + testArchive
+
+	th.ExpectSameStrings("URL", out.URL,
+		"expected URL", s.expectURL(origin),
+	)
+	th.ExpectSameStrings("Origin", out.OriginURL(),
+		"expected Origin", origin,
+	)
+
+	fallback := testOrigin + "backup/" + testArchive
+	if s.Switches[useForeignFallback] {
+		fallback = testForeign + testArchive
+	}
+
+	th.ExpectSameStrings("Fallback", out.Fallbacks[0],
+		"expected Fallback", s.expectURL(fallback),
+	)
+
+	signature := origin + libmonteur.EXTENSION_MINISIG
+	switch {
+	case s.Switches[useSignatureURL]:
+		signature = testOrigin + testSignature
+	case s.Switches[useSignatureValue]:
+		signature = ""
+	}
+
+	th.ExpectSameStrings("Signature URL", source.Signature.URL,
+		"expected Signature URL", s.expectURL(signature),
+	)
+	th.ExpectSameStrings("Signature Key", source.Signature.Keys[0],
+		"expected Signature Key", "/repo/"+testKey,
+	)
+}
+
+// createMirrorURL is to get the mirrors alongside the URL to rewrite and its
+// expected result.
+func (s *testScenario) createMirrorURL() (mirrors []*libmonteur.TOMLMirror,
+	url string,
+	expect string) {
+	mirrors = []*libmonteur.TOMLMirror{
+		{Prefix: testOrigin, URL: testMirror},
+	}
+	url = testOrigin + "backup/" + testArchive
+	expect = testMirror + "backup/" + testArchive
+
+	if s.Switches[useLongerPrefix] {
+		mirrors = append(mirrors, &libmonteur.TOMLMirror{
+			Prefix: testOrigin + "backup/",
+			URL:    testBackup,
+		})
+		expect = testBackup + testArchive
+	}
+
+	if s.Switches[useForeignURL] {
+		url = testForeign + testArchive
+		expect = url
+	}
+
+	return mirrors, url, expect
+}
+
+func (s *testScenario) assertMirrorURL(th *thelper.THelper,
+	url string,
+	expect string) {
+	th.ExpectSameStrings("URL", url, "expected URL", expect)
+}
+
+// createInspection layers the inspector scenario's files on top of its
+// workspace variables.
+func (s *testScenario) createInspection(t *testing.T) (out *Inspection,
+	err error) {
+	dir := t.TempDir()
+	settings := filepath.Join(dir, "config.toml")
+	task := filepath.Join(dir, "task.toml")
+
+	doc := testInspectTask
+	if s.Switches[useBadTask] {
+		doc += "\n[Variables]\n"
+	}
+
+	err = os.WriteFile(settings, []byte(testInspectSettings), 0600)
+	if err == nil {
+		err = os.WriteFile(task, []byte(doc), 0600)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test inspector files: %s", err)
+	}
+
+	subject := &Inspector{Job: libmonteur.JOB_BUILD}
+	switch {
+	case s.Switches[usePackageJob]:
+		subject.Job = libmonteur.JOB_PACKAGE
+	case s.Switches[useReleaseJob]:
+		subject.Job = libmonteur.JOB_RELEASE
+	}
+
+	variables := s.createVariables(t)
+	variables[libmonteur.VAR_APP] = &libmonteur.Software{
+		Name:    "Monteur",
+		Version: "1.2.3",
+	}
+	variables[libmonteur.VAR_TMP] = "/repo/.monteurFS/tmp"
+	variables["Workspace"] = "workspace"
+	variables["Stage"] = "workspace"
+	variables["Task"] = "workspace"
+	subject.Init(variables)
+
+	if !s.Switches[useNoSettings] {
+		_, err = subject.Settings(settings)
+		if err != nil {
+			t.Fatalf("failed to inspect test settings: %s", err)
+		}
+	}
+
+	return subject.Task(task)
+}
+
+// expectInspection is to get the expected `value ⟵ origin` of the scenario's
+// variables.
+func (s *testScenario) expectInspection() (expect map[string]string) {
+	expect = map[string]string{
+		"Workspace": "workspace ⟵ " + libmonteur.ORIGIN_WORKSPACE,
+		"Stage":     "stage ⟵ " + libmonteur.ORIGIN_STAGE,
+		"StageFMT":  "stage/fmt ⟵ " + libmonteur.ORIGIN_STAGE_FMT,
+		"Task":      "task ⟵ " + libmonteur.ORIGIN_TASK,
+		"TaskFMT":   "task/stage/fmt ⟵ " + libmonteur.ORIGIN_TASK_FMT,
+	}
+
+	if s.Switches[useNoSettings] {
+		expect["Stage"] = "workspace ⟵ " + libmonteur.ORIGIN_WORKSPACE
+		delete(expect, "StageFMT")
+		expect["TaskFMT"] = "task/<no value> ⟵ " +
+			libmonteur.ORIGIN_TASK_FMT
+	}
+
+	return expect
+}
+
+func (s *testScenario) assertInspection(th *thelper.THelper,
+	out *Inspection,
+	err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	th.ExpectSameBool("inspection", out != nil,
+		"expected inspection", !s.Switches[expectError],
+	)
+
+	if out == nil {
+		return
+	}
+
+	expect := s.expectInspection()
+	th.ExpectSameStrings("variables", _listInspection(out, expect),
+		"expected variables", _listExpectation(expect),
+	)
+
+	pkg, ok := out.Packages["linux-amd64"]
+	th.ExpectSameBool("package", ok,
+		"expected package",
+		s.Switches[usePackageJob] || s.Switches[useReleaseJob],
+	)
+
+	if !ok {
+		return
+	}
+
+	origin := libmonteur.ORIGIN_PACKAGE + " linux-amd64"
+	expect[libmonteur.VAR_PACKAGE_OS] = "linux ⟵ " + origin
+	expect[libmonteur.VAR_PACKAGE_ARCH] = "amd64 ⟵ " + origin
+	expect[libmonteur.VAR_PACKAGE_VERSION] = "1-2-3 ⟵ " + origin
+
+	th.ExpectSameStrings("package variables", _listInspection(pkg, expect),
+		"expected package variables", _listExpectation(expect),
+	)
+	th.ExpectSameStrings("package name", pkg.Name,
+		"expected package name", "Inspect / linux-amd64",
+	)
+}
+
+func _listInspection(out *Inspection, expect map[string]string) string {
+	list := []string{}
+
+	for k := range expect {
+		list = append(list, fmt.Sprintf("%s = %v ⟵ %s",
+			k,
+			out.Variables[k],
+			out.Origins[k],
+		))
+	}
+
+	sort.Strings(list)
+
+	return strings.Join(list, "\n")
+}
+
+func _listExpectation(expect map[string]string) string {
+	list := []string{}
+
+	for k, v := range expect {
+		list = append(list, k+" = "+v)
+	}
+
+	sort.Strings(list)
+
+	return strings.Join(list, "\n")
+}
+
+// createConfig writes the config file of the scenario and returns the expected
+// issue key alongside its position.
+func (s *testScenario) createConfig(t *testing.T) (path string,
+	key string,
+	line int,
+	column int) {
+	doc := testTask
+	if s.TestType == testValidatorSettings {
+		doc = testSettings
+	}
+
+	switch {
+	case s.Switches[useMissingName]:
+		doc += "\n[[CMD]]\nType = \"command\"\n" +
+			"Condition = [ \"all-all\" ]\nSource = \"true\"\n"
+		key, line, column = "CMD[1].Name", 14, 1
+	case s.Switches[useUnresolvedVariable] &&
+		s.TestType == testValidatorSettings:
+		doc = strings.Replace(doc, "/app", "/{{ .Missing }}", 1)
+		key, line, column = "FMTVariables.Target", 5, 1
+	case s.Switches[useUnresolvedVariable]:
+		doc = strings.Replace(doc, ".Output", ".Missing", 1)
+		key, line, column = "CMD[0].Source", 12, 1
+	case s.Switches[useUnknownKey]:
+		doc = strings.Replace(doc, "Description",
+			"Extra = 1\nDescription", 1,
+		)
+		key, line, column = "Metadata.Extra", 3, 1
+	case s.Switches[useTypeMismatch]:
+		doc = strings.Replace(doc, `"all-all" ]`, `"all-all" ]`+"\n"+
+			"Target = 1", 1)
+		key, line, column = "Target", 12, 1
+	case s.Switches[useUndefinedSecret], s.Switches[useDefinedSecret]:
+		doc = strings.Replace(doc, `}}"`,
+			`}} {{ GetSecret \"Token\" }}"`, 1,
+		)
+
+		if s.Switches[useUndefinedSecret] {
+			key, line, column = "CMD[0].Source", 12, 1
+		}
+	}
+
+	path = filepath.Join(t.TempDir(), "config.toml")
+
+	err := os.WriteFile(path, []byte(doc), 0600)
+	if err != nil {
+		t.Fatalf("failed to create test config: %s", err)
+	}
+
+	return path, key, line, column
+}
+
+func (s *testScenario) createValidator(t *testing.T) *Validator {
+	variables := s.createVariables(t)
+	secrets, _ := variables[libmonteur.VAR_SECRETS].(*libsecrets.Secrets)
+
+	if s.Switches[useDefinedSecret] {
+		path := filepath.Join(t.TempDir(), "secrets.toml")
+
+		err := os.WriteFile(path, []byte("Token = \"abcdefgh\"\n"), 0600)
+		if err != nil {
+			t.Fatalf("failed to create test secrets file: %s", err)
+		}
+
+		err = secrets.Parse([]string{path})
+		if err != nil {
+			t.Fatalf("failed to parse test secrets file: %s", err)
+		}
+	}
+
+	job := libmonteur.JOB_BUILD
+	if s.Switches[useUnknownJob] {
+		job = "unknown"
+	}
+
+	return &Validator{
+		Job:       job,
+		Variables: map[string]interface{}{},
+		Secrets:   secrets.Session(),
+	}
+}
+
+func (s *testScenario) assertValidator(th *thelper.THelper,
+	subject *Validator,
+	list []*Issue,
+	key string,
+	line int,
+	column int,
+	err error) {
+	th.ExpectError(err, s.Switches[expectError])
+
+	// the secret values are never read
+	th.ExpectSameBool("queried secrets",
+		len(subject.Secrets.Queried()) == 0,
+		"expected queried secrets", true,
+	)
+
+	if s.Switches[expectError] {
+		return
+	}
+
+	if line == 0 {
+		th.ExpectSameBool("issues", len(list) == 0,
+			"expected issues", true,
+		)
+	} else {
+		th.ExpectSameBool("issues", len(list) == 1,
+			"expected issues", true,
+		)
+	}
+
+	if len(list) == 1 {
+		th.ExpectSameBool("message", strings.Contains(list[0].Message,
+			strings.TrimPrefix(key, "Metadata.")),
+			"expected message", true,
+		)
+		th.ExpectSameBool("position", list[0].Line == line &&
+			list[0].Column == column,
+			"expected position", true,
+		)
+		th.ExpectSameBool("string", strings.HasPrefix(list[0].String(),
+			fmt.Sprintf("%s:%d:%d: ", list[0].Path, line, column)),
+			"expected string", true,
+		)
+	}
+
+	if s.TestType != testValidatorSettings {
+		return
+	}
+
+	_, ok := subject.Variables["Target"]
+	th.ExpectSameBool("merged variables", ok &&
+		subject.Variables["Output"] == "bin",
+		"expected merged variables", true,
+	)
+}
+
+// createTree creates the source tree for the incremental scenarios.
+//
+// Besides the Go files, it has a `.txt` file, a directory and a dangling
+// symlink named like Go files, and a symlink to a Go file.
+func (s *testScenario) createTree(t *testing.T) (root string) {
+	root = t.TempDir()
+
+	for _, path := range []string{
+		"src/main.go",
+		"src/pkg/lib.go",
+		"src/pkg/deep/deep.go",
+		"src/pkg/deep/notes.txt",
+	} {
+		content := []byte("package " + path)
+		path = filepath.Join(root, path)
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = os.WriteFile(path, content, 0644)
+		}
+
+		if err != nil {
+			t.Fatalf("failed to create test tree: %s", err)
+		}
+	}
+
+	err := os.Mkdir(filepath.Join(root, "src", "dir.go"), 0755)
+	if err == nil {
+		err = os.Symlink(filepath.Join(root, "missing.go"),
+			filepath.Join(root, "src", "broken.go"),
+		)
+	}
+
+	if err == nil {
+		err = os.Symlink(filepath.Join("..", "main.go"),
+			filepath.Join(root, "src", "pkg", "link.go"),
+		)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test tree: %s", err)
+	}
+
+	return root
+}
+
+// createGlob is to get the glob pattern alongside its expected matches.
+func (s *testScenario) createGlob(root string) (pattern string,
+	expect []string) {
+	switch {
+	case s.Switches[useNestedGlob]:
+		pattern = "src/**/*.go"
+		expect = []string{
+			"src/main.go",
+			"src/pkg/deep/deep.go",
+			"src/pkg/lib.go",
+			"src/pkg/link.go",
+		}
+	case s.Switches[useDirectoryGlob]:
+		pattern = "src/**/deep/*.go"
+		expect = []string{"src/pkg/deep/deep.go"}
+	case s.Switches[useTrailingGlob]:
+		pattern = "src/pkg/**"
+		expect = []string{
+			"src/pkg/deep/deep.go",
+			"src/pkg/deep/notes.txt",
+			"src/pkg/lib.go",
+			"src/pkg/link.go",
+		}
+	case s.Switches[useFlatGlob]:
+		pattern = "src/*.go"
+		expect = []string{"src/main.go"}
+	case s.Switches[useUnmatchedGlob]:
+		pattern = "src/**/*.rs"
+	case s.Switches[useMissingBaseGlob]:
+		pattern = "missing/**/*.go"
+	}
+
+	for i, v := range expect {
+		expect[i] = filepath.Join(root, v)
+	}
+
+	return filepath.Join(root, pattern), expect
+}
+
+func (s *testScenario) assertGlob(th *thelper.THelper,
+	list []string,
+	expect []string,
+	err error) {
+	th.ExpectError(err, false)
+	th.ExpectSameStrings("matches", strings.Join(list, "\n"),
+		"expected matches", strings.Join(expect, "\n"),
+	)
+}
+
+func (s *testScenario) createIncremental(t *testing.T,
+	root string) *incremental {
+	variables := s.createVariables(t)
+	variables[libmonteur.VAR_ROOT] = root
+	variables[libmonteur.VAR_HOME] = root
+	variables[libmonteur.VAR_STATE] = filepath.Join(root, ".state")
+	variables[libmonteur.VAR_JOB] = libmonteur.JOB_BUILD
+	variables[libmonteur.VAR_TIMESTAMP] = "2022-01-01"
+	variables[libmonteur.VAR_TMP] = filepath.Join(root, ".tmp", "1")
+	variables[libmonteur.VAR_LOG] = filepath.Join(root, ".log", "1")
+	variables["Output"] = "bin/app"
+
+	metadata := &libmonteur.TOMLMetadata{
+		Name:    "Build App",
+		Inputs:  []string{"src/**/*.go"},
+		Outputs: []string{"{{ .Output }}"},
+	}
+
+	if s.Switches[useNoInputs] {
+		metadata.Inputs = nil
+	}
+
+	return &incremental{
+		metadata:  metadata,
+		variables: variables,
+		orders: []*libmonteur.TOMLAction{
+			{
+				Name:      "Compile",
+				Type:      "command",
+				Condition: []string{"all-all"},
+				Source:    "go build -o {{ .Output }}",
+			},
+		},
+	}
+}
+
+// change is to apply the scenario's changes after the first digest.
+func (s *testScenario) change(t *testing.T, subject *incremental) {
+	var err error
+
+	root, _ := subject.variables[libmonteur.VAR_ROOT].(string)
+
+	switch {
+	case s.Switches[useInputEdit]:
+		err = os.WriteFile(filepath.Join(root, "src", "pkg", "lib.go"),
+			[]byte("package edited"),
+			0644,
+		)
+	case s.Switches[useCMDChange]:
+		subject.orders[0].Source = "go build -race -o {{ .Output }}"
+	case s.Switches[useVariableChange]:
+		subject.variables["Output"] = "bin/app2"
+	case s.Switches[useRunVariables]:
+		subject.variables[libmonteur.VAR_TIMESTAMP] = "2022-01-02"
+		subject.variables[libmonteur.VAR_TMP] = filepath.Join(root,
+			".tmp", "2",
+		)
+		subject.variables[libmonteur.VAR_LOG] = filepath.Join(root,
+			".log", "2",
+		)
+	case s.Switches[useMovedRoot]:
+		err = s.moveRoot(subject, root+"-moved")
+	case s.Switches[useOtherHomeDir]:
+		subject.variables[libmonteur.VAR_HOME] = filepath.Join(root,
+			"src",
+		)
+	case s.Switches[useMissingOutput]:
+		err = os.Remove(filepath.Join(root, "bin", "app"))
+	case s.Switches[useForce]:
+		subject.variables[libmonteur.VAR_FORCE] = true
+	}
+
+	if err != nil {
+		t.Fatalf("failed to change test task: %s", err)
+	}
+}
+
+// moveRoot moves the test tree to another checkout location.
+func (s *testScenario) moveRoot(subject *incremental, root string) error {
+	old, _ := subject.variables[libmonteur.VAR_ROOT].(string)
+
+	for _, key := range []string{
+		libmonteur.VAR_ROOT,
+		libmonteur.VAR_HOME,
+		libmonteur.VAR_STATE,
+		libmonteur.VAR_TMP,
+		libmonteur.VAR_LOG,
+	} {
+		path, _ := subject.variables[key].(string)
+		subject.variables[key] = root + strings.TrimPrefix(path, old)
+	}
+
+	return os.Rename(old, root)
+}
+
+// createOutputs creates the task outputs as if the task was run.
+func (s *testScenario) createOutputs(t *testing.T, root string) {
+	path := filepath.Join(root, "bin", "app")
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		err = os.WriteFile(path, []byte("app"), 0755)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test outputs: %s", err)
+	}
+}
+
+func (s *testScenario) assertHash(th *thelper.THelper,
+	before string,
+	after string,
+	err error) {
+	th.ExpectError(err, false)
+	th.ExpectSameBool("digest", before != "" && after != "",
+		"expected digest", true,
+	)
+	th.ExpectSameBool("same digest", before == after,
+		"expected same digest", s.Switches[expectSameDigest],
+	)
+}
+
+func (s *testScenario) assertIsUpToDate(th *thelper.THelper,
+	subject *incremental,
+	ok bool,
+	err error) {
+	th.ExpectError(err, false)
+	th.ExpectSameBool("up to date", ok,
+		"expected up to date", s.Switches[expectUpToDate],
+	)
+
+	_, statErr := os.Stat(subject.path())
+	th.ExpectSameBool("state kept", statErr == nil,
+		"expected state kept", s.Switches[expectUpToDate],
+	)
+}
+
+// createTaskLogger initializes the task logger with the scenario's
+// Verbosity.
+func (s *testScenario) createTaskLogger(t *testing.T) (log *liblog.Logger,
+	err error) {
+	variables := s.createVariables(t)
+	variables[libmonteur.VAR_LOG] = t.TempDir()
+	variables[libmonteur.VAR_JOB] = libmonteur.JOB_BUILD
+	variables[libmonteur.VAR_LOG_VERBOSITY] = libmonteur.LOG_VERBOSITY_NORMAL
+
+	switch {
+	case s.Switches[useQuiet]:
+		variables[libmonteur.VAR_LOG_VERBOSITY] =
+			libmonteur.LOG_VERBOSITY_QUIET
+	case s.Switches[useVerbose]:
+		variables[libmonteur.VAR_LOG_VERBOSITY] =
+			libmonteur.LOG_VERBOSITY_VERBOSE
+	case s.Switches[useDebug]:
+		variables[libmonteur.VAR_LOG_VERBOSITY] =
+			libmonteur.LOG_VERBOSITY_DEBUG
+	}
+
+	secrets, _ := variables[libmonteur.VAR_SECRETS].(*libsecrets.Secrets)
+
+	err = initializeLogger(&log, "Build App", variables, secrets)
+	if log != nil {
+		t.Cleanup(log.Close)
+	}
+
+	return log, err
+}
+
+func (s *testScenario) assertTaskLogger(th *thelper.THelper,
+	log *liblog.Logger,
+	err error) {
+	th.ExpectError(err, false)
+	if log == nil {
+		return
+	}
+
+	th.ExpectSameBool("to terminal", log.ToTerminal,
+		"expected to terminal", s.Switches[expectToTerminal],
+	)
+	th.ExpectSameBool("debug mode", log.DebugMode,
+		"expected debug mode", s.Switches[expectToTerminal],
+	)
+}
@@ -27,6 +27,7 @@ func reportError(log *liblog.Logger,
 	format string, args ...interface{}) {
 	if log != nil {
 		log.Error(format, args...)
+		log.SecretsAudit()
 		log.Sync()
 		log.Close()
 	}
@@ -42,6 +43,7 @@ func reportOutput(log *liblog.Logger,
 	format string, args ...interface{}) {
 	if log != nil {
 		log.Output(format, args...)
+		log.SecretsAudit()
 		log.Sync()
 		log.Close()
 	}
@@ -57,6 +59,7 @@ func reportStatus(log *liblog.Logger,
 	format string, args ...interface{}) {
 	if log != nil {
 		log.Info(format, args...)
+		log.SecretsAudit()
 		log.Sync()
 		log.Close()
 	}
@@ -68,6 +71,7 @@ func reportStatus(log *liblog.Logger,
 
 func reportDone(log *liblog.Logger, ch chan conductor.Message, name string) {
 	if log != nil {
+		log.SecretsAudit()
 		log.Success(libmonteur.LOG_DONE + "\n")
 		log.Sync()
 		log.Close()
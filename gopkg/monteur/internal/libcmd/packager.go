@@ -174,6 +174,8 @@ func (me *packager) _runCMD(variables map[string]interface{}) (err error) {
 		orders:    me.cmd,
 		fxSTDOUT:  me.reportOutput,
 		fxSTDERR:  me.reportStatus,
+		reportUp:  me.reportUp,
+		name:      me.metadata.Name,
 	}
 
 	err = task.Exec()
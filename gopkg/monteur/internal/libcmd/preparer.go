@@ -229,6 +229,8 @@ func (me *preparer) _runCMD(variables map[string]interface{}) (err error) {
 		orders:    me.cmd,
 		fxSTDOUT:  me.reportOutput,
 		fxSTDERR:  me.reportStatus,
+		reportUp:  me.reportUp,
+		name:      me.metadata.Name,
 	}
 
 	err = task.Exec()
@@ -255,6 +255,8 @@ func (me *releaser) runManually(variables map[string]interface{}) (err error) {
 		orders:    me.cmd,
 		fxSTDOUT:  me.reportOutput,
 		fxSTDERR:  me.reportStatus,
+		reportUp:  me.reportUp,
+		name:      me.metadata.Name,
 	}
 
 	err = task.Exec()
@@ -17,6 +17,7 @@ package libcmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/commander"
@@ -208,11 +209,28 @@ func sanitizeSources(sources map[string]*libmonteur.TOMLSource,
 		return err
 	}
 
+	// signature URL is derived from the origin URL so it is mirrored
+	// together with the URL and Fallbacks afterward
+	err = sanitizeSourceSignature(*out, variables)
+	if err != nil {
+		return err
+	}
+
+	err = sanitizeSourceMirror(*out, variables)
+	if err != nil {
+		return err
+	}
+
 	err = sanitizeSourceHeaders(*out, variables)
 	if err != nil {
 		return err
 	}
 
+	err = sanitizeSourceAuth(*out, variables)
+	if err != nil {
+		return err
+	}
+
 	err = sanitizeSourceChecksum(*out)
 	if err != nil {
 		return err
@@ -316,6 +334,124 @@ func sanitizeSourceHeaders(out *libmonteur.TOMLSource,
 	return nil
 }
 
+func sanitizeSourceAuth(out *libmonteur.TOMLSource,
+	variables *map[string]interface{}) (err error) {
+	auth := out.Auth
+	if auth == nil {
+		return nil
+	}
+
+	for _, field := range []*string{
+		&auth.Type,
+		&auth.Username,
+		&auth.Password,
+		&auth.Token,
+		&auth.Netrc,
+	} {
+		*field, err = libtemplater.Template(*field, *variables)
+		if err != nil {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_AUTH_BAD,
+				err,
+			)
+		}
+	}
+
+	auth.Type = strings.ToLower(auth.Type)
+
+	switch auth.Type {
+	case libmonteur.PROGRAM_AUTH_BASIC:
+		if auth.Username == "" {
+			err = fmt.Errorf("%s: missing Username",
+				libmonteur.ERROR_PROGRAM_AUTH_BAD,
+			)
+		}
+	case libmonteur.PROGRAM_AUTH_BEARER:
+		if auth.Token == "" {
+			err = fmt.Errorf("%s: missing Token",
+				libmonteur.ERROR_PROGRAM_AUTH_BAD,
+			)
+		}
+	case libmonteur.PROGRAM_AUTH_NETRC:
+	default:
+		err = fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_PROGRAM_AUTH_TYPE_UNKNOWN,
+			auth.Type,
+		)
+	}
+
+	return err
+}
+
+func sanitizeSourceSignature(out *libmonteur.TOMLSource,
+	variables *map[string]interface{}) (err error) {
+	sig := out.Signature
+	if sig == nil {
+		return nil
+	}
+
+	for _, field := range []*string{
+		&sig.Type,
+		&sig.URL,
+		&sig.Value,
+		&sig.Namespace,
+	} {
+		*field, err = libtemplater.Template(*field, *variables)
+		if err != nil {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_SIGNATURE_BAD,
+				err,
+			)
+		}
+	}
+
+	sig.Type = strings.ToLower(sig.Type)
+	root, _ := (*variables)[libmonteur.VAR_ROOT].(string)
+	keys := []string{}
+
+	for _, v := range sig.Keys {
+		v, err = libtemplater.Template(v, *variables)
+		if err != nil {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_SIGNATURE_BAD,
+				err,
+			)
+		}
+
+		// trusted keys are kept inside the repository
+		if !filepath.IsAbs(v) {
+			v = filepath.Join(root, v)
+		}
+
+		keys = append(keys, v)
+	}
+
+	sig.Keys = keys
+
+	if len(sig.Keys) == 0 {
+		return fmt.Errorf("%s: missing Keys",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_BAD,
+		)
+	}
+
+	switch {
+	case sig.Type == libmonteur.PROGRAM_SIGNATURE_MINISIGN &&
+		sig.URL == "" && sig.Value == "":
+		sig.URL = out.URL + libmonteur.EXTENSION_MINISIG
+	case sig.Type == libmonteur.PROGRAM_SIGNATURE_SSH &&
+		sig.URL == "" && sig.Value == "":
+		sig.URL = out.URL + libmonteur.EXTENSION_SIG
+	case sig.Type != libmonteur.PROGRAM_SIGNATURE_MINISIGN &&
+		sig.Type != libmonteur.PROGRAM_SIGNATURE_SSH:
+		return fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_TYPE_UNKNOWN,
+			sig.Type,
+		)
+	}
+
+	return nil
+}
+
 func sanitizeSourceURL(out *libmonteur.TOMLSource,
 	variables *map[string]interface{}) (err error) {
 	out.URL, err = libtemplater.Template(out.URL, *variables)
@@ -335,9 +471,169 @@ func sanitizeSourceURL(out *libmonteur.TOMLSource,
 
 	(*variables)[libmonteur.VAR_URL] = out.URL
 
+	fallbacks := []string{}
+	for _, v := range out.Fallbacks {
+		v, err = libtemplater.Template(v, *variables)
+		if err != nil {
+			return fmt.Errorf("%s: Fallbacks error = %s",
+				libmonteur.ERROR_PROGRAM_ARCHIVE_BAD,
+				err,
+			)
+		}
+
+		if v == "" {
+			return fmt.Errorf("%s: Fallbacks = '%s'",
+				libmonteur.ERROR_PROGRAM_ARCHIVE_BAD,
+				v,
+			)
+		}
+
+		fallbacks = append(fallbacks, v)
+	}
+
+	out.Fallbacks = fallbacks
+
+	return nil
+}
+
+// sanitizeSourceNetwork merges the task's network settings over the
+// workspace's ones and templates all their values.
+func sanitizeSourceNetwork(in *libmonteur.TOMLNetwork,
+	out *libmonteur.TOMLSource,
+	variables map[string]interface{}) (err error) {
+	network := &libmonteur.TOMLNetwork{}
+
+	workspace, _ := variables[libmonteur.VAR_NETWORK].(*libmonteur.TOMLNetwork)
+	if workspace != nil {
+		network.Merge(workspace)
+	}
+
+	network.Merge(in)
+
+	for _, field := range []*string{
+		&network.Proxy,
+		&network.Cert,
+		&network.Key,
+	} {
+		*field, err = libtemplater.Template(*field, variables)
+		if err != nil {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_NETWORK_BAD,
+				err,
+			)
+		}
+	}
+
+	for i, path := range network.CAFiles {
+		network.CAFiles[i], err = libtemplater.Template(path, variables)
+		if err != nil {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_NETWORK_BAD,
+				err,
+			)
+		}
+	}
+
+	if (network.Cert == "") != (network.Key == "") {
+		return fmt.Errorf("%s: both Cert and Key are required",
+			libmonteur.ERROR_PROGRAM_NETWORK_BAD,
+		)
+	}
+
+	out.Network = network
+
 	return nil
 }
 
+// sanitizeSourceGit templates the pinned revision of a `git` type source.
+//
+// The Commit is compulsory for `git` type so that the fetched source is always
+// verified.
+func sanitizeSourceGit(metadata *libmonteur.TOMLMetadata,
+	out *libmonteur.TOMLSource,
+	variables map[string]interface{}) (err error) {
+	if strings.ToLower(metadata.Type) != libmonteur.PROGRAM_TYPE_GIT {
+		return nil
+	}
+
+	if out.Git == nil {
+		out.Git = &libmonteur.TOMLGit{}
+	}
+
+	for _, field := range []*string{
+		&out.Git.Ref,
+		&out.Git.Commit,
+	} {
+		*field, err = libtemplater.Template(*field, variables)
+		if err != nil {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_GIT_BAD,
+				err,
+			)
+		}
+	}
+
+	if out.Git.Commit == "" {
+		return fmt.Errorf("%s: Commit = ''", libmonteur.ERROR_PROGRAM_GIT_BAD)
+	}
+
+	out.Git.Commit = strings.ToLower(out.Git.Commit)
+
+	return nil
+}
+
+// sanitizeSourceMirror rewrites the URL and fallback URLs using the longest
+// matching prefix from the workspace's mirrors. The original URL is kept as
+// TOMLSource.Origin so the credentials are only sent to its host and never to
+// the mirror.
+func sanitizeSourceMirror(out *libmonteur.TOMLSource,
+	variables *map[string]interface{}) (err error) {
+	var mirrors []*libmonteur.TOMLMirror
+
+	mirrors, _ = (*variables)[libmonteur.VAR_MIRRORS].([]*libmonteur.TOMLMirror)
+	for _, m := range mirrors {
+		if m == nil || m.Prefix == "" || m.URL == "" {
+			return fmt.Errorf("%s: Prefix and URL are required",
+				libmonteur.ERROR_PROGRAM_MIRROR_BAD,
+			)
+		}
+	}
+
+	out.Origin = out.URL
+	out.URL = _mirrorURL(out.URL, mirrors)
+	(*variables)[libmonteur.VAR_URL] = out.URL
+
+	for i, v := range out.Fallbacks {
+		out.Fallbacks[i] = _mirrorURL(v, mirrors)
+	}
+
+	if out.Signature != nil && out.Signature.URL != "" {
+		out.Signature.URL = _mirrorURL(out.Signature.URL, mirrors)
+	}
+
+	return nil
+}
+
+func _mirrorURL(url string, mirrors []*libmonteur.TOMLMirror) string {
+	var mirror *libmonteur.TOMLMirror
+
+	for _, m := range mirrors {
+		if !strings.HasPrefix(url, m.Prefix) {
+			continue
+		}
+
+		if mirror == nil || len(m.Prefix) > len(mirror.Prefix) {
+			mirror = m
+		}
+	}
+
+	if mirror == nil {
+		return url
+	}
+
+	return mirror.URL + strings.TrimPrefix(url, mirror.Prefix)
+}
+
 func sanitizeSourceMethod(out *libmonteur.TOMLSource,
 	variables *map[string]interface{}) (err error) {
 	out.Method, err = libtemplater.Template(out.Method, *variables)
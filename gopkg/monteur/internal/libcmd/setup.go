@@ -21,18 +21,22 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/commander"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/endec/toml"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libchecksum"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libgit"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libhttp"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblocal"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblockfile"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libtargz"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libtemplater"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libunpack"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libzip"
 )
 
@@ -42,6 +46,7 @@ type setup struct {
 
 	reportUp chan conductor.Message
 	log      *liblog.Logger
+	reported time.Time
 
 	variables map[string]interface{}
 	metadata  *libmonteur.TOMLMetadata
@@ -57,6 +62,7 @@ func (me *setup) Parse(path string, secrets *libsecrets.Secrets) (err error) {
 	cmd := []*libmonteur.TOMLAction{}
 	cfg := map[string]string{}
 	sources := map[string]*libmonteur.TOMLSource{}
+	network := &libmonteur.TOMLNetwork{}
 
 	// initialize all important variables
 	me.metadata = &libmonteur.TOMLMetadata{}
@@ -70,6 +76,7 @@ func (me *setup) Parse(path string, secrets *libsecrets.Secrets) (err error) {
 		FMTVariables *map[string]interface{}
 		Dependencies *[]*libmonteur.TOMLDependency
 		Sources      *map[string]*libmonteur.TOMLSource
+		Network      *libmonteur.TOMLNetwork
 		CMD          *[]*libmonteur.TOMLAction
 		Config       *map[string]string
 	}{
@@ -78,6 +85,7 @@ func (me *setup) Parse(path string, secrets *libsecrets.Secrets) (err error) {
 		FMTVariables: &fmtVar,
 		Dependencies: &dep,
 		Sources:      &sources,
+		Network:      network,
 		CMD:          &cmd,
 		Config:       &cfg,
 	}
@@ -117,6 +125,16 @@ func (me *setup) Parse(path string, secrets *libsecrets.Secrets) (err error) {
 		return err
 	}
 
+	err = sanitizeSourceNetwork(network, me.source, me.variables)
+	if err != nil {
+		return err
+	}
+
+	err = sanitizeSourceGit(me.metadata, me.source, me.variables)
+	if err != nil {
+		return err
+	}
+
 	err = sanitizeCMD(cmd, &me.cmd, me.thisSystem)
 	if err != nil {
 		return err
@@ -141,7 +159,10 @@ func (me *setup) Run(ctx context.Context, ch chan conductor.Message) {
 	var unpackFx func(*libmonteur.TOMLSource, map[string]interface{}) error
 	var sourceFx func(context.Context,
 		*libmonteur.TOMLSource,
-		map[string]interface{}, *liblog.Logger, libchecksum.Hasher) error
+		map[string]interface{},
+		*liblog.Logger,
+		func(int64, int64),
+		libchecksum.Hasher) error
 	var cs libchecksum.Hasher
 	var err error
 	var task *executive
@@ -165,7 +186,13 @@ func (me *setup) Run(ctx context.Context, ch chan conductor.Message) {
 		me.reportError(err)
 	}
 
-	err = sourceFx(ctx, me.source, me.variables, me.log, cs)
+	err = sourceFx(ctx,
+		me.source,
+		me.variables,
+		me.log,
+		me.reportProgress,
+		cs,
+	)
 	if err != nil {
 		me.reportError(err)
 		return
@@ -188,6 +215,8 @@ func (me *setup) Run(ctx context.Context, ch chan conductor.Message) {
 		orders:    me.cmd,
 		fxSTDOUT:  me.reportOutput,
 		fxSTDERR:  me.reportStatus,
+		reportUp:  me.reportUp,
+		name:      me.metadata.Name,
 	}
 
 	err = task.Exec()
@@ -208,6 +237,35 @@ func (me *setup) Run(ctx context.Context, ch chan conductor.Message) {
 	me.reportDone()
 }
 
+// pin verifies the downloaded source at path against the setup lockfile.
+//
+// Only `https-download` sources are pinned since they are the only fetched
+// artifacts: `git` sources are already pinned by their commit while
+// `local-system` sources are never fetched. It runs before the download is
+// saved into the user level download cache so a drifted download is never
+// shared with other workspaces.
+func (me *setup) pin(path string) (err error) {
+	var pin *liblockfile.Pin
+
+	lock, _ := me.variables[libmonteur.VAR_SETUP_LOCK].(*liblockfile.Lockfile)
+	if lock == nil {
+		return nil
+	}
+
+	pin, err = liblockfile.NewPin(me.source, path)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	me.log.Info("Verifying source against setup.lock: %s (%s)",
+		pin.SHA256,
+		me.thisSystem,
+	)
+
+	//nolint:wrapcheck
+	return lock.Verify(me.metadata.Name, me.thisSystem, pin)
+}
+
 func (me *setup) processConfig() (err error) {
 	var configPath, pathing string
 
@@ -240,13 +298,27 @@ func (me *setup) processConfig() (err error) {
 
 func (me *setup) prepareSourceFx() (out func(context.Context,
 	*libmonteur.TOMLSource,
-	map[string]interface{}, *liblog.Logger, libchecksum.Hasher) error,
+	map[string]interface{},
+	*liblog.Logger,
+	func(int64, int64),
+	libchecksum.Hasher) error,
 	err error) {
 	switch strings.ToLower(me.metadata.Type) {
 	case libmonteur.PROGRAM_TYPE_HTTPS_DOWNLOAD:
-		out = libhttp.Source
+		out = func(ctx context.Context,
+			source *libmonteur.TOMLSource,
+			variables map[string]interface{},
+			log *liblog.Logger,
+			progress func(int64, int64),
+			cs libchecksum.Hasher) error {
+			return libhttp.VerifiedSource(ctx, source, variables, log,
+				progress, cs, me.pin,
+			)
+		}
 	case libmonteur.PROGRAM_TYPE_LOCAL_SYSTEM:
 		out = liblocal.Source
+	case libmonteur.PROGRAM_TYPE_GIT:
+		out = libgit.Source
 	default:
 		err = fmt.Errorf("%s: %s",
 			libmonteur.ERROR_PROGRAM_TYPE_UNKNOWN,
@@ -258,48 +330,27 @@ func (me *setup) prepareSourceFx() (out func(context.Context,
 }
 
 func (me *setup) prepareChecksumFx() (out libchecksum.Hasher, err error) {
-	var csType string
-
-	if me.source.Checksum == nil {
-		return nil, nil
-	}
-
-	csType = strings.ToLower(me.source.Checksum.Type)
-	out, err = libchecksum.CreateChecksum(csType)
-	if err != nil {
-		return nil, err //nolint:wrapcheck
-	}
-
-	// parse checksum value
-	switch strings.ToLower(me.source.Checksum.Format) {
-	case libmonteur.CHECKSUM_FORMAT_BASE64:
-		err = out.ParseBase64(me.source.Checksum.Value)
-	case libmonteur.CHECKSUM_FORMAT_BASE64_URL:
-		err = out.ParseBase64URL(me.source.Checksum.Value)
-	case libmonteur.CHECKSUM_FORMAT_HEX:
-		err = out.ParseHex(me.source.Checksum.Value)
-	default:
-		return nil, fmt.Errorf("%s: '%s'",
-			libmonteur.ERROR_CHECKSUM_FORMAT_UNKNOWN,
-			me.source.Checksum.Format,
-		)
-	}
-
-	if err != nil {
-		return nil, fmt.Errorf("%s: %s",
-			libmonteur.ERROR_CHECKSUM_BAD,
-			err,
-		)
-	}
-
-	return out, nil
+	return libchecksum.Parse(me.source.Checksum) //nolint:wrapcheck
 }
 
 func (me *setup) prepareUnpackFx() (out func(*libmonteur.TOMLSource,
 	map[string]interface{}) error, err error) {
+	// git source is checked out as it is
+	if strings.ToLower(me.metadata.Type) == libmonteur.PROGRAM_TYPE_GIT {
+		return nil, nil
+	}
+
 	switch me.source.Format {
 	case libmonteur.PROGRAM_FORMAT_TAR_GZ:
 		out = libtargz.Unpack
+	case libmonteur.PROGRAM_FORMAT_TAR_XZ:
+		out = libunpack.TarXZ
+	case libmonteur.PROGRAM_FORMAT_TAR_BZ2:
+		out = libunpack.TarBZ2
+	case libmonteur.PROGRAM_FORMAT_GZ:
+		out = libunpack.GZ
+	case libmonteur.PROGRAM_FORMAT_BZ2:
+		out = libunpack.BZ2
 	case libmonteur.PROGRAM_FORMAT_ZIP:
 		out = libzip.Unpack
 	case libmonteur.PROGRAM_FORMAT_RAW:
@@ -331,6 +382,24 @@ func (me *setup) reportOutput(format string, args ...interface{}) {
 	reportOutput(me.log, me.reportUp, me.metadata.Name, format, args...)
 }
 
+func (me *setup) reportProgress(downloaded int64, total int64) {
+	if me.reportUp == nil {
+		return
+	}
+
+	// throttle the reports except the final one
+	if downloaded != total &&
+		time.Since(me.reported) < libmonteur.PROGRESS_INTERVAL {
+		return
+	}
+
+	me.reported = time.Now()
+	me.reportUp <- conductor.CreateProgress(me.metadata.Name,
+		downloaded,
+		total,
+	)
+}
+
 func (me *setup) reportDone() {
 	reportDone(me.log, me.reportUp, me.metadata.Name)
 }
@@ -0,0 +1,759 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/endec/toml"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libtemplater"
+)
+
+// Issue is a single problem found by Validator in a config file.
+type Issue struct {
+	Path    string
+	Message string
+	Line    int
+	Column  int
+}
+
+// String is to generate the `path:line:column: message` form of the Issue.
+//
+// Should the position be unknown (`0`), the `path: message` form is used.
+func (me *Issue) String() string {
+	if me.Line == 0 {
+		return fmt.Sprintf("%s: %s", me.Path, me.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s",
+		me.Path,
+		me.Line,
+		me.Column,
+		me.Message,
+	)
+}
+
+type validatorData struct {
+	Metadata     *libmonteur.TOMLMetadata
+	Variables    map[string]interface{}
+	FMTVariables map[string]interface{}
+	Dependencies []*libmonteur.TOMLDependency
+	CMD          []*libmonteur.TOMLAction
+	Sources      map[string]*libmonteur.TOMLSource
+	Network      *libmonteur.TOMLNetwork
+	Downloads    *libmonteur.TOMLDownloads
+	Config       map[string]string
+	Packages     map[string]*libmonteur.TOMLPackage
+	Changelog    *libmonteur.TOMLChangelog
+	Releases     *libmonteur.TOMLRelease
+}
+
+// Validator statically checks a job's config files without running them.
+//
+// Unlike Manager, it decodes the files strictly and collects all the issues
+// it can find instead of stopping at the first error.
+type Validator struct {
+	Variables map[string]interface{}
+	Secrets   *libsecrets.Secrets
+	Job       string
+
+	path   string
+	doc    []byte
+	known  map[string]bool
+	issues []*Issue
+}
+
+// Settings validates the job's settings file (e.g. `build/config.toml`).
+//
+// Its variables are merged into Validator.Variables for validating the task
+// files that come after it.
+func (me *Validator) Settings(path string) (list []*Issue, err error) {
+	var data interface{}
+
+	d := &validatorData{
+		Variables:    map[string]interface{}{},
+		FMTVariables: map[string]interface{}{},
+		Downloads:    &libmonteur.TOMLDownloads{},
+	}
+
+	tables := []string{"Variables", "FMTVariables"}
+	if me.Job == libmonteur.JOB_SETUP {
+		tables = append(tables, "Downloads")
+	}
+
+	data, err = _schema(d, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := me.decode(path, data)
+	if err != nil || !ok {
+		return me.issues, err
+	}
+
+	me.initKnown(d)
+
+	err = me.checkVariables(d)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range d.Variables {
+		me.Variables[k] = v
+	}
+
+	for k, v := range d.FMTVariables {
+		me.Variables[k] = v
+	}
+
+	return me.issues, nil
+}
+
+// Task validates a job's task file (e.g. `build/jobs/linux-amd64.toml`).
+func (me *Validator) Task(path string) (list []*Issue, err error) {
+	var ok bool
+	var data interface{}
+
+	d := &validatorData{
+		Metadata:     &libmonteur.TOMLMetadata{},
+		Variables:    map[string]interface{}{},
+		FMTVariables: map[string]interface{}{},
+		Dependencies: []*libmonteur.TOMLDependency{},
+		CMD:          []*libmonteur.TOMLAction{},
+		Sources:      map[string]*libmonteur.TOMLSource{},
+		Network:      &libmonteur.TOMLNetwork{},
+		Config:       map[string]string{},
+		Packages:     map[string]*libmonteur.TOMLPackage{},
+		Changelog:    &libmonteur.TOMLChangelog{},
+		Releases: &libmonteur.TOMLRelease{
+			Data:     &libmonteur.TOMLReleaseData{},
+			Packages: map[string]*libmonteur.TOMLPackage{},
+		},
+	}
+
+	tables := _tables(me.Job)
+	if tables == nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_VALIDATE_JOB_UNKNOWN,
+			me.Job,
+		)
+	}
+
+	data, err = _schema(d, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err = me.decode(path, data)
+	if err != nil || !ok {
+		return me.issues, err
+	}
+
+	me.initKnown(d)
+	me.checkMetadata(d)
+
+	err = me.checkVariables(d)
+	if err != nil {
+		return nil, err
+	}
+
+	me.checkDependencies(d)
+	me.checkCMD("CMD", d.CMD)
+	me.checkCMD("Changelog.CMD", d.Changelog.CMD)
+
+	err = me.checkSources(d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = me.checkPackages("Packages", d.Packages)
+	if err != nil {
+		return nil, err
+	}
+
+	err = me.checkReleases(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return me.issues, nil
+}
+
+// _tables lists the tables of the job's task file, matching the ones decoded
+// by its task (e.g. `setup.Parse`). It returns nil for an unknown job.
+func _tables(job string) (list []string) {
+	list = []string{
+		"Metadata",
+		"Variables",
+		"FMTVariables",
+		"Dependencies",
+		"CMD",
+	}
+
+	switch job {
+	case libmonteur.JOB_SETUP:
+		return append(list, "Sources", "Network", "Config")
+	case libmonteur.JOB_PREPARE:
+		return append(list, "Changelog", "Packages")
+	case libmonteur.JOB_PACKAGE:
+		return append(list, "Packages")
+	case libmonteur.JOB_RELEASE:
+		return append(list, "Releases")
+	case libmonteur.JOB_TEST,
+		libmonteur.JOB_BUILD,
+		libmonteur.JOB_COMPOSE,
+		libmonteur.JOB_PUBLISH,
+		libmonteur.JOB_CLEAN:
+		return list
+	default:
+		return nil
+	}
+}
+
+// _schema builds the decoding data structure holding only the given tables.
+//
+// Each table is taken from the validatorData field of the same name so the
+// file is decoded straight into the libmonteur TOML data types.
+func _schema(d *validatorData, tables []string) (out interface{}, err error) {
+	source := reflect.ValueOf(d).Elem()
+	fields := make([]reflect.StructField, len(tables))
+	values := make([]reflect.Value, len(tables))
+
+	for i, name := range tables {
+		v := source.FieldByName(name)
+		if !v.IsValid() {
+			return nil, fmt.Errorf("%s: unknown table '%s'",
+				libmonteur.ERROR_VALIDATE_SCHEMA_BAD,
+				name,
+			)
+		}
+
+		if v.Kind() != reflect.Ptr {
+			v = v.Addr()
+		}
+
+		fields[i] = reflect.StructField{Name: name, Type: v.Type()}
+		values[i] = v
+	}
+
+	s := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, v := range values {
+		s.Field(i).Set(v)
+	}
+
+	return s.Addr().Interface(), nil
+}
+
+func (me *Validator) decode(path string, data interface{}) (bool, error) {
+	me.path = path
+	me.issues = []*Issue{}
+
+	list, err := toml.ValidateFile(path, data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_TOML_PARSE_FAILED,
+			err,
+		)
+	}
+
+	// keep the document for positioning the issues found afterward
+	me.doc, err = os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_TOML_PARSE_FAILED,
+			err,
+		)
+	}
+
+	// unknown keys do not stop the decoding so the data is still usable
+	ok := true
+
+	for _, v := range list {
+		message := v.Message
+		if v.Key != "" {
+			message = fmt.Sprintf("%s '%s'", v.Message, v.Key)
+		}
+
+		me.issues = append(me.issues, &Issue{
+			Path:    path,
+			Message: message,
+			Line:    v.Line,
+			Column:  v.Column,
+		})
+
+		if v.Message != toml.ERROR_UNKNOWN_KEY {
+			ok = false
+		}
+	}
+
+	return ok, nil
+}
+
+func (me *Validator) initKnown(d *validatorData) {
+	me.known = map[string]bool{}
+
+	for k := range me.Variables {
+		me.known[k] = true
+	}
+
+	for k := range d.Variables {
+		me.known[k] = true
+	}
+
+	for k := range d.FMTVariables {
+		me.known[k] = true
+	}
+
+	// variables generated by the task itself during its run
+	for _, k := range []string{
+		libmonteur.VAR_ARCHIVE,
+		libmonteur.VAR_CHANGELOG_ENTRIES,
+		libmonteur.VAR_FORMAT,
+		libmonteur.VAR_METHOD,
+		libmonteur.VAR_PACKAGE,
+		libmonteur.VAR_PACKAGE_ARCH,
+		libmonteur.VAR_PACKAGE_NAME,
+		libmonteur.VAR_PACKAGE_OS,
+		libmonteur.VAR_PACKAGE_VERSION,
+		libmonteur.VAR_PACKAGE_VERSION_DIGIT_LED,
+		libmonteur.VAR_SOURCE,
+		libmonteur.VAR_SOURCE_ARCH,
+		libmonteur.VAR_SOURCE_COMPUTE,
+		libmonteur.VAR_SOURCE_OS,
+		libmonteur.VAR_TARGET,
+		libmonteur.VAR_URL,
+	} {
+		me.known[k] = true
+	}
+
+	list := d.CMD
+	if d.Changelog != nil {
+		list = append(list, d.Changelog.CMD...)
+	}
+
+	for _, cmd := range list {
+		if cmd != nil && cmd.Save != "" {
+			me.known[cmd.Save] = true
+		}
+	}
+}
+
+// report is to record an issue of the given key (e.g. `CMD[1].Name`).
+//
+// The issue is positioned at the key inside the config file or its closest
+// parent table when the key is absent.
+func (me *Validator) report(key string, format string, args ...interface{}) {
+	line, column := toml.Locate(me.doc, key)
+
+	me.issues = append(me.issues, &Issue{
+		Path:    me.path,
+		Message: key + ": " + fmt.Sprintf(format, args...),
+		Line:    line,
+		Column:  column,
+	})
+}
+
+func (me *Validator) checkMetadata(d *validatorData) {
+	var list []string
+
+	if d.Metadata.Name == "" {
+		me.report("Metadata.Name", "%s",
+			libmonteur.ERROR_VALIDATE_NAME_MISSING,
+		)
+	}
+
+	switch me.Job {
+	case libmonteur.JOB_SETUP:
+		list = []string{
+			libmonteur.PROGRAM_TYPE_GIT,
+			libmonteur.PROGRAM_TYPE_HTTPS_DOWNLOAD,
+			libmonteur.PROGRAM_TYPE_LOCAL_SYSTEM,
+		}
+	case libmonteur.JOB_PREPARE:
+		list = []string{
+			libmonteur.CHANGELOG_MARKDOWN,
+			libmonteur.CHANGELOG_MANUAL,
+			libmonteur.CHANGELOG_DEB,
+		}
+	case libmonteur.JOB_PACKAGE:
+		list = []string{
+			libmonteur.PACKAGE_DEB_MANUAL,
+			libmonteur.PACKAGE_MANUAL,
+			libmonteur.PACKAGE_TARGZ,
+			libmonteur.PACKAGE_ZIP,
+		}
+	case libmonteur.JOB_RELEASE:
+		list = []string{
+			libmonteur.RELEASE_ARCHIVE,
+			libmonteur.RELEASE_MANUAL,
+		}
+	default:
+		return
+	}
+
+	if !_isListed(list, strings.ToLower(d.Metadata.Type)) {
+		me.report("Metadata.Type", "%s '%s'",
+			libmonteur.ERROR_VALIDATE_TYPE_UNKNOWN,
+			d.Metadata.Type,
+		)
+	}
+}
+
+func (me *Validator) checkVariables(d *validatorData) (err error) {
+	list, err := _sortedKeys(d.FMTVariables)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range list {
+		if v, ok := d.FMTVariables[k].(string); ok {
+			me.checkTemplate("FMTVariables."+k, v)
+		}
+	}
+
+	return nil
+}
+
+func (me *Validator) checkDependencies(d *validatorData) {
+	for i, dep := range d.Dependencies {
+		label := fmt.Sprintf("Dependencies[%d]", i)
+
+		if dep == nil {
+			continue
+		}
+
+		if dep.Name == "" {
+			me.report(label+".Name", "%s",
+				libmonteur.ERROR_VALIDATE_NAME_MISSING,
+			)
+		}
+
+		if dep.Condition == "" {
+			me.report(label+".Condition", "%s",
+				libmonteur.ERROR_VALIDATE_CONDITION_MISSING,
+			)
+		}
+
+		if !dep.Type.IsValid() {
+			me.report(label+".Type", "%s '%s'",
+				libmonteur.ERROR_VALIDATE_ACTION_UNKNOWN,
+				dep.Type,
+			)
+		}
+
+		me.checkTemplate(label+".Command", dep.Command)
+	}
+}
+
+func (me *Validator) checkCMD(name string, list []*libmonteur.TOMLAction) {
+	for i, cmd := range list {
+		label := fmt.Sprintf("%s[%d]", name, i)
+
+		if cmd == nil {
+			continue
+		}
+
+		if cmd.Name == "" {
+			me.report(label+".Name", "%s",
+				libmonteur.ERROR_VALIDATE_NAME_MISSING,
+			)
+		}
+
+		if len(cmd.Condition) == 0 {
+			me.report(label+".Condition", "%s",
+				libmonteur.ERROR_VALIDATE_CONDITION_MISSING,
+			)
+		}
+
+		if !cmd.Type.IsValid() {
+			me.report(label+".Type", "%s '%s'",
+				libmonteur.ERROR_VALIDATE_ACTION_UNKNOWN,
+				cmd.Type,
+			)
+		}
+
+		me.checkTemplate(label+".Location", cmd.Location)
+		me.checkTemplate(label+".Source", cmd.Source)
+		me.checkTemplate(label+".Target", cmd.Target)
+		me.checkTemplate(label+".ToSTDOUT", cmd.ToSTDOUT)
+		me.checkTemplate(label+".ToSTDERR", cmd.ToSTDERR)
+	}
+}
+
+func (me *Validator) checkSources(d *validatorData) (err error) {
+	var list []string
+
+	formats := []string{
+		libmonteur.PROGRAM_FORMAT_BZ2,
+		libmonteur.PROGRAM_FORMAT_GZ,
+		libmonteur.PROGRAM_FORMAT_RAW,
+		libmonteur.PROGRAM_FORMAT_TAR_BZ2,
+		libmonteur.PROGRAM_FORMAT_TAR_GZ,
+		libmonteur.PROGRAM_FORMAT_TAR_XZ,
+		libmonteur.PROGRAM_FORMAT_ZIP,
+	}
+
+	list, err = _sortedKeys(d.Sources)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range list {
+		label := "Sources." + k
+		src := d.Sources[k]
+
+		if src == nil {
+			continue
+		}
+
+		if src.Format != "" && !strings.Contains(src.Format, "{{") &&
+			!_isListed(formats, strings.ToLower(src.Format)) {
+			me.report(label+".Format", "%s '%s'",
+				libmonteur.ERROR_VALIDATE_FORMAT_UNKNOWN,
+				src.Format,
+			)
+		}
+
+		me.checkTemplate(label+".Format", src.Format)
+		me.checkTemplate(label+".Archive", src.Archive)
+		me.checkTemplate(label+".Method", src.Method)
+		me.checkTemplate(label+".URL", src.URL)
+
+		err = me.checkMap(label+".Headers", src.Headers)
+		if err != nil {
+			return err
+		}
+
+		me.checkAuth(label+".Auth", src.Auth)
+		me.checkSignature(label+".Signature", src.Signature)
+
+		if src.Git != nil {
+			me.checkTemplate(label+".Git.Ref", src.Git.Ref)
+			me.checkTemplate(label+".Git.Commit", src.Git.Commit)
+		}
+	}
+
+	if d.Network != nil {
+		me.checkTemplate("Network.Proxy", d.Network.Proxy)
+		me.checkTemplate("Network.Cert", d.Network.Cert)
+		me.checkTemplate("Network.Key", d.Network.Key)
+
+		for i, v := range d.Network.CAFiles {
+			me.checkTemplate(fmt.Sprintf("Network.CAFiles[%d]", i), v)
+		}
+	}
+
+	return me.checkMap("Config", d.Config)
+}
+
+func (me *Validator) checkAuth(label string, auth *libmonteur.TOMLAuth) {
+	types := []string{
+		libmonteur.PROGRAM_AUTH_BASIC,
+		libmonteur.PROGRAM_AUTH_BEARER,
+		libmonteur.PROGRAM_AUTH_NETRC,
+	}
+
+	if auth == nil {
+		return
+	}
+
+	if !strings.Contains(auth.Type, "{{") &&
+		!_isListed(types, strings.ToLower(auth.Type)) {
+		me.report(label+".Type", "%s '%s'",
+			libmonteur.ERROR_PROGRAM_AUTH_TYPE_UNKNOWN,
+			auth.Type,
+		)
+	}
+
+	me.checkTemplate(label+".Type", auth.Type)
+	me.checkTemplate(label+".Username", auth.Username)
+	me.checkTemplate(label+".Password", auth.Password)
+	me.checkTemplate(label+".Token", auth.Token)
+	me.checkTemplate(label+".Netrc", auth.Netrc)
+}
+
+func (me *Validator) checkSignature(label string,
+	sig *libmonteur.TOMLSignature) {
+	types := []string{
+		libmonteur.PROGRAM_SIGNATURE_MINISIGN,
+		libmonteur.PROGRAM_SIGNATURE_SSH,
+	}
+
+	if sig == nil {
+		return
+	}
+
+	if !strings.Contains(sig.Type, "{{") &&
+		!_isListed(types, strings.ToLower(sig.Type)) {
+		me.report(label+".Type", "%s '%s'",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_TYPE_UNKNOWN,
+			sig.Type,
+		)
+	}
+
+	if len(sig.Keys) == 0 {
+		me.report(label+".Keys", "%s",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_BAD,
+		)
+	}
+
+	me.checkTemplate(label+".Type", sig.Type)
+	me.checkTemplate(label+".URL", sig.URL)
+	me.checkTemplate(label+".Value", sig.Value)
+	me.checkTemplate(label+".Namespace", sig.Namespace)
+
+	for i, v := range sig.Keys {
+		me.checkTemplate(fmt.Sprintf("%s.Keys[%d]", label, i), v)
+	}
+}
+
+func (me *Validator) checkPackages(name string,
+	packages map[string]*libmonteur.TOMLPackage) (err error) {
+	var list, files []string
+
+	list, err = _sortedKeys(packages)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range list {
+		label := name + "." + k
+		pkg := packages[k]
+
+		if pkg == nil {
+			continue
+		}
+
+		me.checkTemplate(label+".Changelog", pkg.Changelog)
+		me.checkTemplate(label+".Source", pkg.Source)
+		me.checkTemplate(label+".Target", pkg.Target)
+
+		files, err = _sortedKeys(pkg.Files)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			me.checkTemplate(label+".Files", f)
+			me.checkTemplate(label+".Files."+f, pkg.Files[f])
+		}
+	}
+
+	return nil
+}
+
+func (me *Validator) checkReleases(d *validatorData) (err error) {
+	me.checkTemplate("Releases.Target", d.Releases.Target)
+
+	if d.Releases.Data != nil {
+		me.checkTemplate("Releases.Data.Path", d.Releases.Data.Path)
+	}
+
+	return me.checkPackages("Releases.Packages", d.Releases.Packages)
+}
+
+// checkMap checks the templates of all the values in the given text map.
+func (me *Validator) checkMap(name string, m map[string]string) (err error) {
+	list, err := _sortedKeys(m)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range list {
+		me.checkTemplate(name+"."+k, m[k])
+	}
+
+	return nil
+}
+
+func (me *Validator) checkTemplate(label string, text string) {
+	if !strings.Contains(text, "{{") {
+		return
+	}
+
+	ret, err := libtemplater.Analyze(text)
+	if err != nil {
+		me.report(label, "%s: %s",
+			libmonteur.ERROR_VALIDATE_TEMPLATE_BAD,
+			err,
+		)
+
+		return
+	}
+
+	for _, v := range ret.Variables {
+		if !me.known[v] {
+			me.report(label, "%s '.%s'",
+				libmonteur.ERROR_VALIDATE_VARIABLE_UNRESOLVED,
+				v,
+			)
+		}
+	}
+
+	if me.Secrets == nil {
+		return
+	}
+
+	// only the key is checked so no secret value is ever read
+	for _, args := range ret.Calls["GetSecret"] {
+		if len(args) == 0 {
+			continue
+		}
+
+		if !me.Secrets.Has(args[0]) {
+			me.report(label, "%s '%s'",
+				libmonteur.ERROR_VALIDATE_SECRET_UNDEFINED,
+				args[0],
+			)
+		}
+	}
+}
+
+func _isListed(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// _sortedKeys lists the keys of the given string keyed map in sorted order.
+func _sortedKeys(in interface{}) (out []string, err error) {
+	m := reflect.ValueOf(in)
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("%s: unsupported map type '%T'",
+			libmonteur.ERROR_VALIDATE_SCHEMA_BAD,
+			in,
+		)
+	}
+
+	out = make([]string, 0, m.Len())
+	for _, k := range m.MapKeys() {
+		out = append(out, k.String())
+	}
+
+	sort.Strings(out)
+
+	return out, nil
+}
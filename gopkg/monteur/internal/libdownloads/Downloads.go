@@ -0,0 +1,466 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package libdownloads is the user level cache of downloaded sources.
+//
+// The cache is shared across all repositories of the same user so that the
+// same tool archive is only downloaded once. Each entry is keyed by the
+// source's checksum value (or its URL when checksum is absent) and is always
+// verified before reuse.
+package libdownloads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libchecksum"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	EXTENSION_ENTRY = ".json"
+	PREFIX_TEMP     = ".tmp-"
+	PREFIX_URL      = "url-"
+)
+
+// STRAY_AGE is the minimum age of a stray file before it is removed.
+//
+// Younger files may still be in use by a concurrent run storing its download.
+const STRAY_AGE = time.Hour
+
+// keyPattern matches the cache keys generated by `Key`.
+var keyPattern = regexp.MustCompile(`^[a-z0-9>-]+-[0-9a-fA-F]+$`)
+
+// Entry is the metadata of a cached download.
+type Entry struct {
+	Key      string    `json:"key"`
+	URL      string    `json:"url"`
+	Archive  string    `json:"archive"`
+	SHA256   string    `json:"sha256"`
+	Size     int64     `json:"size"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Cache is the user level download cache directory.
+//
+// Cache is safe to be created using the standard `&struct{}` method.
+type Cache struct {
+	// Dir is the cache directory
+	Dir string
+}
+
+// Open is to create the user level download cache.
+//
+// The directory is `ENV_DOWNLOADS_DIR` when set, otherwise the
+// `DIRECTORY_DOWNLOADS` inside the user cache directory (e.g.
+// `$XDG_CACHE_HOME/monteur/downloads`).
+func Open() (out *Cache, err error) {
+	var dir string
+
+	dir = os.Getenv(libmonteur.ENV_DOWNLOADS_DIR)
+	if dir == "" {
+		dir, err = os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s",
+				libmonteur.ERROR_DOWNLOADS_MISSING,
+				err,
+			)
+		}
+
+		dir = filepath.Join(dir, libmonteur.DIRECTORY_DOWNLOADS)
+	}
+
+	return &Cache{Dir: dir}, nil
+}
+
+// Key is to generate the cache key of the given source.
+//
+// The key is the checksum algorithm and its hex value (e.g. `sha256-ab12...`)
+// when checksum is available. Otherwise, it is the SHA256 of the URL before
+// mirror rewriting so that the same entry is shared with or without mirrors.
+func Key(source *libmonteur.TOMLSource) (key string, err error) {
+	var cs libchecksum.Hasher
+
+	if source.Checksum == nil {
+		sum := sha256.Sum256([]byte(source.OriginURL()))
+		return PREFIX_URL + hex.EncodeToString(sum[:]), nil
+	}
+
+	cs, err = libchecksum.Parse(source.Checksum)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	key, err = cs.ToHex()
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", libmonteur.ERROR_CHECKSUM_BAD, err)
+	}
+
+	return strings.ToLower(source.Checksum.Type) + "-" + key, nil
+}
+
+// Fetch is to copy the cached download of the given key into destination.
+//
+// The cached file is verified against both its recorded SHA256 and the
+// source's checksum (when available) before the copy. A corrupted entry is
+// removed and reported as an error. If there is no such entry, both out and
+// err shall be `nil`.
+func (me *Cache) Fetch(key string,
+	source *libmonteur.TOMLSource,
+	destination string) (out *Entry, err error) {
+	var cs libchecksum.Hasher
+	var ok bool
+
+	out, err = me.read(key)
+	if out == nil || err != nil {
+		return nil, err
+	}
+
+	path := me.path(key)
+
+	cs, err = libchecksum.Parse(source.Checksum)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	ok, err = _verify(path, out.SHA256, cs)
+	if err != nil || !ok {
+		_ = me.Remove(key)
+
+		if err == nil {
+			err = fmt.Errorf("%s: %s",
+				libmonteur.ERROR_DOWNLOADS_CORRUPTED,
+				key,
+			)
+		}
+
+		return nil, err
+	}
+
+	err = _copy(destination, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_READ, err)
+	}
+
+	// record the usage for pruning
+	out.LastUsed = time.Now().UTC()
+	_ = me.write(out)
+
+	return out, nil
+}
+
+// Store is to save the downloaded file at path into the cache.
+//
+// The file is copied into a temporary file and renamed into place so that a
+// partially stored entry is never reused.
+func (me *Cache) Store(key string,
+	source *libmonteur.TOMLSource,
+	path string) (err error) {
+	var f *os.File
+	var size int64
+
+	err = os.MkdirAll(me.Dir, libmonteur.PERMISSION_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+
+	f, err = os.CreateTemp(me.Dir, PREFIX_TEMP)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	hash := sha256.New()
+	size, err = _copyFile(io.MultiWriter(f, hash), path)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+
+	err = os.Rename(f.Name(), me.path(key))
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+
+	now := time.Now().UTC()
+
+	return me.write(&Entry{
+		Key:      key,
+		URL:      source.URL,
+		Archive:  source.Archive,
+		SHA256:   hex.EncodeToString(hash.Sum(nil)),
+		Size:     size,
+		Created:  now,
+		LastUsed: now,
+	})
+}
+
+// List is to get all the cached downloads sorted by their last usage.
+func (me *Cache) List() (out []*Entry, err error) {
+	var entries []os.DirEntry
+	var entry *Entry
+
+	entries, err = os.ReadDir(me.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Entry{}, nil
+		}
+
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_READ, err)
+	}
+
+	out = []*Entry{}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), EXTENSION_ENTRY) {
+			continue
+		}
+
+		entry, err = me.read(strings.TrimSuffix(e.Name(), EXTENSION_ENTRY))
+		if err != nil || entry == nil {
+			continue
+		}
+
+		out = append(out, entry)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].LastUsed.After(out[j].LastUsed)
+	})
+
+	return out, nil
+}
+
+// Prune is to remove the cached downloads unused since the given age.
+//
+// Stray files like incomplete temporary files and entries without metadata
+// are removed as well once they are older than STRAY_AGE. Files not named by
+// the cache are left untouched.
+func (me *Cache) Prune(age time.Duration) (out []*Entry, err error) {
+	var list []*Entry
+
+	list, err = me.List()
+	if err != nil {
+		return nil, err
+	}
+
+	out = []*Entry{}
+	known := map[string]bool{}
+	deadline := time.Now().Add(-age)
+
+	for _, entry := range list {
+		if entry.LastUsed.After(deadline) {
+			known[entry.Key] = true
+			known[entry.Key+EXTENSION_ENTRY] = true
+			continue
+		}
+
+		err = me.Remove(entry.Key)
+		if err != nil {
+			return out, err
+		}
+
+		out = append(out, entry)
+	}
+
+	return out, me.removeStrays(known)
+}
+
+// Clear is to remove all the cached downloads.
+//
+// Like `Prune`, stray files younger than STRAY_AGE and files not named by the
+// cache are left untouched.
+func (me *Cache) Clear() (out []*Entry, err error) {
+	out, err = me.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range out {
+		err = me.Remove(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, me.removeStrays(map[string]bool{})
+}
+
+// Remove is to delete the cached download of the given key.
+func (me *Cache) Remove(key string) (err error) {
+	for _, path := range []string{
+		me.path(key),
+		me.path(key) + EXTENSION_ENTRY,
+	} {
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_DOWNLOADS_REMOVE,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (me *Cache) removeStrays(known map[string]bool) (err error) {
+	var entries []os.DirEntry
+
+	entries, err = os.ReadDir(me.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_READ, err)
+	}
+
+	deadline := time.Now().Add(-STRAY_AGE)
+
+	for _, e := range entries {
+		if known[e.Name()] || !e.Type().IsRegular() || !_isOwned(e.Name()) {
+			continue
+		}
+
+		info, statErr := e.Info()
+		if statErr != nil || info.ModTime().After(deadline) {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(me.Dir, info.Name()))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_DOWNLOADS_REMOVE,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// _isOwned checks the file name follows the cache's own naming.
+func _isOwned(name string) bool {
+	if strings.HasPrefix(name, PREFIX_TEMP) {
+		return true
+	}
+
+	return keyPattern.MatchString(strings.TrimSuffix(name, EXTENSION_ENTRY))
+}
+
+func (me *Cache) read(key string) (out *Entry, err error) {
+	var data []byte
+
+	data, err = os.ReadFile(me.path(key) + EXTENSION_ENTRY)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_READ, err)
+	}
+
+	out = &Entry{}
+	err = json.Unmarshal(data, out)
+	if err != nil || out.Key != key {
+		// unreadable metadata is the same as missing
+		_ = me.Remove(key)
+		return nil, nil
+	}
+
+	return out, nil
+}
+
+func (me *Cache) write(entry *Entry) (err error) {
+	var data []byte
+	var f *os.File
+
+	data, err = json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+
+	f, err = os.CreateTemp(me.Dir, PREFIX_TEMP)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.Write(data)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+
+	err = os.Rename(f.Name(), me.path(entry.Key)+EXTENSION_ENTRY)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_DOWNLOADS_STORE, err)
+	}
+
+	return nil
+}
+
+func (me *Cache) path(key string) string {
+	return filepath.Join(me.Dir, filepath.Base(key))
+}
+
+func _verify(path string, sum string, cs libchecksum.Hasher) (bool, error) {
+	var f *os.File
+	var err error
+	var ok bool
+
+	f, err = os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_DOWNLOADS_READ,
+			err,
+		)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	reader := io.TeeReader(f, hash)
+
+	if cs != nil {
+		ok, err = cs.Compare(reader)
+		if err != nil || !ok {
+			return false, err //nolint:wrapcheck
+		}
+	}
+
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return false, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_DOWNLOADS_READ,
+			err,
+		)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)) == sum, nil
+}
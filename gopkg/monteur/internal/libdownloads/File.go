@@ -0,0 +1,62 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libdownloads
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+func _copy(destination string, source string) (err error) {
+	var f *os.File
+
+	err = os.MkdirAll(filepath.Dir(destination),
+		libmonteur.PERMISSION_DIRECTORY,
+	)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	f, err = os.Create(destination)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = _copyFile(f, source)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(destination)
+
+		return err
+	}
+
+	return f.Close() //nolint:wrapcheck
+}
+
+func _copyFile(w io.Writer, path string) (size int64, err error) {
+	var f *os.File
+
+	f, err = os.Open(path)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	return io.Copy(w, f) //nolint:wrapcheck
+}
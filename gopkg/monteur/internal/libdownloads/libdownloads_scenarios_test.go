@@ -0,0 +1,114 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libdownloads
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testFetch,
+			Description: `
+Cache.Fetch should copy the cached download into destination when:
+1. the source has a checksum.
+2. the download was stored with the same key.
+`,
+			Switches: map[string]bool{
+				useChecksum: true,
+				expectFound: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testFetch,
+			Description: `
+Cache.Fetch should copy the cached download into destination when:
+1. the source has no checksum.
+2. the download was stored with the same URL key.
+`,
+			Switches: map[string]bool{
+				expectFound: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testFetch,
+			Description: `
+Cache.Fetch should report a cache miss without error when:
+1. the source has a checksum.
+2. nothing was stored with the given key.
+`,
+			Switches: map[string]bool{
+				useChecksum:     true,
+				useMissingEntry: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testFetch,
+			Description: `
+Cache.Fetch should reject and remove the cached download when:
+1. the source has no checksum.
+2. the cached file no longer matches its recorded SHA256.
+`,
+			Switches: map[string]bool{
+				useCorruptedEntry: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      5,
+			TestType: testFetch,
+			Description: `
+Cache.Fetch should reject and remove the cached download when:
+1. the source has a checksum.
+2. both the cached file and its recorded SHA256 were replaced.
+`,
+			Switches: map[string]bool{
+				useChecksum:    true,
+				useForgedEntry: true,
+				expectError:    true,
+			},
+		}, {
+			UID:      6,
+			TestType: testPrune,
+			Description: `
+Cache.Prune should keep the entry and only remove its own stale strays when:
+1. the entry was used recently.
+2. the directory has stale and active temporary files, an orphaned key
+   file and files not named by the cache.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      7,
+			TestType: testPrune,
+			Description: `
+Cache.Prune should remove the entry and only its own stale strays when:
+1. the entry was unused for longer than the given age.
+2. the directory has stale and active temporary files, an orphaned key
+   file and files not named by the cache.
+`,
+			Switches: map[string]bool{
+				useUnusedEntry: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testClear,
+			Description: `
+Cache.Clear should remove the entry and only its own stale strays when:
+1. the entry was used recently.
+2. the directory has stale and active temporary files, an orphaned key
+   file and files not named by the cache.
+`,
+			Switches: map[string]bool{},
+		},
+	}
+}
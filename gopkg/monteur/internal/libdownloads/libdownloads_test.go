@@ -0,0 +1,244 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libdownloads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	testFetch = "testFetch"
+	testPrune = "testPrune"
+	testClear = "testClear"
+)
+
+const (
+	useChecksum       = "useChecksum"
+	useMissingEntry   = "useMissingEntry"
+	useCorruptedEntry = "useCorruptedEntry"
+	useForgedEntry    = "useForgedEntry"
+	useUnusedEntry    = "useUnusedEntry"
+
+	expectError = "expectError"
+	expectFound = "expectFound"
+)
+
+const (
+	fileURL     = "https://example.com/tool.tar.gz"
+	fileArchive = "tool.tar.gz"
+	fileContent = "monteur downloaded archive"
+	pruneAge    = 24 * time.Hour
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createSource() *libmonteur.TOMLSource {
+	source := &libmonteur.TOMLSource{
+		URL:     fileURL,
+		Archive: fileArchive,
+	}
+
+	if !s.Switches[useChecksum] {
+		return source
+	}
+
+	sum := sha256.Sum256([]byte(fileContent))
+	source.Checksum = &libmonteur.TOMLChecksum{
+		Type:   libmonteur.CHECKSUM_ALGO_SHA256,
+		Format: libmonteur.CHECKSUM_FORMAT_HEX,
+		Value:  hex.EncodeToString(sum[:]),
+	}
+
+	return source
+}
+
+func (s *testScenario) createCache(t *testing.T,
+	source *libmonteur.TOMLSource) (cache *Cache, key string) {
+	cache = &Cache{Dir: t.TempDir()}
+
+	key, err := Key(source)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	if s.Switches[useMissingEntry] {
+		return cache, key
+	}
+
+	path := filepath.Join(t.TempDir(), fileArchive)
+	err = os.WriteFile(path, []byte(fileContent), 0644)
+	if err == nil {
+		err = cache.Store(key, source, path)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to store test download: %s", err)
+	}
+
+	s.tamper(t, cache, key)
+
+	return cache, key
+}
+
+func (s *testScenario) tamper(t *testing.T, cache *Cache, key string) {
+	data := []byte("tampered")
+
+	switch {
+	case s.Switches[useCorruptedEntry]:
+	case s.Switches[useForgedEntry]:
+		// forge the recorded SHA256 to match the tampered file
+		entry, _ := cache.read(key)
+		sum := sha256.Sum256(data)
+		entry.SHA256 = hex.EncodeToString(sum[:])
+		raw, _ := json.Marshal(entry)
+		_ = os.WriteFile(cache.path(key)+EXTENSION_ENTRY, raw, 0644)
+	default:
+		return
+	}
+
+	err := os.WriteFile(cache.path(key), data, 0644)
+	if err != nil {
+		t.Fatalf("failed to tamper test download: %s", err)
+	}
+}
+
+func (s *testScenario) assertFetch(th *thelper.THelper,
+	cache *Cache, key string, destination string,
+	entry *Entry, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	th.ExpectSameBool("found", entry != nil,
+		"expected found", s.Switches[expectFound],
+	)
+
+	data, _ := os.ReadFile(destination)
+	if s.Switches[expectFound] {
+		th.ExpectSameStrings("fetched", string(data),
+			"expected", fileContent,
+		)
+	}
+
+	if !s.Switches[expectError] {
+		return
+	}
+
+	// rejected entry must be removed from cache
+	_, statErr := os.Stat(cache.path(key))
+	th.ExpectSameBool("removed", os.IsNotExist(statErr),
+		"expected removed", true,
+	)
+}
+
+// createStrays fills the cache directory with files that are not entries and
+// reports whether each of them shall survive the cleanup.
+func (s *testScenario) createStrays(t *testing.T,
+	cache *Cache) (strays map[string]bool) {
+	old := time.Now().Add(-2 * STRAY_AGE)
+	strays = map[string]bool{
+		PREFIX_TEMP + "stale":   false,
+		PREFIX_TEMP + "active":  true,
+		PREFIX_URL + "0123abcd": false,
+		"README":                true,
+		"notes":                 true,
+	}
+
+	for name := range strays {
+		path := filepath.Join(cache.Dir, name)
+
+		var err error
+
+		if name == "notes" {
+			err = os.Mkdir(path, 0755)
+		} else {
+			err = os.WriteFile(path, []byte(fileContent), 0644)
+		}
+
+		if err == nil && name != PREFIX_TEMP+"active" {
+			err = os.Chtimes(path, old, old)
+		}
+
+		if err != nil {
+			t.Fatalf("failed to create stray file: %s", err)
+		}
+	}
+
+	return strays
+}
+
+// useEntry is to age the stored entry's last usage when requested.
+func (s *testScenario) useEntry(t *testing.T, cache *Cache, key string) {
+	if !s.Switches[useUnusedEntry] {
+		return
+	}
+
+	entry, err := cache.read(key)
+	if err == nil && entry != nil {
+		entry.LastUsed = time.Now().Add(-2 * pruneAge)
+		err = cache.write(entry)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to age test download: %s", err)
+	}
+}
+
+func (s *testScenario) assertCleanup(th *thelper.THelper,
+	cache *Cache,
+	key string,
+	strays map[string]bool,
+	list []*Entry,
+	err error) {
+	removed := s.TestType == testClear || s.Switches[useUnusedEntry]
+
+	th.ExpectError(err, false)
+	th.ExpectSameBool("listed", len(list) == 1,
+		"expected listed", removed,
+	)
+
+	for _, path := range []string{
+		cache.path(key),
+		cache.path(key) + EXTENSION_ENTRY,
+	} {
+		_, statErr := os.Stat(path)
+		th.ExpectSameBool("kept "+filepath.Base(path), statErr == nil,
+			"expected kept "+filepath.Base(path), !removed,
+		)
+	}
+
+	for name, kept := range strays {
+		_, statErr := os.Stat(filepath.Join(cache.Dir, name))
+		th.ExpectSameBool("kept "+name, statErr == nil,
+			"expected kept "+name, kept,
+		)
+	}
+}
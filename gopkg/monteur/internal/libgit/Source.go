@@ -0,0 +1,81 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libgit
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/gitclient"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libchecksum"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// Source is to checkout the pinned commit of a git repository.
+//
+// The repository is cloned into the Archive directory inside the working
+// directory and fetched incrementally on the subsequent runs.
+func Source(ctx context.Context,
+	source *libmonteur.TOMLSource,
+	variables map[string]interface{},
+	log *liblog.Logger,
+	_ func(downloaded int64, total int64),
+	_ libchecksum.Hasher) (err error) {
+	var ok bool
+	var directory, commit string
+
+	log.Info("Sourcing %s using git...", source.URL)
+
+	if source.Git == nil {
+		return fmt.Errorf("%s: Commit = ''", libmonteur.ERROR_PROGRAM_GIT_BAD)
+	}
+
+	// extract Raw directory pathing
+	directory, ok = variables[libmonteur.VAR_TMP].(string)
+	if !ok {
+		panic("MONTEUR DEV: why is VAR_TMP not assigned?")
+	}
+
+	// setup repository
+	repo := &gitclient.Repository{
+		Directory: filepath.Join(directory, source.Archive),
+		URL:       source.URL,
+		Ref:       source.Git.Ref,
+		Commit:    source.Git.Commit,
+	}
+
+	repo.Offline, _ = variables[libmonteur.VAR_OFFLINE].(bool)
+
+	if source.Network != nil {
+		repo.Proxy = source.Network.Proxy
+	}
+
+	log.Info("Git Directory: %v", repo.Directory)
+	log.Info("Git Ref: %v", repo.Ref)
+	log.Info("Git Commit: %v", repo.Commit)
+	log.Info("Git Offline: %v", repo.Offline)
+
+	commit, err = repo.Checkout(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_PROGRAM_GIT_FAILED, err)
+	}
+
+	log.Info("%s ➤ checked out commit %s", source.URL, commit)
+
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// Run is a single Job execution record in a stage's history file.
+//
+// Each Run is written as a single line of JSON (JSON Lines) and all durations
+// are in milliseconds.
+//
+// Run is safe to be created using the standard `&struct{}` method.
+type Run struct {
+	Timestamp string  `json:"timestamp"`
+	Stage     string  `json:"stage"`
+	Status    string  `json:"status"`
+	Tasks     []*Task `json:"tasks"`
+	Duration  int64   `json:"duration_ms"`
+}
+
+// Task is the execution record of a single task inside a Run.
+type Task struct {
+	Name     string  `json:"name"`
+	Status   string  `json:"status"`
+	Steps    []*Step `json:"steps"`
+	Duration int64   `json:"duration_ms"`
+}
+
+// Step is the execution record of a single `[[CMD]]` step inside a Task.
+type Step struct {
+	Name     string `json:"name"`
+	Index    int    `json:"index"`
+	ExitCode int    `json:"exit_code"`
+	Duration int64  `json:"duration_ms"`
+}
+
+// Add is to add a task's execution record into the Run.
+//
+// Unfinished steps are not recorded.
+func (me *Run) Add(result *conductor.Result) {
+	task := &Task{
+		Name:     result.Name,
+		Status:   result.Status,
+		Steps:    []*Step{},
+		Duration: result.Duration.Milliseconds(),
+	}
+
+	for _, step := range result.Steps {
+		if !step.Finished {
+			continue
+		}
+
+		task.Steps = append(task.Steps, &Step{
+			Name:     step.Name,
+			Index:    step.Index,
+			ExitCode: step.ExitCode,
+			Duration: step.Duration.Milliseconds(),
+		})
+	}
+
+	me.Tasks = append(me.Tasks, task)
+}
+
+// Save is to append the Run into the given history file.
+//
+// The history file and its directory are created when absent.
+func (me *Run) Save(path string) (err error) {
+	var f *os.File
+	var data []byte
+
+	data, err = json.Marshal(me)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_HISTORY_WRITE, err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), libmonteur.PERMISSION_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_HISTORY_WRITE, err)
+	}
+
+	f, err = os.OpenFile(path,
+		os.O_WRONLY|os.O_CREATE|os.O_APPEND,
+		libmonteur.PERMISSION_FILE,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_HISTORY_WRITE, err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_HISTORY_WRITE, err)
+	}
+
+	return nil
+}
+
+// Time is to obtain the Run's parsed timestamp.
+func (me *Run) Time() time.Time {
+	t, _ := time.Parse(time.RFC3339, me.Timestamp)
+	return t
+}
+
+// Filename is to generate the history filename of a stage.
+func Filename(stage string) string {
+	return stage + libmonteur.EXTENSION_JSONL
+}
+
+// Load is to read all the Runs from the given history file, oldest first.
+//
+// Malformed lines (e.g. from an interrupted write) are skipped.
+func Load(path string) (list []*Run, err error) {
+	var f *os.File
+
+	f, err = os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_HISTORY_MISSING,
+			path,
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_HISTORY_READ, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		run := &Run{}
+
+		err = json.Unmarshal(scanner.Bytes(), run)
+		if err != nil {
+			continue
+		}
+
+		list = append(list, run)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_HISTORY_READ, err)
+	}
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_HISTORY_MISSING,
+			path,
+		)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhistory
+
+import (
+	"sort"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+)
+
+const (
+	// DEFAULT_WINDOW is the default number of previous Runs for baseline.
+	DEFAULT_WINDOW = 10
+
+	// DEFAULT_THRESHOLD is the default regression threshold in percent.
+	DEFAULT_THRESHOLD = 20
+
+	// SLOWEST_STEPS is the number of slowest steps to be presented.
+	SLOWEST_STEPS = 10
+
+	// REGRESSION_MIN_DELTA is the minimum slowdown to be flagged so that
+	// jitters of very short tasks and steps are ignored.
+	REGRESSION_MIN_DELTA = 100 * time.Millisecond
+)
+
+// Trend is the timing trend of a stage, task, or step across Runs.
+type Trend struct {
+	// Name is the trend's subject (e.g. `Task ➤ Step`)
+	Name string
+
+	// History are the previous Runs' durations (oldest first) followed by
+	// the latest duration
+	History []time.Duration
+
+	// Latest is the latest Run's duration
+	Latest time.Duration
+
+	// Median is the median duration of the previous passed Runs
+	Median time.Duration
+
+	// Change is the Latest's difference from Median in percent
+	Change float64
+
+	// Regressed is set when Change exceeds the analysis threshold
+	Regressed bool
+}
+
+// Stats is the timing analysis of a stage's history.
+type Stats struct {
+	// Latest is the analyzed latest Run
+	Latest *Run
+
+	// Stage is the stage's wall time trend
+	Stage *Trend
+
+	// Critical is the latest Run's longest task dictating the stage's wall
+	// time since all tasks are executed in parallel
+	Critical *Task
+
+	// Tasks are the latest Run's tasks' trends sorted by name
+	Tasks []*Trend
+
+	// Steps are the latest Run's steps' trends from the slowest
+	Steps []*Trend
+
+	// Regressions are all the regressed stage, tasks, and steps trends
+	Regressions []*Trend
+
+	// Runs is the number of Runs being analyzed including the latest
+	Runs int
+}
+
+// Analyze is to analyze the latest Run against its previous Runs.
+//
+// The `window` is the maximum number of previous Runs used as the baseline
+// and only passed records are counted in. A trend is regressed when its latest
+// duration exceeds the baseline's median by more than `threshold` percent and
+// REGRESSION_MIN_DELTA.
+func Analyze(runs []*Run, window uint, threshold uint) (stats *Stats) {
+	if len(runs) == 0 {
+		return &Stats{}
+	}
+
+	latest := runs[len(runs)-1]
+	previous := runs[:len(runs)-1]
+	if uint(len(previous)) > window {
+		previous = previous[uint(len(previous))-window:]
+	}
+
+	stats = &Stats{
+		Latest: latest,
+		Runs:   len(previous) + 1,
+	}
+
+	stats.Stage = _trend(latest.Stage, threshold,
+		_ms(latest.Duration),
+		func(run *Run) (time.Duration, bool, bool) {
+			return _ms(run.Duration),
+				run.Status == conductor.RESULT_PASSED,
+				true
+		},
+		previous,
+	)
+
+	for _, task := range latest.Tasks {
+		stats.Tasks = append(stats.Tasks, _trend(task.Name, threshold,
+			_ms(task.Duration),
+			_taskDuration(task.Name),
+			previous,
+		))
+
+		if stats.Critical == nil || task.Duration > stats.Critical.Duration {
+			stats.Critical = task
+		}
+
+		for _, step := range task.Steps {
+			stats.Steps = append(stats.Steps, _trend(
+				task.Name+" ➤ "+step.Name,
+				threshold,
+				_ms(step.Duration),
+				_stepDuration(task.Name, step.Name),
+				previous,
+			))
+		}
+	}
+
+	sort.Slice(stats.Tasks, func(i, j int) bool {
+		return stats.Tasks[i].Name < stats.Tasks[j].Name
+	})
+
+	sort.SliceStable(stats.Steps, func(i, j int) bool {
+		return stats.Steps[i].Latest > stats.Steps[j].Latest
+	})
+
+	for _, list := range [][]*Trend{{stats.Stage}, stats.Tasks, stats.Steps} {
+		for _, trend := range list {
+			if trend.Regressed {
+				stats.Regressions = append(stats.Regressions, trend)
+			}
+		}
+	}
+
+	return stats
+}
+
+// _trend builds a Trend where `fx` extracts a previous Run's duration, its
+// eligibility as baseline, and its availability.
+func _trend(name string,
+	threshold uint,
+	latest time.Duration,
+	fx func(run *Run) (d time.Duration, passed bool, ok bool),
+	previous []*Run) (trend *Trend) {
+	var baseline []time.Duration
+
+	trend = &Trend{
+		Name:   name,
+		Latest: latest,
+	}
+
+	for _, run := range previous {
+		d, passed, ok := fx(run)
+		if !ok {
+			continue
+		}
+
+		trend.History = append(trend.History, d)
+		if passed {
+			baseline = append(baseline, d)
+		}
+	}
+
+	trend.History = append(trend.History, latest)
+	trend.Median = _median(baseline)
+
+	if trend.Median == 0 {
+		return trend
+	}
+
+	delta := latest - trend.Median
+	trend.Change = float64(delta) / float64(trend.Median) * 100
+	trend.Regressed = delta >= REGRESSION_MIN_DELTA &&
+		trend.Change > float64(threshold)
+
+	return trend
+}
+
+func _taskDuration(name string) func(*Run) (time.Duration, bool, bool) {
+	return func(run *Run) (time.Duration, bool, bool) {
+		for _, task := range run.Tasks {
+			if task.Name == name {
+				return _ms(task.Duration),
+					task.Status == conductor.RESULT_PASSED,
+					true
+			}
+		}
+
+		return 0, false, false
+	}
+}
+
+func _stepDuration(taskName string,
+	stepName string) func(*Run) (time.Duration, bool, bool) {
+	return func(run *Run) (time.Duration, bool, bool) {
+		for _, task := range run.Tasks {
+			if task.Name != taskName {
+				continue
+			}
+
+			for _, step := range task.Steps {
+				if step.Name == stepName {
+					return _ms(step.Duration),
+						step.ExitCode == 0,
+						true
+				}
+			}
+		}
+
+		return 0, false, false
+	}
+}
+
+func _median(list []time.Duration) time.Duration {
+	if len(list) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration{}, list...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	i := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[i]
+	}
+
+	return (sorted[i-1] + sorted[i]) / 2
+}
+
+func _ms(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
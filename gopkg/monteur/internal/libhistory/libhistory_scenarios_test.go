@@ -0,0 +1,137 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhistory
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testAnalyze,
+			Description: `
+Analyze should return empty stats when:
+1. the history is empty.
+`,
+			Switches: map[string]bool{
+				useNoRuns: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testAnalyze,
+			Description: `
+Analyze should report trends without baseline and regressions when:
+1. the history only has the latest run.
+`,
+			Switches: map[string]bool{
+				useSingleRun: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testAnalyze,
+			Description: `
+Analyze should flag the stage, task and step regressions when:
+1. the previous runs all passed.
+2. the latest run is slower beyond the threshold.
+3. a short task is slower only by jitter.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      4,
+			TestType: testAnalyze,
+			Description: `
+Analyze should exclude the failed runs from the baseline when:
+1. the previous runs have passed and much slower failed runs.
+2. the latest run is slower beyond the threshold.
+`,
+			Switches: map[string]bool{
+				useFailedRuns: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testAnalyze,
+			Description: `
+Analyze should exclude the cancelled runs from the baseline when:
+1. the previous runs have passed and much faster cancelled runs.
+2. the cancelled runs have no finished steps.
+3. the latest run is slower beyond the threshold.
+`,
+			Switches: map[string]bool{
+				useCancelledRuns: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testAnalyze,
+			Description: `
+Analyze should not flag any regression when:
+1. the previous runs all failed.
+2. the latest run is slower.
+`,
+			Switches: map[string]bool{
+				useOnlyFailedRuns: true,
+			},
+		}, {
+			UID:      7,
+			TestType: testAnalyze,
+			Description: `
+Analyze should only use the window's previous runs when:
+1. the history has more previous runs than the window.
+`,
+			Switches: map[string]bool{
+				useWindow: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testMedian,
+			Description: `
+_median should return zero when:
+1. the list is empty.
+`,
+			Switches: map[string]bool{
+				useEmptyList: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testMedian,
+			Description: `
+_median should return the item when:
+1. the list has a single item.
+`,
+			Switches: map[string]bool{
+				useSingleItem: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testMedian,
+			Description: `
+_median should return the middle item without sorting the list when:
+1. the unsorted list has an odd number of items.
+`,
+			Switches: map[string]bool{
+				useOddItems: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testMedian,
+			Description: `
+_median should return the middle items' average without sorting the list
+when:
+1. the unsorted list has an even number of items.
+`,
+			Switches: map[string]bool{
+				useEvenItems: true,
+			},
+		},
+	}
+}
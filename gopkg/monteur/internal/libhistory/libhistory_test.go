@@ -0,0 +1,276 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhistory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+)
+
+const (
+	testAnalyze = "testAnalyze"
+	testMedian  = "testMedian"
+)
+
+const (
+	useNoRuns         = "useNoRuns"
+	useSingleRun      = "useSingleRun"
+	useFailedRuns     = "useFailedRuns"
+	useCancelledRuns  = "useCancelledRuns"
+	useOnlyFailedRuns = "useOnlyFailedRuns"
+	useWindow         = "useWindow"
+
+	useEmptyList  = "useEmptyList"
+	useSingleItem = "useSingleItem"
+	useOddItems   = "useOddItems"
+	useEvenItems  = "useEvenItems"
+)
+
+const (
+	taskBuild   = "build"
+	taskLint    = "lint"
+	stepCompile = "compile"
+	stepVet     = "vet"
+
+	passedRuns = 3
+	extraRuns  = 2
+	window     = 2
+	threshold  = 20
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createRun creates a run with the build and lint tasks in milliseconds.
+func (s *testScenario) createRun(status string,
+	stage int64,
+	build int64,
+	lint int64) *Run {
+	exitCode := 0
+	if status == conductor.RESULT_FAILED {
+		exitCode = 1
+	}
+
+	run := &Run{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Stage:     "test",
+		Status:    status,
+		Duration:  stage,
+		Tasks: []*Task{
+			{
+				Name:     taskLint,
+				Status:   conductor.RESULT_PASSED,
+				Duration: lint,
+				Steps: []*Step{
+					{Name: stepVet, Duration: lint / 2},
+				},
+			}, {
+				Name:     taskBuild,
+				Status:   status,
+				Duration: build,
+				Steps: []*Step{
+					{
+						Name:     stepCompile,
+						ExitCode: exitCode,
+						Duration: build - 300,
+					},
+				},
+			},
+		},
+	}
+
+	if status == conductor.RESULT_CANCELLED {
+		// unfinished steps are never recorded
+		run.Tasks[1].Steps = []*Step{}
+	}
+
+	return run
+}
+
+// createRuns creates the history from the oldest run to the latest run.
+func (s *testScenario) createRuns() (runs []*Run, size uint) {
+	size = DEFAULT_WINDOW
+	if s.Switches[useWindow] {
+		size = window
+	}
+
+	switch {
+	case s.Switches[useNoRuns]:
+		return nil, size
+	case s.Switches[useSingleRun]:
+	case s.Switches[useOnlyFailedRuns]:
+		for i := 0; i < extraRuns; i++ {
+			runs = append(runs, s.createRun(conductor.RESULT_FAILED,
+				1000, 800, 100,
+			))
+		}
+	default:
+		for i := 0; i < passedRuns; i++ {
+			runs = append(runs, s.createRun(conductor.RESULT_PASSED,
+				1000+int64(i)*10, 800, 100,
+			))
+		}
+	}
+
+	for i := 0; i < extraRuns; i++ {
+		switch {
+		case s.Switches[useFailedRuns]:
+			runs = append(runs, s.createRun(conductor.RESULT_FAILED,
+				9000, 8800, 100,
+			))
+		case s.Switches[useCancelledRuns]:
+			runs = append(runs, s.createRun(
+				conductor.RESULT_CANCELLED,
+				100, 50, 100,
+			))
+		}
+	}
+
+	runs = append(runs, s.createRun(conductor.RESULT_PASSED,
+		2000, 1800, 150,
+	))
+
+	return runs, size
+}
+
+func (s *testScenario) assertAnalyze(th *thelper.THelper,
+	runs []*Run,
+	stats *Stats) {
+	var regressions []string
+
+	if s.Switches[useNoRuns] {
+		th.ExpectSameBool("empty", stats.Latest == nil &&
+			stats.Stage == nil &&
+			stats.Critical == nil &&
+			stats.Runs == 0 &&
+			len(stats.Tasks) == 0 &&
+			len(stats.Steps) == 0 &&
+			len(stats.Regressions) == 0,
+			"expected empty", true,
+		)
+
+		return
+	}
+
+	previous := len(runs) - 1
+	if s.Switches[useWindow] {
+		previous = window
+	}
+
+	// failed and cancelled runs are recorded but never the baseline
+	median := 1010 * time.Millisecond
+	steps := previous + 1
+	switch {
+	case s.Switches[useSingleRun] || s.Switches[useOnlyFailedRuns]:
+		median = 0
+	case s.Switches[useWindow]:
+		median = 1015 * time.Millisecond
+	case s.Switches[useCancelledRuns]:
+		steps -= extraRuns
+	}
+
+	if median != 0 {
+		regressions = []string{
+			"test",
+			taskBuild,
+			taskBuild + " ➤ " + stepCompile,
+		}
+	}
+
+	th.ExpectSameBool("latest", stats.Latest == runs[len(runs)-1],
+		"expected latest", true,
+	)
+	th.ExpectSameStrings("runs", fmt.Sprint(stats.Runs),
+		"expected runs", fmt.Sprint(previous+1),
+	)
+	th.ExpectSameStrings("stage history",
+		fmt.Sprint(len(stats.Stage.History)),
+		"expected stage history", fmt.Sprint(previous+1),
+	)
+	th.ExpectSameStrings("stage median", stats.Stage.Median.String(),
+		"expected stage median", median.String(),
+	)
+	th.ExpectSameStrings("critical", stats.Critical.Name,
+		"expected critical", taskBuild,
+	)
+	th.ExpectSameStrings("tasks", s.names(stats.Tasks),
+		"expected tasks", fmt.Sprint([]string{taskBuild, taskLint}),
+	)
+	th.ExpectSameStrings("steps", s.names(stats.Steps),
+		"expected steps", fmt.Sprint([]string{
+			taskBuild + " ➤ " + stepCompile,
+			taskLint + " ➤ " + stepVet,
+		}),
+	)
+	th.ExpectSameStrings("step history",
+		fmt.Sprint(len(stats.Steps[0].History)),
+		"expected step history", fmt.Sprint(steps),
+	)
+	th.ExpectSameStrings("regressions", s.names(stats.Regressions),
+		"expected regressions", fmt.Sprint(regressions),
+	)
+}
+
+func (s *testScenario) names(list []*Trend) string {
+	names := []string{}
+	for _, trend := range list {
+		names = append(names, trend.Name)
+	}
+
+	return fmt.Sprint(names)
+}
+
+// createDurations is to get the unsorted list alongside its median.
+func (s *testScenario) createDurations() (list []time.Duration,
+	expect time.Duration) {
+	switch {
+	case s.Switches[useEmptyList]:
+		return nil, 0
+	case s.Switches[useSingleItem]:
+		return []time.Duration{5}, 5
+	case s.Switches[useOddItems]:
+		return []time.Duration{9, 1, 5}, 5
+	case s.Switches[useEvenItems]:
+		return []time.Duration{9, 1, 6, 2}, 4
+	}
+
+	return nil, 0
+}
+
+func (s *testScenario) assertMedian(th *thelper.THelper,
+	list []time.Duration,
+	original string,
+	median time.Duration,
+	expect time.Duration) {
+	th.ExpectSameStrings("median", median.String(),
+		"expected median", expect.String(),
+	)
+	th.ExpectSameStrings("list", fmt.Sprint(list),
+		"unsorted list", original,
+	)
+}
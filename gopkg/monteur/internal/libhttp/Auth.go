@@ -0,0 +1,58 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhttp
+
+import (
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/httpclient"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// _auth converts the sanitized source's Auth into the downloader's one.
+func _auth(auth *libmonteur.TOMLAuth, log *liblog.Logger) *httpclient.Auth {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Type {
+	case libmonteur.PROGRAM_AUTH_BASIC:
+		log.Info("Downloader Auth: %s (%s)", auth.Type, auth.Username)
+
+		return &httpclient.Auth{
+			Username: auth.Username,
+			Password: auth.Password,
+		}
+	case libmonteur.PROGRAM_AUTH_BEARER:
+		log.Info("Downloader Auth: %s", auth.Type)
+
+		return &httpclient.Auth{
+			Token: auth.Token,
+		}
+	case libmonteur.PROGRAM_AUTH_NETRC:
+		path := auth.Netrc
+		if path == "" {
+			path = httpclient.NetrcPath()
+		}
+
+		log.Info("Downloader Auth: %s (%s)", auth.Type, path)
+
+		return &httpclient.Auth{
+			Netrc: path,
+		}
+	}
+
+	return nil
+}
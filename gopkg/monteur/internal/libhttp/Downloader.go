@@ -17,11 +17,15 @@ package libhttp
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/httpclient"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libchecksum"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libdownloads"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
 )
@@ -29,9 +33,66 @@ import (
 func Source(ctx context.Context, source *libmonteur.TOMLSource,
 	variables map[string]interface{},
 	log *liblog.Logger,
+	progress func(downloaded int64, total int64),
 	cs libchecksum.Hasher) (err error) {
+	return VerifiedSource(ctx, source, variables, log, progress, cs, nil)
+}
+
+// VerifiedSource is Source with an additional verify function checking the
+// downloaded file at path (e.g. against `setup.lock`).
+//
+// The download is only saved into the user level download cache after both
+// its signature and the verify function accepted it.
+func VerifiedSource(ctx context.Context, source *libmonteur.TOMLSource,
+	variables map[string]interface{},
+	log *liblog.Logger,
+	progress func(downloaded int64, total int64),
+	cs libchecksum.Hasher,
+	verify func(path string) error) (err error) {
+	var cache *libdownloads.Cache
+	var key string
+
+	cache, key, err = _download(ctx, source, variables, log, progress, cs)
+	if err != nil {
+		return err
+	}
+
+	destination, _ := variables[libmonteur.VAR_TMP].(string)
+	destination = filepath.Join(destination, source.Archive)
+
+	// verify before the source is unpacked or cached
+	switch {
+	case source.Signature != nil:
+		err = _verify(ctx, source, variables, log, verify)
+	case verify != nil:
+		err = verify(destination)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// save into the user level download cache for other runs
+	_store(cache, key, source, destination, log)
+
+	return nil
+}
+
+// _download fetches the source into the VAR_TMP directory.
+//
+// The returned cache is nil unless the source was freshly downloaded and shall
+// be stored once it is verified.
+func _download(ctx context.Context, source *libmonteur.TOMLSource,
+	variables map[string]interface{},
+	log *liblog.Logger,
+	progress func(downloaded int64, total int64),
+	cs libchecksum.Hasher) (cache *libdownloads.Cache,
+	key string,
+	err error) {
 	var ok bool
 	var destination string
+	var entry *libdownloads.Entry
+	var offline bool
 
 	log.Info("Sourcing %s using HTTPS download...", source.URL)
 
@@ -50,12 +111,47 @@ func Source(ctx context.Context, source *libmonteur.TOMLSource,
 		libmonteur.PERMISSION_DIRECTORY,
 	)
 
+	// reuse the user level download cache when available
+	offline, _ = variables[libmonteur.VAR_OFFLINE].(bool)
+	cache, key = _openCache(source, log)
+	if cache != nil {
+		entry, err = cache.Fetch(key, source, destination)
+		switch {
+		case err != nil && offline:
+			return nil, "", err //nolint:wrapcheck
+		case err != nil:
+			log.Warning("%s. Downloading again...", err)
+			err = nil
+		case entry != nil:
+			log.Info("%s ➤ reused from download cache '%s'",
+				source.URL,
+				cache.Dir,
+			)
+
+			if progress != nil {
+				progress(entry.Size, entry.Size)
+			}
+
+			return nil, "", nil
+		}
+	}
+
+	if offline {
+		return nil, "", fmt.Errorf("%s: %s",
+			libmonteur.ERROR_DOWNLOADS_OFFLINE,
+			source.OriginURL(),
+		)
+	}
+
 	// setup downloader
-	d := &httpclient.Downloader{
-		Destination: destination,
-		Headers:     source.Headers,
+	d, err := _newDownloader(source, destination, log)
+	if err != nil {
+		return nil, "", err
 	}
 
+	d.Fallbacks = source.Fallbacks
+	d.Segments = _segments(variables)
+
 	d.HandleError = func(e error) {
 		err = e
 	}
@@ -64,6 +160,14 @@ func Source(ctx context.Context, source *libmonteur.TOMLSource,
 		log.Info("%s ➤ download completed", source.URL)
 	}
 
+	d.HandleRetry = func(url string, e error, delay time.Duration) {
+		log.Warning("%s ➤ %s. Retrying in %s...",
+			url,
+			e,
+			delay.Round(time.Millisecond),
+		)
+	}
+
 	d.HandleProgress = func(downloaded, total int64) {
 		percent := float64(downloaded) / float64(total) * 100
 
@@ -72,6 +176,10 @@ func Source(ctx context.Context, source *libmonteur.TOMLSource,
 			total,
 			percent,
 		)
+
+		if progress != nil {
+			progress(downloaded, total)
+		}
 	}
 
 	log.Info("Downloader Destination: %v", d.Destination)
@@ -79,18 +187,100 @@ func Source(ctx context.Context, source *libmonteur.TOMLSource,
 	log.Info("Downloader HandleProgress: %v", d.HandleProgress)
 	log.Info("Downloader Method: %v", source.Method)
 	log.Info("Downloader URL: %v", source.URL)
+	log.Info("Downloader Fallbacks: %v", source.Fallbacks)
+	log.Info("Downloader Segments: %v", d.Segments)
 	log.Info("Downloader Checksum: %v", cs)
 
 	if len(d.Headers) == 0 {
 		log.Info("Downloader Headers: {}")
 	} else {
 		for k, v := range d.Headers {
+			if httpclient.IsSensitiveHeader(k) {
+				v = "xxxxx"
+			}
+
 			log.Info("  '%s': '%s'", k, v)
 		}
 	}
 
 	log.Info("Begin downloading...")
 	d.Download(ctx, source.Method, source.URL, cs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return cache, key, nil
+}
+
+// _store saves the verified download at path into the user level download
+// cache. Failures only disable the caching so they are logged as warnings.
+func _store(cache *libdownloads.Cache,
+	key string,
+	source *libmonteur.TOMLSource,
+	path string,
+	log *liblog.Logger) {
+	if cache == nil {
+		return
+	}
+
+	err := cache.Store(key, source, path)
+	if err != nil {
+		log.Warning("%s", err)
+	}
+}
+
+// _newDownloader creates the downloader with the source's network, credentials
+// and redirect settings.
+func _newDownloader(source *libmonteur.TOMLSource,
+	destination string,
+	log *liblog.Logger) (d *httpclient.Downloader, err error) {
+	d = &httpclient.Downloader{
+		Destination: destination,
+		Headers:     source.Headers,
+		Origin:      source.OriginURL(),
+	}
+
+	err = _network(d, source.Network, log)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Auth = _auth(source.Auth, log)
+
+	d.HandleRedirect = func(req *http.Request, via []*http.Request) error {
+		log.Info("%s ➤ redirected to %s", via[0].URL, req.URL.Host)
+		return nil
+	}
+
+	return d, nil
+}
+
+func _segments(variables map[string]interface{}) uint {
+	value := variables[libmonteur.VAR_DOWNLOADS]
+
+	downloads, ok := value.(*libmonteur.TOMLDownloads)
+	if !ok || downloads == nil {
+		return 0
+	}
+
+	return downloads.Segments
+}
+
+func _openCache(source *libmonteur.TOMLSource,
+	log *liblog.Logger) (cache *libdownloads.Cache, key string) {
+	var err error
+
+	cache, err = libdownloads.Open()
+	if err != nil {
+		log.Warning("%s. Download cache is disabled.", err)
+		return nil, ""
+	}
+
+	key, err = libdownloads.Key(source)
+	if err != nil {
+		log.Warning("%s. Download cache is disabled.", err)
+		return nil, ""
+	}
 
-	return err
+	return cache, key
 }
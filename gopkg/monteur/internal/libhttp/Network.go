@@ -0,0 +1,163 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/httpclient"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	pemPrefix = "-----BEGIN"
+)
+
+// _network applies the network settings onto the downloader.
+func _network(d *httpclient.Downloader,
+	network *libmonteur.TOMLNetwork,
+	log *liblog.Logger) (err error) {
+	if network == nil {
+		return nil
+	}
+
+	if network.Proxy != "" {
+		d.Proxy = network.Proxy
+		log.Info("Downloader Proxy: %s", _redact(network.Proxy))
+	}
+
+	if len(network.CAFiles) == 0 && network.Cert == "" && !network.Insecure {
+		return nil
+	}
+
+	d.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if len(network.CAFiles) > 0 {
+		d.TLS.RootCAs, err = _certPool(network.CAFiles)
+		if err != nil {
+			return err
+		}
+
+		log.Info("Downloader CA Files: %v", network.CAFiles)
+	}
+
+	if network.Cert != "" {
+		err = _clientCert(d.TLS, network)
+		if err != nil {
+			return err
+		}
+
+		log.Info("Downloader Client Certificate: loaded")
+	}
+
+	if network.Insecure {
+		d.TLS.InsecureSkipVerify = true //nolint:gosec
+		log.Warning("TLS verification is disabled by Network.Insecure!")
+	}
+
+	return nil
+}
+
+func _certPool(files []string) (pool *x509.CertPool, err error) {
+	var data []byte
+
+	pool, err = x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, path := range files {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_NETWORK_CA,
+				err,
+			)
+		}
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("%s: no certificate found in %s",
+				libmonteur.ERROR_PROGRAM_NETWORK_CA,
+				path,
+			)
+		}
+	}
+
+	return pool, nil
+}
+
+func _clientCert(config *tls.Config,
+	network *libmonteur.TOMLNetwork) (err error) {
+	var cert tls.Certificate
+	var certPEM, keyPEM []byte
+
+	certPEM, err = _pem(network.Cert)
+	if err != nil {
+		return err
+	}
+
+	keyPEM, err = _pem(network.Key)
+	if err != nil {
+		return err
+	}
+
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			libmonteur.ERROR_PROGRAM_NETWORK_CERT,
+			err,
+		)
+	}
+
+	config.Certificates = []tls.Certificate{cert}
+
+	return nil
+}
+
+// _pem reads the given value as a file path unless it is already PEM data.
+func _pem(value string) (data []byte, err error) {
+	if strings.HasPrefix(strings.TrimSpace(value), pemPrefix) {
+		return []byte(value), nil
+	}
+
+	data, err = os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_PROGRAM_NETWORK_CERT,
+			err,
+		)
+	}
+
+	return data, nil
+}
+
+// _redact hides the credentials in the given URL for logging.
+func _redact(urlStr string) string {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return "<unparsable>"
+	}
+
+	return u.Redacted()
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libdownloads"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsignature"
+)
+
+// _verify checks the downloaded source against its detached signature and
+// then the optional verify function before the signature is cached.
+func _verify(ctx context.Context,
+	source *libmonteur.TOMLSource,
+	variables map[string]interface{},
+	log *liblog.Logger,
+	verify func(path string) error) (err error) {
+	var data []byte
+	var cache *libdownloads.Cache
+	var key string
+
+	destination, _ := variables[libmonteur.VAR_TMP].(string)
+	destination = filepath.Join(destination, source.Archive)
+	path := destination + libmonteur.EXTENSION_SIG
+	sig := source.Signature
+	defer os.Remove(path)
+
+	data = []byte(sig.Value)
+	if sig.Value == "" {
+		data, cache, key, err = _fetchSignature(ctx,
+			source,
+			path,
+			variables,
+			log,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = libsignature.Verify(sig, data, destination)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	log.Info("%s ➤ %s signature verified", source.URL, sig.Type)
+
+	if verify != nil {
+		err = verify(destination)
+		if err != nil {
+			return err
+		}
+	}
+
+	_store(cache, key, &libmonteur.TOMLSource{URL: sig.URL}, path, log)
+
+	return nil
+}
+
+// _fetchSignature obtains the signature data from the download cache or its
+// URL otherwise.
+//
+// Like `_download`, the returned cache is only set for a fresh download.
+func _fetchSignature(ctx context.Context,
+	source *libmonteur.TOMLSource,
+	path string,
+	variables map[string]interface{},
+	log *liblog.Logger) (data []byte,
+	cache *libdownloads.Cache,
+	key string,
+	err error) {
+	var entry *libdownloads.Entry
+
+	sig := &libmonteur.TOMLSource{URL: source.Signature.URL}
+	offline, _ := variables[libmonteur.VAR_OFFLINE].(bool)
+
+	cache, key = _openCache(sig, log)
+	if cache != nil {
+		entry, err = cache.Fetch(key, sig, path)
+		switch {
+		case err != nil && offline:
+			return nil, nil, "", err //nolint:wrapcheck
+		case err != nil:
+			log.Warning("%s. Downloading again...", err)
+			err = nil
+		case entry != nil:
+			data, err = _readSignature(path)
+			return data, nil, "", err
+		}
+	}
+
+	if offline {
+		return nil, nil, "", fmt.Errorf("%s: %s",
+			libmonteur.ERROR_DOWNLOADS_OFFLINE,
+			sig.URL,
+		)
+	}
+
+	d, err := _newDownloader(source, path, log)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	d.HandleError = func(e error) {
+		err = e
+	}
+
+	log.Info("Downloading signature %s...", sig.URL)
+	d.Download(ctx, http.MethodGet, sig.URL, nil)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %s",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_BAD,
+			err,
+		)
+	}
+
+	data, err = _readSignature(path)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return data, cache, key, nil
+}
+
+func _readSignature(path string) (data []byte, err error) {
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_BAD,
+			err,
+		)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhttp
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testSource,
+			Description: `
+Source should cache the archive and its signature when:
+1. the downloaded archive matches its signature URL.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testSource,
+			Description: `
+Source should fail and cache nothing when:
+1. the downloaded archive does not match its signature URL.
+`,
+			Switches: map[string]bool{
+				useTamperedArchive: true,
+				expectError:        true,
+			},
+		}, {
+			UID:      3,
+			TestType: testSource,
+			Description: `
+Source should cache the archive when:
+1. the downloaded archive matches its signature Value.
+`,
+			Switches: map[string]bool{
+				useSignatureValue: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testSource,
+			Description: `
+Source should fail and cache nothing when:
+1. the downloaded archive does not match its signature Value.
+`,
+			Switches: map[string]bool{
+				useSignatureValue:  true,
+				useTamperedArchive: true,
+				expectError:        true,
+			},
+		}, {
+			UID:      5,
+			TestType: testSource,
+			Description: `
+Source should cache the archive when:
+1. the source has no signature.
+`,
+			Switches: map[string]bool{
+				useUnsigned: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testSource,
+			Description: `
+Source should not cache anything when:
+1. the signature is valid.
+2. the verify function rejects the archive.
+`,
+			Switches: map[string]bool{
+				useRejectedVerify: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      7,
+			TestType: testSource,
+			Description: `
+Source should not cache anything when:
+1. the source has no signature.
+2. the verify function rejects the archive.
+`,
+			Switches: map[string]bool{
+				useUnsigned:       true,
+				useRejectedVerify: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      8,
+			TestType: testSource,
+			Description: `
+Source should fail without downloading when:
+1. the run is offline.
+2. the source is not in the download cache.
+`,
+			Switches: map[string]bool{
+				useOffline:  true,
+				expectError: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testSource,
+			Description: `
+Source should fail without downloading when:
+1. the run is offline.
+2. the source is not in the download cache.
+3. the source has no signature.
+`,
+			Switches: map[string]bool{
+				useOffline:  true,
+				useUnsigned: true,
+				expectError: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libhttp
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libdownloads"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/liblog"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+)
+
+const (
+	testSource = "testSource"
+)
+
+const (
+	useTamperedArchive = "useTamperedArchive"
+	useSignatureValue  = "useSignatureValue"
+	useUnsigned        = "useUnsigned"
+	useRejectedVerify  = "useRejectedVerify"
+	useOffline         = "useOffline"
+	expectError        = "expectError"
+)
+
+const (
+	testArchive   = "tool.tar.gz"
+	testContent   = "monteur signed artifact"
+	testKeyFile   = "minisign.pub"
+	testKeyID     = "monteur!"
+	testAlgo      = "Ed"
+	testComment   = "timestamp:1650000000\tfile:tool.tar.gz"
+	testUntrusted = "untrusted comment: "
+	testTrusted   = "trusted comment: "
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createServer serves the archive and its minisign signature.
+func (s *testScenario) createServer(t *testing.T,
+	signature []byte) *httptest.Server {
+	content := []byte(testContent)
+	if s.Switches[useTamperedArchive] {
+		content = []byte(testContent + "!")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+testArchive, func(w http.ResponseWriter,
+		r *http.Request) {
+		_, _ = w.Write(content)
+	})
+	mux.HandleFunc("/"+testArchive+libmonteur.EXTENSION_MINISIG,
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(signature)
+		},
+	)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// createSignature signs the untampered archive content with a new key pair and
+// saves the trusted public key into dir.
+func (s *testScenario) createSignature(t *testing.T,
+	dir string) (signature []byte, keyPath string) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %s", err)
+	}
+
+	raw := append(append([]byte(testAlgo), testKeyID...), public...)
+	keyPath = filepath.Join(dir, testKeyFile)
+
+	err = os.WriteFile(keyPath, []byte(testUntrusted+"minisign public key\n"+
+		base64.StdEncoding.EncodeToString(raw)+"\n",
+	), 0600)
+	if err != nil {
+		t.Fatalf("failed to create test key: %s", err)
+	}
+
+	sig := ed25519.Sign(private, []byte(testContent))
+	global := ed25519.Sign(private, append(append([]byte{}, sig...),
+		testComment...,
+	))
+	raw = append(append([]byte(testAlgo), testKeyID...), sig...)
+
+	return []byte(testUntrusted + "signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(raw) + "\n" +
+		testTrusted + testComment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"), keyPath
+}
+
+func (s *testScenario) createSource(server *httptest.Server,
+	signature []byte,
+	keyPath string) *libmonteur.TOMLSource {
+	source := &libmonteur.TOMLSource{
+		URL:     server.URL + "/" + testArchive,
+		Archive: testArchive,
+		Method:  http.MethodGet,
+	}
+
+	if s.Switches[useUnsigned] {
+		return source
+	}
+
+	source.Signature = &libmonteur.TOMLSignature{
+		Type: libmonteur.PROGRAM_SIGNATURE_MINISIGN,
+		URL:  source.URL + libmonteur.EXTENSION_MINISIG,
+		Keys: []string{keyPath},
+	}
+
+	if s.Switches[useSignatureValue] {
+		source.Signature.URL = ""
+		source.Signature.Value = string(signature)
+	}
+
+	return source
+}
+
+func (s *testScenario) createLogger(t *testing.T) *liblog.Logger {
+	secrets := &libsecrets.Secrets{}
+
+	err := secrets.Parse(nil)
+	if err != nil {
+		t.Fatalf("failed to create test secrets: %s", err)
+	}
+
+	log := &liblog.Logger{}
+	log.Init(secrets)
+
+	return log
+}
+
+// createVerify rejects every download when useRejectedVerify is set.
+func (s *testScenario) createVerify() func(path string) error {
+	if !s.Switches[useRejectedVerify] {
+		return nil
+	}
+
+	return func(path string) error {
+		return fmt.Errorf("rejected %s", path)
+	}
+}
+
+// source downloads the test source with an isolated download cache.
+func (s *testScenario) source(t *testing.T) (cacheDir string, err error) {
+	dir := t.TempDir()
+	cacheDir = filepath.Join(dir, "cache")
+	t.Setenv(libmonteur.ENV_DOWNLOADS_DIR, cacheDir)
+
+	signature, keyPath := s.createSignature(t, dir)
+	server := s.createServer(t, signature)
+	source := s.createSource(server, signature, keyPath)
+
+	err = VerifiedSource(context.Background(),
+		source,
+		map[string]interface{}{
+			libmonteur.VAR_TMP:     filepath.Join(dir, "tmp"),
+			libmonteur.VAR_OFFLINE: s.Switches[useOffline],
+		},
+		s.createLogger(t),
+		nil,
+		nil,
+		s.createVerify(),
+	)
+
+	return cacheDir, err
+}
+
+func (s *testScenario) assertSource(th *thelper.THelper,
+	cacheDir string,
+	err error) {
+	cache := &libdownloads.Cache{Dir: cacheDir}
+	list, _ := cache.List()
+
+	if s.Switches[expectError] {
+		th.ExpectError(err, true)
+		th.ExpectSameBool("cached entries", len(list) == 0,
+			"expected cached entries", true,
+		)
+		offline := err != nil && strings.Contains(err.Error(),
+			libmonteur.ERROR_DOWNLOADS_OFFLINE,
+		)
+		th.ExpectSameBool("offline error", offline,
+			"expected offline error", s.Switches[useOffline],
+		)
+
+		return
+	}
+
+	expect := 2
+	if s.Switches[useUnsigned] || s.Switches[useSignatureValue] {
+		expect = 1
+	}
+
+	th.ExpectError(err, false)
+	th.ExpectSameBool("cached entries", len(list) == expect,
+		"expected cached entries", true,
+	)
+}
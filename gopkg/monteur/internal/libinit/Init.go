@@ -404,11 +404,49 @@ BinCfgDir = '.monteurFS/config'
 LogDir = '.monteurFS/log'
 DataDir = '.configs/monteur/app/data'
 ReleaseDir = '.monteurFS/releases'
+HistoryDir = '.monteurFS/history'
+StateDir = '.monteurFS/state'
+LockFile = '.monteurFS/monteur.lock'
 SecretsDir = [
         '{{ .HomeDir }}/.secrets',
         '{{ .RootDir }}/.configs/monteur/secrets',
 ]
 
+# [Secrets]
+# MinLength = 6             # shorter secret values are not redacted
+#
+# Secret providers are queried in order when a key is absent from SecretsDir.
+# [[Secrets.Providers]]
+# Type = 'env'
+# Prefix = 'MONTEUR_SECRET_'
+#
+# [[Secrets.Providers]]
+# Type = 'helper'
+# Name = 'vault'            # executes: monteur-secret-vault get <key>
+
+# [Logs]
+# Keep = 20                 # keep only the last N runs per stage
+# MaxAge = '30d'            # remove runs older than this (e.g. '72h', '30d')
+# MaxSize = '1.5GB'         # total size limit per stage (B, KB, MB, GB, TB)
+
+# [Cache]                   # outputs cache for tasks with Inputs and Outputs
+# Dir = '.monteurFS/cache'  # local cache directory (default)
+# URL = 'https://cache.example.com/monteur'  # HTTP GET/PUT cache server
+
+# Setup download URLs are rewritten by their longest matching prefix.
+# [[Mirrors]]
+# Prefix = 'https://go.dev/dl/'
+# URL = 'https://artifacts.example.com/go/'
+
+# Setup downloads' network settings. All values support GetSecret templating
+# and each setup job can override them with its own [Network] table.
+# [Network]
+# Proxy = 'http://{{- GetSecret "PROXY_AUTH" -}}@proxy.example.com:3128'
+# CAFiles = [ '/etc/ssl/certs/corporate-ca.pem' ]
+# Cert = '{{- GetSecret "CLIENT_CERT" -}}'  # PEM data or file path
+# Key = '{{- GetSecret "CLIENT_KEY" -}}'    # PEM data or file path
+# Insecure = false  # skip TLS verification (internal mirrors only)
+
 [Language]
 Name = '`+libmonteur.LANG_NAME_DEFAULT+`'
 Code = '`+libmonteur.LANG_CODE_DEFAULT+`'
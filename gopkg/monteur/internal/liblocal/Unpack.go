@@ -29,6 +29,7 @@ func Source(ctx context.Context,
 	source *libmonteur.TOMLSource,
 	variables map[string]interface{},
 	log *liblog.Logger,
+	_ func(downloaded int64, total int64),
 	cs libchecksum.Hasher) (err error) {
 	log.Info("Sourcing %s locally...", source.URL)
 	_, err = exec.LookPath(source.URL)
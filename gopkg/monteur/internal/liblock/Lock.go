@@ -0,0 +1,249 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package liblock is the advisory workspace lock guarding against concurrent
+// runs clobbering the shared `.monteurFS` directories.
+package liblock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	// WAIT_INTERVAL is the polling interval when waiting for a busy Lock.
+	WAIT_INTERVAL = 500 * time.Millisecond
+)
+
+// Holder is the identity of a Lock owner recorded inside the lock file.
+type Holder struct {
+	Stage    string `json:"stage"`
+	Hostname string `json:"hostname"`
+	Started  string `json:"started"`
+	PID      int    `json:"pid"`
+}
+
+// String is to describe the Holder for contention reporting.
+func (me *Holder) String() string {
+	return fmt.Sprintf("stage '%s' (PID %d on '%s' since %s)",
+		me.Stage,
+		me.PID,
+		me.Hostname,
+		me.Started,
+	)
+}
+
+// IsStale is to check the Holder's process is no longer running.
+//
+// Only the Holder from the same host can be verified. A Holder without any
+// PID (e.g. the owner has not recorded itself yet) and Holders from other
+// hosts (e.g. a shared network filesystem) are always considered alive.
+func (me *Holder) IsStale() bool {
+	if me.PID <= 0 {
+		return false
+	}
+
+	hostname, _ := os.Hostname()
+
+	if me.Hostname != hostname {
+		return false
+	}
+
+	return !_isAlive(me.PID)
+}
+
+// Lock is the advisory lock file of a workspace.
+//
+// The lock is an OS file lock (`flock(2)` or `LockFileEx`) held on the lock
+// file for the whole run, so the OS releases it as soon as its process dies
+// and a crashed run never leaves a stale lock behind. The lock file records
+// the Holder so that any other Monteur process can identify the owner.
+//
+// Lock is safe to be created using the standard `&struct{}` method.
+type Lock struct {
+	// Path is the lock filepath
+	Path string
+
+	// Stage is the stage name of the current run
+	Stage string
+
+	file *os.File
+}
+
+// Acquire is to attempt locking the workspace once without waiting.
+//
+// When the Lock is held by another live process, its Holder is returned with
+// `nil` error. A `nil` Holder with `nil` error means the Lock is acquired.
+//
+// When the Lock is still held but its Holder is stale, the OS lock cannot be
+// trusted (e.g. a network filesystem without working file locks) so an error
+// naming the Holder is returned instead of waiting for it forever.
+func (me *Lock) Acquire() (holder *Holder, err error) {
+	var data []byte
+	var f *os.File
+	var locked bool
+
+	if me.file != nil {
+		return nil, nil
+	}
+
+	owner := &Holder{
+		Stage:   me.Stage,
+		PID:     os.Getpid(),
+		Started: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	owner.Hostname, _ = os.Hostname()
+
+	data, err = json.Marshal(owner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_LOCK_FAILED, err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(me.Path), libmonteur.PERMISSION_DIRECTORY)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_LOCK_FAILED, err)
+	}
+
+	for {
+		f, err = os.OpenFile(me.Path,
+			os.O_RDWR|os.O_CREATE,
+			libmonteur.PERMISSION_FILE,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s",
+				libmonteur.ERROR_LOCK_FAILED,
+				err,
+			)
+		}
+
+		locked, err = _tryLock(f)
+		if err != nil || !locked {
+			holder = _readHolder(f)
+			f.Close()
+
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s",
+					libmonteur.ERROR_LOCK_FAILED,
+					err,
+				)
+			}
+
+			if holder.IsStale() {
+				return nil, fmt.Errorf("%s: %s. Remove '%s' if no "+
+					"other run is active",
+					libmonteur.ERROR_LOCK_STALE,
+					holder,
+					me.Path,
+				)
+			}
+
+			return holder, nil
+		}
+
+		// the previous owner may have removed the file before unlocking
+		if _isCurrent(f, me.Path) {
+			break
+		}
+
+		_ = _unlock(f)
+		f.Close()
+	}
+
+	err = _writeHolder(f, data)
+	if err != nil {
+		_ = _unlock(f)
+		f.Close()
+
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_LOCK_FAILED, err)
+	}
+
+	me.file = f
+
+	return nil, nil
+}
+
+// Release is to unlock the workspace and remove its lock file.
+//
+// Only the Lock acquired by Acquire owns the lock file. Calling Release on an
+// unacquired or already released Lock does nothing.
+func (me *Lock) Release() (err error) {
+	if me == nil || me.file == nil {
+		return nil
+	}
+
+	f := me.file
+	me.file = nil
+
+	err = _release(f, me.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_LOCK_RELEASE, err)
+	}
+
+	return nil
+}
+
+// _isCurrent checks the opened file is still the one at the lock filepath.
+func _isCurrent(f *os.File, path string) bool {
+	opened, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(opened, current)
+}
+
+func _writeHolder(f *os.File, data []byte) (err error) {
+	err = f.Truncate(0)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = f.WriteAt(data, 0)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return f.Sync() //nolint:wrapcheck
+}
+
+// _readHolder reads the Holder of a busy lock file. A corrupted or empty lock
+// file gives an empty Holder.
+func _readHolder(f *os.File) (holder *Holder) {
+	holder = &Holder{}
+
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<20))
+	if err != nil {
+		return holder
+	}
+
+	err = json.Unmarshal(data, holder)
+	if err != nil {
+		return &Holder{}
+	}
+
+	return holder
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblock
+
+import (
+	"testing"
+)
+
+func TestAcquire(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testAcquire {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		path := s.createLockFile(t)
+		other := s.createHolder(t, path)
+
+		if s.Switches[useContention] {
+			// test
+			winners, err := s.contend(path)
+
+			// assert
+			th.ExpectUIDCorrectness(i, s.UID, false)
+			th.ExpectError(err, false)
+			th.ExpectSameBool("single winner", winners == 1,
+				"expected single winner", true,
+			)
+			s.log(th, map[string]interface{}{
+				"winners": winners,
+				"error":   err,
+			})
+			th.Conclude()
+
+			continue
+		}
+
+		// test
+		subject := &Lock{Path: path, Stage: testStage}
+		holder, err := subject.Acquire()
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertAcquire(th, path, holder, err)
+		s.log(th, map[string]interface{}{
+			"path":   path,
+			"holder": holder,
+			"error":  err,
+		})
+		th.Conclude()
+
+		_ = subject.Release()
+		_ = other.Release()
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblock
+
+import (
+	"testing"
+)
+
+func TestRelease(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testRelease {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		path := s.createLockFile(t)
+		owner := s.createHolder(t, path)
+		subject := owner
+
+		switch {
+		case s.Switches[useUnacquired]:
+			subject = &Lock{Path: path, Stage: testStage}
+		case s.Switches[useReleasedTwice]:
+			_ = owner.Release()
+			owner = s.createHolder(t, path)
+		}
+
+		// test
+		err := subject.Release()
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertRelease(th, path, err)
+		s.log(th, map[string]interface{}{
+			"path":  path,
+			"error": err,
+		})
+		th.Conclude()
+
+		_ = owner.Release()
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package liblock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// LockFileEx locks are mandatory so the locked byte is placed far beyond the
+// lock file content to keep it readable by other processes.
+const (
+	LOCK_OFFSET_LOW  = 0xFFFFFFFE
+	LOCK_OFFSET_HIGH = 0x7FFFFFFF
+)
+
+func _overlapped() *windows.Overlapped {
+	return &windows.Overlapped{
+		Offset:     LOCK_OFFSET_LOW,
+		OffsetHigh: LOCK_OFFSET_HIGH,
+	}
+}
+
+func _tryLock(f *os.File) (locked bool, err error) {
+	err = windows.LockFileEx(windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0,
+		1,
+		0,
+		_overlapped(),
+	)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, windows.ERROR_LOCK_VIOLATION):
+		return false, nil
+	default:
+		return false, err //nolint:wrapcheck
+	}
+}
+
+func _unlock(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), //nolint:wrapcheck
+		0,
+		1,
+		0,
+		_overlapped(),
+	)
+}
+
+// _release unlocks the lock file before removing it since Windows cannot
+// remove an opened file. The removal fails harmlessly when another process
+// has already opened it.
+func _release(f *os.File, path string) (err error) {
+	_ = _unlock(f)
+	f.Close()
+	_ = os.Remove(path)
+
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblock
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should acquire the lock when:
+1. no lock file exists.
+`,
+			Switches: map[string]bool{
+				expectAcquired: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should return the holder without acquiring when:
+1. the lock is held by another Lock.
+`,
+			Switches: map[string]bool{
+				useHeldLock: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should take over the lock when:
+1. the lock file is left behind by a dead process.
+`,
+			Switches: map[string]bool{
+				useStaleLock:   true,
+				expectAcquired: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should take over the lock when:
+1. the lock file left behind is corrupted.
+`,
+			Switches: map[string]bool{
+				useCorruptLock: true,
+				expectAcquired: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should take over the lock when:
+1. the lock file left behind is empty.
+`,
+			Switches: map[string]bool{
+				useEmptyLock:   true,
+				expectAcquired: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should only be won by exactly one Lock when:
+1. many Locks acquire the same lock file concurrently.
+`,
+			Switches: map[string]bool{
+				useContention: true,
+			},
+		}, {
+			UID:      7,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should only be won by exactly one Lock when:
+1. many Locks acquire the same lock file concurrently.
+2. the lock file left behind is corrupted.
+`,
+			Switches: map[string]bool{
+				useContention:  true,
+				useCorruptLock: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testRelease,
+			Description: `
+Lock.Release should remove the lock file and free the lock when:
+1. the Lock is the owner.
+`,
+			Switches: map[string]bool{
+				useHeldLock:   true,
+				expectRemoved: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testRelease,
+			Description: `
+Lock.Release should not touch the lock when:
+1. the Lock did not acquire it.
+2. the lock is held by another Lock.
+`,
+			Switches: map[string]bool{
+				useHeldLock:   true,
+				useUnacquired: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testRelease,
+			Description: `
+Lock.Release should not touch the lock when:
+1. the Lock was already released.
+2. the lock is now held by another Lock.
+`,
+			Switches: map[string]bool{
+				useHeldLock:      true,
+				useReleasedTwice: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testIsStale,
+			Description: `
+Holder.IsStale should report alive when:
+1. the Holder is parsed from a corrupted lock file.
+2. the Holder's process cannot be verified without PID.
+`,
+			Switches: map[string]bool{
+				useCorruptLock: true,
+			},
+		}, {
+			UID:      12,
+			TestType: testIsStale,
+			Description: `
+Holder.IsStale should report stale when:
+1. the Holder is from the same host.
+2. the Holder's process is no longer running.
+`,
+			Switches: map[string]bool{
+				useDeadPID:  true,
+				expectStale: true,
+			},
+		}, {
+			UID:      13,
+			TestType: testIsStale,
+			Description: `
+Holder.IsStale should report alive when:
+1. the Holder is from the same host.
+2. the Holder's process is running.
+`,
+			Switches: map[string]bool{
+				useLivePID: true,
+			},
+		}, {
+			UID:      14,
+			TestType: testIsStale,
+			Description: `
+Holder.IsStale should report alive when:
+1. the Holder is from another host.
+`,
+			Switches: map[string]bool{
+				useOtherHost: true,
+			},
+		}, {
+			UID:      15,
+			TestType: testAcquire,
+			Description: `
+Lock.Acquire should report the stale holder without acquiring when:
+1. the lock is held by another Lock.
+2. the lock file records a process on the same host that is gone.
+`,
+			Switches: map[string]bool{
+				useHeldLock: true,
+				useDeadPID:  true,
+				expectError: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblock
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testAcquire = "testAcquire"
+	testRelease = "testRelease"
+	testIsStale = "testIsStale"
+)
+
+const (
+	useHeldLock      = "useHeldLock"
+	useStaleLock     = "useStaleLock"
+	useCorruptLock   = "useCorruptLock"
+	useEmptyLock     = "useEmptyLock"
+	useContention    = "useContention"
+	useUnacquired    = "useUnacquired"
+	useReleasedTwice = "useReleasedTwice"
+	useOtherHost     = "useOtherHost"
+	useDeadPID       = "useDeadPID"
+	useLivePID       = "useLivePID"
+
+	expectAcquired = "expectAcquired"
+	expectError    = "expectError"
+	expectStale    = "expectStale"
+	expectRemoved  = "expectRemoved"
+)
+
+const (
+	testStage       = "build"
+	testOtherStage  = "test"
+	testDeadPID     = 0x7FFFFFF0
+	testOtherHost   = "monteur.invalid"
+	testContenders  = 8
+	testLockFile    = "monteur.lock"
+	testCorruptData = "{\"stage\": \"bu"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createLockFile prepares the lock filepath, optionally holding a leftover
+// lock file without any OS lock on it.
+func (s *testScenario) createLockFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), ".monteurFS", testLockFile)
+
+	var data []byte
+	switch {
+	case s.Switches[useStaleLock]:
+		hostname, _ := os.Hostname()
+		data, _ = json.Marshal(&Holder{
+			Stage:    testOtherStage,
+			Hostname: hostname,
+			PID:      testDeadPID,
+		})
+	case s.Switches[useCorruptLock]:
+		data = []byte(testCorruptData)
+	case s.Switches[useEmptyLock]:
+		data = []byte{}
+	default:
+		return path
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test lock file: %s", err)
+	}
+
+	return path
+}
+
+// createHolder acquires the Lock from another Lock instance when requested.
+func (s *testScenario) createHolder(t *testing.T, path string) *Lock {
+	lock := &Lock{Path: path, Stage: testOtherStage}
+	if !s.Switches[useHeldLock] {
+		return lock
+	}
+
+	holder, err := lock.Acquire()
+	if err != nil || holder != nil {
+		t.Fatalf("failed to hold test lock: %v %v", holder, err)
+	}
+
+	if !s.Switches[useDeadPID] {
+		return lock
+	}
+
+	// the held lock file records a process that is gone
+	hostname, _ := os.Hostname()
+	data, _ := json.Marshal(&Holder{
+		Stage:    testOtherStage,
+		Hostname: hostname,
+		PID:      testDeadPID,
+	})
+
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatalf("failed to record dead test holder: %s", err)
+	}
+
+	return lock
+}
+
+// contend acquires the same lock filepath concurrently and returns the number
+// of winners.
+func (s *testScenario) contend(path string) (winners int, err error) {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < testContenders; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			lock := &Lock{Path: path, Stage: testStage}
+			holder, e := lock.Acquire()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if e != nil {
+				err = e
+				return
+			}
+
+			if holder == nil {
+				winners++
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return winners, err
+}
+
+func (s *testScenario) createHolderData() *Holder {
+	hostname, _ := os.Hostname()
+	holder := &Holder{Stage: testStage, Hostname: hostname}
+
+	switch {
+	case s.Switches[useCorruptLock]:
+		return &Holder{}
+	case s.Switches[useOtherHost]:
+		holder.Hostname = testOtherHost
+		holder.PID = testDeadPID
+	case s.Switches[useDeadPID]:
+		holder.PID = testDeadPID
+	case s.Switches[useLivePID]:
+		holder.PID = os.Getpid()
+	}
+
+	return holder
+}
+
+func (s *testScenario) assertAcquire(th *thelper.THelper,
+	path string, holder *Holder, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	th.ExpectSameBool("acquired", holder == nil && err == nil,
+		"expected acquired", s.Switches[expectAcquired],
+	)
+
+	if holder != nil {
+		th.ExpectSameStrings("holder stage", holder.Stage,
+			"expected holder stage", testOtherStage,
+		)
+		th.ExpectSameBool("holder PID", holder.PID == os.Getpid(),
+			"expected holder PID", true,
+		)
+	}
+
+	if !s.Switches[expectAcquired] {
+		return
+	}
+
+	// the lock file records the new owner
+	data, _ := os.ReadFile(path)
+	owner := &Holder{}
+	_ = json.Unmarshal(data, owner)
+	th.ExpectSameStrings("owner stage", owner.Stage,
+		"expected owner stage", testStage,
+	)
+	th.ExpectSameBool("owner PID", owner.PID == os.Getpid(),
+		"expected owner PID", true,
+	)
+}
+
+func (s *testScenario) assertRelease(th *thelper.THelper,
+	path string, err error) {
+	th.ExpectError(err, false)
+
+	_, statErr := os.Stat(path)
+	th.ExpectSameBool("removed", os.IsNotExist(statErr),
+		"expected removed", s.Switches[expectRemoved],
+	)
+
+	// the lock is only free when it was released by its owner
+	lock := &Lock{Path: path, Stage: testStage}
+	holder, _ := lock.Acquire()
+	_ = lock.Release()
+	th.ExpectSameBool("free", holder == nil,
+		"expected free", s.Switches[expectRemoved],
+	)
+}
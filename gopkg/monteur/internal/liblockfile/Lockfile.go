@@ -0,0 +1,171 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package liblockfile is the setup lockfile pinning the fetched sources.
+//
+// The lockfile records the rendered URL (before mirror rewriting), archive
+// name, format, size, and SHA256 of every setup task's fetched source per
+// compute system. It is meant to be committed so that the whole team installs
+// exactly the same tools even when an upstream silently re-tags its release.
+//
+// Only `https-download` sources are pinned: `git` sources are already pinned
+// by their commit and `local-system` sources are never fetched. A pin is
+// verified before the download enters the user level download cache.
+package liblockfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/endec/toml"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	HEADER = "# setup.lock is generated by 'monteur setup'. DO NOT EDIT.\n" +
+		"# Use 'monteur setup --update-lock' to accept upstream changes.\n\n"
+)
+
+// Lockfile is the setup lockfile data structure.
+//
+// Lockfile is safe to be created using the standard `&struct{}` method.
+type Lockfile struct {
+	mutex   sync.Mutex
+	changed bool
+
+	// Path is the lockfile pathing
+	Path string
+
+	// Tasks are the pinned sources by task name and compute system
+	Tasks map[string]map[string]*Pin
+
+	// Update is to accept any drifted source by overwriting its pin
+	Update bool
+}
+
+// Load is to read the lockfile from Lockfile.Path.
+//
+// A missing lockfile is the same as an empty one.
+func (me *Lockfile) Load() (err error) {
+	s := struct {
+		Tasks *map[string]map[string]*Pin
+	}{
+		Tasks: &me.Tasks,
+	}
+
+	me.Tasks = map[string]map[string]*Pin{}
+
+	_, err = os.Stat(me.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	err = toml.DecodeFile(me.Path, &s, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SETUP_LOCK_READ, err)
+	}
+
+	if me.Tasks == nil {
+		me.Tasks = map[string]map[string]*Pin{}
+	}
+
+	return nil
+}
+
+// Verify is to check the given pin against the recorded one.
+//
+// A new task or compute system is recorded without error. A drifted pin is an
+// error unless Lockfile.Update is set, in which case it is overwritten.
+func (me *Lockfile) Verify(task string, system string, pin *Pin) (err error) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if me.Tasks == nil {
+		me.Tasks = map[string]map[string]*Pin{}
+	}
+
+	if me.Tasks[task] == nil {
+		me.Tasks[task] = map[string]*Pin{}
+	}
+
+	recorded := me.Tasks[task][system]
+	if recorded != nil && *recorded == *pin {
+		return nil
+	}
+
+	if recorded != nil && !me.Update {
+		return fmt.Errorf("%s: %s (%s) %s. Use --update-lock to accept",
+			libmonteur.ERROR_SETUP_LOCK_DRIFT,
+			task,
+			system,
+			recorded.Diff(pin),
+		)
+	}
+
+	me.Tasks[task][system] = pin
+	me.changed = true
+
+	return nil
+}
+
+// Save is to write the lockfile into Lockfile.Path when it was changed.
+func (me *Lockfile) Save() (err error) {
+	var data []byte
+	var f *os.File
+
+	if me == nil {
+		return nil
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if !me.changed {
+		return nil
+	}
+
+	data, err = toml.EncodeBytes(&struct {
+		Tasks map[string]map[string]*Pin
+	}{
+		Tasks: me.Tasks,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SETUP_LOCK_WRITE, err)
+	}
+
+	f, err = os.CreateTemp(filepath.Dir(me.Path), ".setup.lock-")
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SETUP_LOCK_WRITE, err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.WriteString(HEADER + string(data))
+	_ = f.Close()
+	if err == nil {
+		err = os.Rename(f.Name(), me.Path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SETUP_LOCK_WRITE, err)
+	}
+
+	me.changed = false
+
+	return nil
+}
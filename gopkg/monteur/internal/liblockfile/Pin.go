@@ -0,0 +1,87 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblockfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// Pin is the record of a fetched source.
+type Pin struct {
+	URL     string
+	Archive string
+	Format  string
+	Size    int64
+	SHA256  string
+}
+
+// NewPin is to create the Pin of the given source fetched into path.
+func NewPin(source *libmonteur.TOMLSource, path string) (out *Pin, err error) {
+	var f *os.File
+	var size int64
+
+	f, err = os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_SETUP_LOCK_HASH,
+			err,
+		)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err = io.Copy(hash, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_SETUP_LOCK_HASH,
+			err,
+		)
+	}
+
+	return &Pin{
+		URL:     source.OriginURL(),
+		Archive: source.Archive,
+		Format:  source.Format,
+		Size:    size,
+		SHA256:  hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// Diff is to describe the fields differing from the given pin.
+func (me *Pin) Diff(other *Pin) string {
+	list := []string{}
+
+	add := func(name string, a interface{}, b interface{}) {
+		if a != b {
+			list = append(list, fmt.Sprintf("%s '%v' ➤ '%v'", name, a, b))
+		}
+	}
+
+	add("URL", me.URL, other.URL)
+	add("Archive", me.Archive, other.Archive)
+	add("Format", me.Format, other.Format)
+	add("Size", me.Size, other.Size)
+	add("SHA256", me.SHA256, other.SHA256)
+
+	return strings.Join(list, ", ")
+}
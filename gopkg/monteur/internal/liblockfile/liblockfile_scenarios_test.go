@@ -0,0 +1,72 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblockfile
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testVerify,
+			Description: `
+Lockfile.Verify should record the fetched pin when:
+1. the lockfile has no pin for the task and compute system.
+`,
+			Switches: map[string]bool{
+				expectPinned: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testVerify,
+			Description: `
+Lockfile.Verify should accept the fetched pin when:
+1. the lockfile has a pin for the task and compute system.
+2. the fetched pin is the same as the recorded one.
+`,
+			Switches: map[string]bool{
+				useRecordedPin: true,
+				expectPinned:   true,
+			},
+		}, {
+			UID:      3,
+			TestType: testVerify,
+			Description: `
+Lockfile.Verify should reject the fetched pin when:
+1. the lockfile has a pin for the task and compute system.
+2. the fetched pin drifted from the recorded one.
+`,
+			Switches: map[string]bool{
+				useRecordedPin: true,
+				useDriftedPin:  true,
+				expectError:    true,
+			},
+		}, {
+			UID:      4,
+			TestType: testVerify,
+			Description: `
+Lockfile.Verify should re-pin the fetched pin when:
+1. the lockfile has a pin for the task and compute system.
+2. the fetched pin drifted from the recorded one.
+3. Lockfile.Update is set.
+`,
+			Switches: map[string]bool{
+				useRecordedPin: true,
+				useDriftedPin:  true,
+				useUpdate:      true,
+				expectPinned:   true,
+			},
+		},
+	}
+}
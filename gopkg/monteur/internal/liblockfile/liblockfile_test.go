@@ -0,0 +1,108 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblockfile
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testVerify = "testVerify"
+)
+
+const (
+	useRecordedPin = "useRecordedPin"
+	useDriftedPin  = "useDriftedPin"
+	useUpdate      = "useUpdate"
+
+	expectError  = "expectError"
+	expectPinned = "expectPinned"
+)
+
+const (
+	taskName   = "Tool"
+	systemName = "linux-amd64"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createPin(sum string) *Pin {
+	return &Pin{
+		URL:     "https://example.com/tool.tar.gz",
+		Archive: "tool.tar.gz",
+		Format:  "tar.gz",
+		Size:    42,
+		SHA256:  sum,
+	}
+}
+
+func (s *testScenario) createLockfile(t *testing.T) *Lockfile {
+	subject := &Lockfile{
+		Path: filepath.Join(t.TempDir(), "setup.lock"),
+	}
+
+	if s.Switches[useRecordedPin] {
+		_ = subject.Verify(taskName, systemName, s.createPin("recorded"))
+
+		err := subject.Save()
+		if err != nil {
+			t.Fatalf("failed to save test lockfile: %s", err)
+		}
+	}
+
+	// reload from file to ensure the pins survive the round trip
+	subject = &Lockfile{
+		Path:   subject.Path,
+		Update: s.Switches[useUpdate],
+	}
+
+	err := subject.Load()
+	if err != nil {
+		t.Fatalf("failed to load test lockfile: %s", err)
+	}
+
+	return subject
+}
+
+func (s *testScenario) createFetchedPin() *Pin {
+	if s.Switches[useDriftedPin] {
+		return s.createPin("drifted")
+	}
+
+	return s.createPin("recorded")
+}
+
+func (s *testScenario) assertVerify(th *thelper.THelper,
+	subject *Lockfile, pin *Pin, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+
+	recorded := subject.Tasks[taskName][systemName]
+	th.ExpectSameBool("pinned", recorded != nil && *recorded == *pin,
+		"expected pinned", s.Switches[expectPinned],
+	)
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblog
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/logger"
+)
+
+const (
+	LEVEL_OUTPUT = "output"
+)
+
+// Record is a single log entry in the JSON Lines log format.
+type Record struct {
+	Timestamp string `json:"timestamp"`
+	Stage     string `json:"stage"`
+	Task      string `json:"task"`
+	Step      string `json:"step"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+}
+
+func (log *Logger) isJSON() bool {
+	return log.Format == libmonteur.LOG_FORMAT_JSON
+}
+
+// writeJSON is to write a filtered message as a Record into the given types'
+// log files and optionally the terminal.
+func (log *Logger) writeJSON(terminal *os.File,
+	level string,
+	message string,
+	logTypes ...logger.StatusType) {
+	var list map[string]*os.File
+
+	data := log.record(level, message)
+	if data == nil {
+		return
+	}
+
+	for _, t := range logTypes {
+		list = log.statusWriters
+		if t == TYPE_OUTPUT {
+			list = log.outputWriters
+		}
+
+		for _, f := range list {
+			_, _ = f.Write(data)
+		}
+	}
+
+	if terminal == nil || !log.isPrintable(level) {
+		return
+	}
+
+	_, _ = terminal.Write(data)
+}
+
+func (log *Logger) record(level string, message string) []byte {
+	buf := &bytes.Buffer{}
+
+	message = strings.TrimRight(message, "\n")
+	if strings.TrimSpace(message) == "" {
+		return nil
+	}
+
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(&Record{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Stage:     log.Stage,
+		Task:      log.Task,
+		Step:      log.step,
+		Level:     strings.ToLower(level),
+		Message:   message,
+	})
+	if err != nil {
+		return nil
+	}
+
+	return buf.Bytes()
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
@@ -35,9 +36,28 @@ type Logger struct {
 	statusWriters map[string]*os.File
 	outputWriters map[string]*os.File
 	filter        func(string) string
+	secrets       *libsecrets.Secrets
+	step          string
+
+	// Format is the log format (`text` by default or `json`).
+	//
+	// The `json` format writes each statement as a JSON Lines Record
+	// tagged with Stage, Task and the current step.
+	Format string
+	Stage  string
+	Task   string
+
+	// Verbosity is the terminal printout level (`normal` by default).
+	//
+	// Only errors and outputs are printed in `quiet`. Warnings and the
+	// task-level progress statements (see `Progress`) are added in
+	// `normal`. All statements are printed in `verbose` and `debug`.
+	// Log files always receive all statements regardless of Verbosity.
+	Verbosity string
 
 	ToTerminal bool
 	DebugMode  bool
+	progress   bool
 }
 
 // Init is to initialize the logger for use.
@@ -47,6 +67,34 @@ func (log *Logger) Init(secrets *libsecrets.Secrets) {
 	log.outputWriters = map[string]*os.File{}
 
 	log.filter = secrets.Filter
+	log.secrets = secrets
+}
+
+// SetStep is to tag the subsequent statements with the given step name.
+func (log *Logger) SetStep(name string) {
+	log.step = name
+}
+
+// SecretsAudit is to log the secret keys queried so far without their values
+// alongside the warnings of failed secret provider queries.
+//
+// The keys are only recorded when the logger is initialized with a Secrets
+// session (see `libsecrets.Secrets.Session`).
+func (log *Logger) SecretsAudit() {
+	for _, err := range log.secrets.Warnings() {
+		log.Warning("%s", err)
+	}
+
+	keys := log.secrets.Queried()
+	if len(keys) == 0 {
+		return
+	}
+
+	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_INFO,
+		log.filter(fmt.Sprintf("Queried Secrets: %s\n",
+			strings.Join(keys, ", "),
+		)),
+	)
 }
 
 // IsHealthy is to check the status of the logger.
@@ -125,14 +173,18 @@ func (log *Logger) Error(format string, a ...interface{}) {
 	out := fmt.Sprintf(format+"\n", a...)
 	out = log.filter(out)
 
-	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_ERROR, out)
-	log.executor.WriteString(logger.TYPE_OUTPUT, logger.TAG_ERROR, out)
-
-	if !log.ToTerminal {
+	if log.isJSON() {
+		log.writeJSON(os.Stdout, logger.TAG_ERROR, out,
+			TYPE_STATUS,
+			TYPE_OUTPUT,
+		)
 		return
 	}
 
-	os.Stdout.WriteString(out)
+	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_ERROR, out)
+	log.executor.WriteString(logger.TYPE_OUTPUT, logger.TAG_ERROR, out)
+
+	log.print(os.Stdout, logger.TAG_ERROR, out)
 }
 
 // Warning is to log a warning statement straight to status type logs.
@@ -140,13 +192,14 @@ func (log *Logger) Warning(format string, a ...interface{}) {
 	out := fmt.Sprintf(format+"\n", a...)
 	out = log.filter(out)
 
-	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_WARNING, out)
-
-	if !log.ToTerminal {
+	if log.isJSON() {
+		log.writeJSON(os.Stderr, logger.TAG_WARNING, out, TYPE_STATUS)
 		return
 	}
 
-	os.Stderr.WriteString(out)
+	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_WARNING, out)
+
+	log.print(os.Stderr, logger.TAG_WARNING, out)
 }
 
 // Info is to log an info statement straight to status type logs.
@@ -154,13 +207,14 @@ func (log *Logger) Info(format string, a ...interface{}) {
 	out := fmt.Sprintf(format+"\n", a...)
 	out = log.filter(out)
 
-	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_INFO, out)
-
-	if !log.ToTerminal {
+	if log.isJSON() {
+		log.writeJSON(os.Stderr, logger.TAG_INFO, out, TYPE_STATUS)
 		return
 	}
 
-	os.Stderr.WriteString(out)
+	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_INFO, out)
+
+	log.print(os.Stderr, logger.TAG_INFO, out)
 }
 
 // Success is to log a success statement straight to status type logs.
@@ -168,13 +222,14 @@ func (log *Logger) Success(format string, a ...interface{}) {
 	out := fmt.Sprintf(format+"\n", a...)
 	out = log.filter(out)
 
-	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_SUCCESS, out)
-
-	if !log.ToTerminal {
+	if log.isJSON() {
+		log.writeJSON(os.Stderr, logger.TAG_SUCCESS, out, TYPE_STATUS)
 		return
 	}
 
-	os.Stderr.WriteString(out)
+	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_SUCCESS, out)
+
+	log.print(os.Stderr, logger.TAG_SUCCESS, out)
 }
 
 // Debug is to log a debug statement straight to status type logs.
@@ -186,28 +241,35 @@ func (log *Logger) Debug(format string, a ...interface{}) {
 	out := fmt.Sprintf(format+"\n", a...)
 	out = log.filter(out)
 
-	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_DEBUG, out)
-
-	if !log.ToTerminal {
+	if log.isJSON() {
+		log.writeJSON(os.Stderr, logger.TAG_DEBUG, out, TYPE_STATUS)
 		return
 	}
 
-	os.Stderr.WriteString(out)
+	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_DEBUG, out)
+
+	log.print(os.Stderr, logger.TAG_DEBUG, out)
 }
 
 // Output is to log an output statements straight to status and output logs.
 func (log *Logger) Output(format string, a ...interface{}) {
-	out := fmt.Sprintf("[ OUTPUT ] "+format+"\n", a...)
+	out := fmt.Sprintf(format+"\n", a...)
 	out = log.filter(out)
 
-	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_NO, out)
-	log.executor.WriteString(logger.TYPE_OUTPUT, logger.TAG_NO, out)
-
-	if !log.ToTerminal {
+	if log.isJSON() {
+		log.writeJSON(os.Stdout, LEVEL_OUTPUT, out,
+			TYPE_STATUS,
+			TYPE_OUTPUT,
+		)
 		return
 	}
 
-	os.Stdout.WriteString(out)
+	out = "[ OUTPUT ] " + out
+
+	log.executor.WriteString(logger.TYPE_STATUS, logger.TAG_NO, out)
+	log.executor.WriteString(logger.TYPE_OUTPUT, logger.TAG_NO, out)
+
+	log.print(os.Stdout, LEVEL_OUTPUT, out)
 }
 
 // Logf is to log a raw statement straight to the selected logs' type.
@@ -216,16 +278,22 @@ func (log *Logger) Logf(logType logger.StatusType,
 	out := fmt.Sprintf(format, a...)
 	out = log.filter(out)
 
-	log.executor.WriteString(logType, logger.TAG_NO, out)
+	if log.isJSON() {
+		level, terminal := logger.TAG_INFO, os.Stderr
+		if logType == TYPE_OUTPUT {
+			level, terminal = LEVEL_OUTPUT, os.Stdout
+		}
 
-	if !log.ToTerminal {
+		log.writeJSON(terminal, level, out, logType)
 		return
 	}
 
+	log.executor.WriteString(logType, logger.TAG_NO, out)
+
 	if logType == logger.TYPE_OUTPUT {
-		os.Stdout.WriteString(out)
+		log.print(os.Stdout, LEVEL_OUTPUT, out)
 		return
 	}
 
-	os.Stderr.WriteString(out)
+	log.print(os.Stderr, logger.TAG_INFO, out)
 }
@@ -0,0 +1,48 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblog
+
+import (
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testJSON {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		log, status, output := s.createLogger(t)
+
+		// test
+		level, message := s.write(log)
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertJSON(th, status, true, level, message)
+		s.assertJSON(th, output, s.Switches[expectOutputLog],
+			level, message,
+		)
+		s.log(th, map[string]interface{}{
+			"status": status,
+			"output": output,
+			"level":  level,
+		})
+		th.Conclude()
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblog
+
+import (
+	"os"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/logger"
+)
+
+// Progress is to create a view of the Logger for task-level progress.
+//
+// The view shares the same log files. Its info and success statements are
+// printed to the terminal in `normal` Verbosity instead of only in `verbose`.
+// It is meant for `conductor.Conductor` reporting the tasks' progress.
+func (log *Logger) Progress() *Logger {
+	view := *log
+	view.progress = true
+
+	return &view
+}
+
+func (log *Logger) isPrintable(level string) bool {
+	if !log.ToTerminal {
+		return false
+	}
+
+	switch log.Verbosity {
+	case libmonteur.LOG_VERBOSITY_VERBOSE, libmonteur.LOG_VERBOSITY_DEBUG:
+		return true
+	}
+
+	switch level {
+	case logger.TAG_ERROR, LEVEL_OUTPUT:
+		return true
+	case logger.TAG_WARNING:
+		return log.Verbosity != libmonteur.LOG_VERBOSITY_QUIET
+	case logger.TAG_INFO, logger.TAG_SUCCESS:
+		return log.progress &&
+			log.Verbosity != libmonteur.LOG_VERBOSITY_QUIET
+	}
+
+	return false
+}
+
+func (log *Logger) print(terminal *os.File, level string, out string) {
+	if !log.isPrintable(level) {
+		return
+	}
+
+	if log.Task != "" {
+		out = "[ " + log.Task + " ] " + out
+	}
+
+	_, _ = terminal.WriteString(out)
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblog
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testJSON,
+			Description: `
+Logger should write a JSON line with string fields into the status log when:
+1. the format is json.
+2. an info statement is logged during a step.
+`,
+			Switches: map[string]bool{
+				useInfo: true,
+				useStep: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testJSON,
+			Description: `
+Logger should write a JSON line with an empty step field when:
+1. the format is json.
+2. a warning statement is logged outside any step.
+`,
+			Switches: map[string]bool{
+				useWarning: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testJSON,
+			Description: `
+Logger should write a JSON line into both status and output logs when:
+1. the format is json.
+2. an error statement is logged.
+`,
+			Switches: map[string]bool{
+				useError:        true,
+				useStep:         true,
+				expectOutputLog: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testJSON,
+			Description: `
+Logger should write a JSON line into both status and output logs when:
+1. the format is json.
+2. an output statement is logged.
+`,
+			Switches: map[string]bool{
+				useOutput:       true,
+				expectOutputLog: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testJSON,
+			Description: `
+Logger should not write any JSON line when:
+1. the format is json.
+2. the statement is only made of newlines.
+`,
+			Switches: map[string]bool{
+				useEmpty:        true,
+				useOutput:       true,
+				expectOutputLog: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testJSON,
+			Description: `
+Logger should keep the markup characters unescaped when:
+1. the format is json.
+2. the statement has HTML characters and trailing newlines.
+`,
+			Switches: map[string]bool{
+				useMarkup: true,
+				useInfo:   true,
+			},
+		}, {
+			UID:      7,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should only print errors and outputs when:
+1. the Verbosity is quiet.
+`,
+			Switches: map[string]bool{
+				useQuiet:            true,
+				expectPrintedError:  true,
+				expectPrintedOutput: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should only print errors and outputs when:
+1. the Verbosity is quiet.
+2. the logger is a progress view.
+`,
+			Switches: map[string]bool{
+				useQuiet:            true,
+				useProgress:         true,
+				expectPrintedError:  true,
+				expectPrintedOutput: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should print errors, outputs, and warnings when:
+1. the Verbosity is normal.
+`,
+			Switches: map[string]bool{
+				expectPrintedError:   true,
+				expectPrintedOutput:  true,
+				expectPrintedWarning: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should print errors, outputs, warnings, infos, and
+successes when:
+1. the Verbosity is normal.
+2. the logger is a progress view.
+`,
+			Switches: map[string]bool{
+				useProgress:          true,
+				expectPrintedError:   true,
+				expectPrintedOutput:  true,
+				expectPrintedWarning: true,
+				expectPrintedInfo:    true,
+				expectPrintedSuccess: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should print every level when:
+1. the Verbosity is verbose.
+`,
+			Switches: map[string]bool{
+				useVerbose:           true,
+				expectPrintedError:   true,
+				expectPrintedOutput:  true,
+				expectPrintedWarning: true,
+				expectPrintedInfo:    true,
+				expectPrintedSuccess: true,
+				expectPrintedDebug:   true,
+			},
+		}, {
+			UID:      12,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should print every level when:
+1. the Verbosity is debug.
+`,
+			Switches: map[string]bool{
+				useDebug:             true,
+				expectPrintedError:   true,
+				expectPrintedOutput:  true,
+				expectPrintedWarning: true,
+				expectPrintedInfo:    true,
+				expectPrintedSuccess: true,
+				expectPrintedDebug:   true,
+			},
+		}, {
+			UID:      13,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should print nothing when:
+1. the Verbosity is normal.
+2. the logger is not for the terminal.
+`,
+			Switches: map[string]bool{
+				useNoTerminal: true,
+			},
+		}, {
+			UID:      14,
+			TestType: testIsPrintable,
+			Description: `
+Logger.isPrintable should print nothing when:
+1. the Verbosity is debug.
+2. the logger is not for the terminal.
+`,
+			Switches: map[string]bool{
+				useDebug:      true,
+				useNoTerminal: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liblog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/logger"
+)
+
+const (
+	testJSON        = "testJSON"
+	testIsPrintable = "testIsPrintable"
+)
+
+const (
+	useInfo    = "useInfo"
+	useWarning = "useWarning"
+	useError   = "useError"
+	useOutput  = "useOutput"
+	useStep    = "useStep"
+	useEmpty   = "useEmpty"
+	useMarkup  = "useMarkup"
+
+	expectOutputLog = "expectOutputLog"
+
+	useQuiet      = "useQuiet"
+	useVerbose    = "useVerbose"
+	useDebug      = "useDebug"
+	useProgress   = "useProgress"
+	useNoTerminal = "useNoTerminal"
+
+	expectPrintedError   = "expectPrintedError"
+	expectPrintedOutput  = "expectPrintedOutput"
+	expectPrintedWarning = "expectPrintedWarning"
+	expectPrintedInfo    = "expectPrintedInfo"
+	expectPrintedSuccess = "expectPrintedSuccess"
+	expectPrintedDebug   = "expectPrintedDebug"
+)
+
+const (
+	logStage   = "build"
+	logTask    = "Build App"
+	logStep    = "Compile"
+	logMessage = "compiling"
+	logMarkup  = "<a href=\"x\"> & 'y'"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createLogger creates the JSON logger writing into the status and output
+// log files.
+func (s *testScenario) createLogger(t *testing.T) (log *Logger,
+	status string, output string) {
+	dir := t.TempDir()
+	status = filepath.Join(dir, "status.log")
+	output = filepath.Join(dir, "output.log")
+
+	secrets := &libsecrets.Secrets{}
+
+	err := secrets.Parse(nil)
+	if err != nil {
+		t.Fatalf("failed to create test secrets: %s", err)
+	}
+
+	log = &Logger{
+		Format: libmonteur.LOG_FORMAT_JSON,
+		Stage:  logStage,
+		Task:   logTask,
+	}
+	log.Init(secrets)
+
+	err = log.Add(TYPE_STATUS, status)
+	if err == nil {
+		err = log.Add(TYPE_OUTPUT, output)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test logger: %s", err)
+	}
+
+	if s.Switches[useStep] {
+		log.SetStep(logStep)
+	}
+
+	return log, status, output
+}
+
+// write is to log the scenario's statement and get its expected level.
+func (s *testScenario) write(log *Logger) (level string, message string) {
+	message = logMessage
+	switch {
+	case s.Switches[useEmpty]:
+		message = ""
+	case s.Switches[useMarkup]:
+		message = logMarkup
+	}
+
+	format := "%s\n\n"
+
+	switch {
+	case s.Switches[useWarning]:
+		level = "warning"
+		log.Warning(format, message)
+	case s.Switches[useError]:
+		level = "error"
+		log.Error(format, message)
+	case s.Switches[useOutput]:
+		level = LEVEL_OUTPUT
+		log.Output(format, message)
+	default:
+		level = "info"
+		log.Info(format, message)
+	}
+
+	log.Sync()
+	log.Close()
+
+	return level, message
+}
+
+// decode is to read all the JSON lines of the given log file.
+func (s *testScenario) decode(path string) (list []map[string]interface{},
+	err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		record := map[string]interface{}{}
+
+		err = json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		list = append(list, record)
+	}
+
+	return list, scanner.Err() //nolint:wrapcheck
+}
+
+func (s *testScenario) assertJSON(th *thelper.THelper,
+	path string,
+	expected bool,
+	level string,
+	message string) {
+	list, err := s.decode(path)
+	th.ExpectError(err, false)
+
+	count := 0
+	if expected && !s.Switches[useEmpty] {
+		count = 1
+	}
+
+	th.ExpectSameStrings("records", fmt.Sprint(len(list)),
+		"expected records", fmt.Sprint(count),
+	)
+
+	if len(list) != 1 || count != 1 {
+		return
+	}
+
+	record := list[0]
+	s.assertFields(th, record)
+
+	step := ""
+	if s.Switches[useStep] {
+		step = logStep
+	}
+
+	for field, value := range map[string]string{
+		"stage":   logStage,
+		"task":    logTask,
+		"step":    step,
+		"level":   level,
+		"message": message,
+	} {
+		got, _ := record[field].(string)
+		th.ExpectSameStrings(field, got, "expected "+field, value)
+	}
+
+	timestamp, _ := record["timestamp"].(string)
+	_, err = time.Parse(time.RFC3339Nano, timestamp)
+	th.ExpectError(err, false)
+}
+
+// assertFields is to check the record has exactly the Record's fields where
+// all of them are strings.
+func (s *testScenario) assertFields(th *thelper.THelper,
+	record map[string]interface{}) {
+	keys := []string{}
+	for key, value := range record {
+		_, ok := value.(string)
+		th.ExpectSameBool(key+" is string", ok,
+			"expected "+key+" is string", true,
+		)
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	th.ExpectSameStrings("fields", strings.Join(keys, ","),
+		"expected fields", "level,message,stage,step,task,timestamp",
+	)
+}
+
+// createVerbosityLogger creates the terminal logger of the scenario's
+// Verbosity without any log file.
+func (s *testScenario) createVerbosityLogger() (log *Logger) {
+	log = &Logger{
+		Verbosity:  libmonteur.LOG_VERBOSITY_NORMAL,
+		ToTerminal: !s.Switches[useNoTerminal],
+	}
+
+	switch {
+	case s.Switches[useQuiet]:
+		log.Verbosity = libmonteur.LOG_VERBOSITY_QUIET
+	case s.Switches[useVerbose]:
+		log.Verbosity = libmonteur.LOG_VERBOSITY_VERBOSE
+	case s.Switches[useDebug]:
+		log.Verbosity = libmonteur.LOG_VERBOSITY_DEBUG
+	}
+
+	if s.Switches[useProgress] {
+		return log.Progress()
+	}
+
+	return log
+}
+
+// printable is to get the levels printable by the given logger.
+func (s *testScenario) printable(log *Logger) (list []string) {
+	for _, level := range []string{
+		logger.TAG_ERROR,
+		LEVEL_OUTPUT,
+		logger.TAG_WARNING,
+		logger.TAG_INFO,
+		logger.TAG_SUCCESS,
+		logger.TAG_DEBUG,
+	} {
+		if log.isPrintable(level) {
+			list = append(list, level)
+		}
+	}
+
+	return list
+}
+
+func (s *testScenario) assertIsPrintable(th *thelper.THelper,
+	list []string) {
+	expect := []string{}
+
+	for _, v := range []struct {
+		level string
+		key   string
+	}{
+		{logger.TAG_ERROR, expectPrintedError},
+		{LEVEL_OUTPUT, expectPrintedOutput},
+		{logger.TAG_WARNING, expectPrintedWarning},
+		{logger.TAG_INFO, expectPrintedInfo},
+		{logger.TAG_SUCCESS, expectPrintedSuccess},
+		{logger.TAG_DEBUG, expectPrintedDebug},
+	} {
+		if s.Switches[v.key] {
+			expect = append(expect, v.level)
+		}
+	}
+
+	th.ExpectSameStrings("printable", strings.Join(list, ", "),
+		"expected printable", strings.Join(expect, ", "),
+	)
+}
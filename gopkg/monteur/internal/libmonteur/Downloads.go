@@ -0,0 +1,36 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libmonteur
+
+import (
+	"time"
+)
+
+const (
+	DOWNLOADS_ACTION_LIST  = "list"
+	DOWNLOADS_ACTION_PRUNE = "prune"
+	DOWNLOADS_ACTION_CLEAR = "clear"
+)
+
+const (
+	// DOWNLOADS_PRUNE_AGE is the unused duration after which a cached
+	// download is removed by the prune action.
+	DOWNLOADS_PRUNE_AGE = 30 * 24 * time.Hour
+
+	// ENV_DOWNLOADS_DIR is the environment variable overriding the user
+	// level download cache directory.
+	ENV_DOWNLOADS_DIR = "MONTEUR_DOWNLOADS_DIR"
+)
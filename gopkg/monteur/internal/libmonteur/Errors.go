@@ -37,6 +37,61 @@ const (
 	ERROR_PUBLISH = "[ ERROR - Publish ]"
 	ERROR_RELEASE = "[ ERROR - Release ]"
 	ERROR_CLEAN   = "[ ERROR - Clean   ]"
+
+	ERROR_CACHE    = "[ ERROR - Cache    ]"
+	ERROR_INSPECT  = "[ ERROR - Inspect  ]"
+	ERROR_SECRETS  = "[ ERROR - Secrets  ]"
+	ERROR_STATS    = "[ ERROR - Stats    ]"
+	ERROR_VALIDATE = "[ ERROR - Validate ]"
+)
+
+const (
+	ERROR_INSPECT_STAGE_UNKNOWN = "unknown stage"
+	ERROR_INSPECT_TASK_MISSING  = "no task found"
+)
+
+const (
+	ERROR_HISTORY_MISSING = "no run history found"
+	ERROR_HISTORY_READ    = "failed to read run history"
+	ERROR_HISTORY_WRITE   = "failed to record run history"
+)
+
+const (
+	ERROR_LOCK_BUSY    = "workspace is locked by another run"
+	ERROR_LOCK_FAILED  = "failed to lock workspace"
+	ERROR_LOCK_RELEASE = "failed to release workspace lock"
+	ERROR_LOCK_STALE   = "workspace is locked by a run that is gone"
+)
+
+const (
+	ERROR_CACHE_BAD       = "bad cache setting"
+	ERROR_CACHE_CORRUPTED = "cache entry failed checksum verification"
+	ERROR_CACHE_OUTSIDE   = "cached output is outside of root directory"
+	ERROR_CACHE_RESTORE   = "failed to restore outputs from cache"
+	ERROR_CACHE_SERVER    = "cache server request failed"
+	ERROR_CACHE_STORE     = "failed to store outputs into cache"
+)
+
+const (
+	ERROR_DOWNLOADS_ACTION_UNKNOWN = "unknown cache action"
+	ERROR_DOWNLOADS_CORRUPTED      = "cached download failed verification"
+	ERROR_DOWNLOADS_MISSING        = "no user cache directory available"
+	ERROR_DOWNLOADS_OFFLINE        = "source is not in download cache while offline"
+	ERROR_DOWNLOADS_READ           = "failed to read download cache"
+	ERROR_DOWNLOADS_REMOVE         = "failed to remove cached download"
+	ERROR_DOWNLOADS_STORE          = "failed to store download into cache"
+)
+
+const (
+	ERROR_SETUP_LOCK_DRIFT = "fetched source drifted from setup.lock"
+	ERROR_SETUP_LOCK_HASH  = "failed to hash fetched source"
+	ERROR_SETUP_LOCK_READ  = "failed to read setup.lock"
+	ERROR_SETUP_LOCK_WRITE = "failed to write setup.lock"
+)
+
+const (
+	ERROR_STATE_INPUT = "failed to hash task inputs"
+	ERROR_STATE_WRITE = "failed to record task state"
 )
 
 const (
@@ -75,6 +130,20 @@ const (
 	ERROR_DEPENDENCY_BAD = "bad dependency"
 )
 
+const (
+	ERROR_VALIDATE_ACTION_UNKNOWN      = "unknown action type"
+	ERROR_VALIDATE_CONDITION_MISSING   = "missing condition"
+	ERROR_VALIDATE_FAILED              = "configurations have issues"
+	ERROR_VALIDATE_FORMAT_UNKNOWN      = "unknown source format"
+	ERROR_VALIDATE_JOB_UNKNOWN         = "unknown job"
+	ERROR_VALIDATE_NAME_MISSING        = "missing name"
+	ERROR_VALIDATE_SCHEMA_BAD          = "bad validation schema"
+	ERROR_VALIDATE_SECRET_UNDEFINED    = "undefined secret"
+	ERROR_VALIDATE_TEMPLATE_BAD        = "bad template"
+	ERROR_VALIDATE_TYPE_UNKNOWN        = "unknown Metadata.Type"
+	ERROR_VALIDATE_VARIABLE_UNRESOLVED = "unresolved variable"
+)
+
 const (
 	ERROR_VARIABLES_FMT_BAD = "bad variable formatting"
 )
@@ -85,9 +154,15 @@ const (
 )
 
 const (
-	ERROR_LOG_PATH_EMPTY = "given path is empty"
-	ERROR_LOG_PREPARE    = "failed to open and prepare log file"
-	ERROR_LOG_UNHEALTHY  = "logger is unhealthy"
+	ERROR_LOG_EVENTS_OPEN    = "failed to open events stream"
+	ERROR_LOG_FORMAT_UNKNOWN = "unknown log format"
+	ERROR_LOG_LATEST_FAILED  = "failed to link latest log directory"
+	ERROR_LOG_PATH_EMPTY     = "given path is empty"
+	ERROR_LOG_PREPARE        = "failed to open and prepare log file"
+	ERROR_LOG_RETENTION_BAD  = "bad log retention setting"
+	ERROR_LOG_RETENTION_FAIL = "failed to remove old log directory"
+	ERROR_LOG_UNHEALTHY      = "logger is unhealthy"
+	ERROR_LOG_VERBOSITY_BAD  = "unknown log verbosity"
 )
 
 const (
@@ -120,6 +195,21 @@ const (
 	ERROR_PROGRAM_ARCHIVE_BAD            = "bad archived program's name"
 	ERROR_PROGRAM_ARCHIVE_FORMAT_BAD     = "bad archived program's format"
 	ERROR_PROGRAM_ARCHIVE_FORMAT_UNKNOWN = "unsupported archived program's format"
+	ERROR_PROGRAM_MIRROR_BAD             = "bad mirror setting"
+	ERROR_PROGRAM_NETWORK_BAD            = "bad network setting"
+	ERROR_PROGRAM_NETWORK_CA             = "failed to load CA bundle"
+	ERROR_PROGRAM_NETWORK_CERT           = "failed to load client certificate"
+
+	ERROR_PROGRAM_AUTH_BAD          = "bad program's source Auth"
+	ERROR_PROGRAM_AUTH_TYPE_UNKNOWN = "unknown program's source Auth.Type"
+
+	ERROR_PROGRAM_SIGNATURE_BAD          = "bad program's source Signature"
+	ERROR_PROGRAM_SIGNATURE_FAILED       = "program's source signature is invalid"
+	ERROR_PROGRAM_SIGNATURE_KEY          = "failed to read trusted signature key"
+	ERROR_PROGRAM_SIGNATURE_TYPE_UNKNOWN = "unknown program's source Signature.Type"
+
+	ERROR_PROGRAM_GIT_BAD    = "bad program's source Git"
+	ERROR_PROGRAM_GIT_FAILED = "failed to checkout program's git source"
 
 	ERROR_PROGRAM_CONFIG_BAD    = "bad program's config data"
 	ERROR_PROGRAM_CONFIG_FAILED = "failed to create program's config file"
@@ -151,6 +241,22 @@ const (
 	ERROR_PUBLISH_METADATA_MISSING = "missing metadata"
 )
 
+const (
+	ERROR_REPORT_WRITE = "failed to write run report"
+)
+
 const (
 	ERROR_SECRET_PARSE = "error parsing secrecy file" //nolint:gosec
+
+	ERROR_SECRET_ACTION_UNKNOWN = "unknown secrets action"
+	ERROR_SECRET_DECRYPT        = "error decrypting secrecy file"
+	ERROR_SECRET_EDIT           = "error editing secrecy file"
+	ERROR_SECRET_ENCRYPT        = "error encrypting secrecy file"
+	ERROR_SECRET_KEY_MISSING    = "missing secrets key" //nolint:gosec
+	ERROR_SECRET_PATH_MISSING   = "missing secrecy file path"
+	ERROR_SECRET_PERMISSION     = "secrecy file is accessible by others"
+
+	ERROR_SECRET_PROVIDER_BAD     = "bad secret provider"
+	ERROR_SECRET_PROVIDER_UNKNOWN = "unknown secret provider type"
+	ERROR_SECRET_HELPER_FAILED    = "secret helper failed"
 )
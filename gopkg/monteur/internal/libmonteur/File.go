@@ -20,7 +20,11 @@ package libmonteur
 // These critical object names are mainly to locate root repository with Monteur
 // supports.
 const (
+	EXTENSION_JSONL   = ".jsonl"
 	EXTENSION_LOG     = ".log"
+	EXTENSION_MINISIG = ".minisig"
+	EXTENSION_SIG     = ".sig"
+	EXTENSION_SHA256  = ".sha256"
 	EXTENSION_TOML    = ".toml"
 	EXTENSION_TARGZ   = ".tar.gz"
 	EXTENSION_ZIP     = ".zip"
@@ -38,6 +42,10 @@ const (
 	DIRECTORY_MONTEUR_CONFIG_D = "config.d"
 	DIRECTORY_MONTEUR_CONFIG   = ".configs/monteur"
 	DIRECTORY_JOBS             = "jobs"
+	DIRECTORY_HISTORY          = ".monteurFS/history"
+	DIRECTORY_STATE            = ".monteurFS/state"
+	DIRECTORY_CACHE            = ".monteurFS/cache"
+	DIRECTORY_DOWNLOADS        = "monteur/downloads" // in user cache dir
 
 	DIRECTORY_APP           = "app"
 	DIRECTORY_APP_CONFIG    = DIRECTORY_APP + "/config"
@@ -66,6 +74,12 @@ const (
 
 	FILE_LOG_OUTPUT = "output" + EXTENSION_LOG
 	FILE_LOG_STATUS = "status" + EXTENSION_LOG
+
+	FILE_REPORT_HTML  = "report.html"
+	FILE_REPORT_JUNIT = "report.xml"
+
+	FILE_LOCK       = ".monteurFS/monteur.lock"
+	FILE_SETUP_LOCK = "setup.lock"
 )
 
 const (
@@ -15,6 +15,10 @@
 
 package libmonteur
 
+import (
+	"time"
+)
+
 // Supported variables keys in key:value variables placholders.
 //
 // It is used in every toml config file inside setup/program/ config directory
@@ -24,8 +28,37 @@ const (
 
 	LOG_JOB_INIT_SUCCESS = "Task initialized successfully. Standing By..."
 	LOG_JOB_START        = "Run Task Now: " + LOG_OK
+	LOG_JOB_UP_TO_DATE   = "Inputs unchanged. Skipping: up to date"
+	LOG_JOB_RESTORED     = "Outputs restored from cache: up to date"
 
 	LOG_SUCCESS = "➤ SUCCESS"
 	LOG_OK      = "➤ OK"
 	LOG_DONE    = "➤ DONE"
 )
+
+// Log directories are the timestamped run directories inside a job's log
+// directory (e.g. `.monteurFS/log/build/2022-Mar-04T05-06-07UTC`) and the
+// symlink pointing to the newest one.
+const (
+	LOG_DIRECTORY_TIMESTAMP = "2006-Jan-02T15-04-05UTC"
+	LOG_DIRECTORY_LATEST    = "latest"
+)
+
+// Supported log formats for both log files and terminal printouts.
+const (
+	LOG_FORMAT_TEXT = "text"
+	LOG_FORMAT_JSON = "json"
+)
+
+// Supported terminal printout verbosity levels.
+const (
+	LOG_VERBOSITY_QUIET   = "quiet"
+	LOG_VERBOSITY_NORMAL  = "normal"
+	LOG_VERBOSITY_VERBOSE = "verbose"
+	LOG_VERBOSITY_DEBUG   = "debug"
+)
+
+// Progress intervals are the refresh rates of the live progress reporting.
+const (
+	PROGRESS_INTERVAL = 100 * time.Millisecond
+)
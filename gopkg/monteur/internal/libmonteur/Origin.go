@@ -0,0 +1,29 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libmonteur
+
+// Variable origin layers for inspecting where a variable's value comes from.
+//
+// The arrangement follows the overriding order where the latter overrides the
+// former.
+const (
+	ORIGIN_WORKSPACE = "workspace"
+	ORIGIN_STAGE     = "stage config [Variables]"
+	ORIGIN_STAGE_FMT = "stage config [FMTVariables]"
+	ORIGIN_TASK      = "task [Variables]"
+	ORIGIN_TASK_FMT  = "task [FMTVariables]"
+	ORIGIN_PACKAGE   = "package"
+)
@@ -20,12 +20,24 @@ package libmonteur
 // It is used in every toml config file inside setup/program/ config directory
 // for compatible function types across different stages.
 const (
+	PROGRAM_TYPE_GIT            = "git"
 	PROGRAM_TYPE_HTTPS_DOWNLOAD = "https-download"
 	PROGRAM_TYPE_LOCAL_SYSTEM   = "local-system"
 
-	PROGRAM_FORMAT_RAW    = "raw"
-	PROGRAM_FORMAT_TAR_GZ = "tar.gz"
-	PROGRAM_FORMAT_ZIP    = "zip"
+	PROGRAM_FORMAT_BZ2     = "bz2"
+	PROGRAM_FORMAT_GZ      = "gz"
+	PROGRAM_FORMAT_RAW     = "raw"
+	PROGRAM_FORMAT_TAR_BZ2 = "tar.bz2"
+	PROGRAM_FORMAT_TAR_GZ  = "tar.gz"
+	PROGRAM_FORMAT_TAR_XZ  = "tar.xz"
+	PROGRAM_FORMAT_ZIP     = "zip"
+
+	PROGRAM_AUTH_BASIC  = "basic"
+	PROGRAM_AUTH_BEARER = "bearer"
+	PROGRAM_AUTH_NETRC  = "netrc"
+
+	PROGRAM_SIGNATURE_MINISIGN = "minisign"
+	PROGRAM_SIGNATURE_SSH      = "ssh"
 
 	PROGRAM_SETUP_INST_MOVE   = "move"
 	PROGRAM_SETUP_INST_SCRIPT = "script"
@@ -26,4 +26,37 @@ package libmonteur
 const (
 	SECRET_NO_DATA  = "<no data>"
 	SECRET_REDACTED = "<REDACTED>"
+
+	// SECRET_MIN_LENGTH is the default minimum length of a redacted value.
+	SECRET_MIN_LENGTH = 6
+)
+
+const (
+	SECRET_PROVIDER_ENV    = "env"
+	SECRET_PROVIDER_HELPER = "helper"
+
+	// SECRET_HELPER_PREFIX is the executable name prefix of a secret
+	// helper. The helper is called as `monteur-secret-<Name> get <key>`.
+	SECRET_HELPER_PREFIX = "monteur-secret-"
+	SECRET_HELPER_GET    = "get"
+)
+
+const (
+	SECRET_ACTION_ENCRYPT = "encrypt"
+	SECRET_ACTION_DECRYPT = "decrypt"
+	SECRET_ACTION_EDIT    = "edit"
+)
+
+const (
+	// ENV_SECRETS_KEY is the environment variable holding the passphrase
+	// for encrypted secrets files.
+	ENV_SECRETS_KEY = "MONTEUR_SECRETS_KEY"
+
+	// ENV_SECRETS_KEY_FILE is the environment variable holding the path to
+	// a file containing the passphrase for encrypted secrets files.
+	ENV_SECRETS_KEY_FILE = "MONTEUR_SECRETS_KEY_FILE"
+
+	// ENV_EDITOR is the environment variable holding the text editor
+	// command for editing encrypted secrets files.
+	ENV_EDITOR = "EDITOR"
 )
@@ -32,10 +32,91 @@ type TOMLChangelog struct {
 	CMD       []*TOMLAction
 }
 
+type TOMLCache struct {
+	Dir string
+	URL string
+}
+
+type TOMLDownloads struct {
+	Limit uint
+
+	// Segments is the maximum concurrent Range requests per download
+	Segments uint
+}
+
+type TOMLMirror struct {
+	Prefix string
+	URL    string
+}
+
+// TOMLNetwork is the network settings for the setup downloads.
+//
+// All the values are templated so the credentials (e.g. the proxy's password
+// or the client key) can be sourced with `GetSecret`. Cert and Key are either
+// PEM contents or file paths.
+type TOMLNetwork struct {
+	Proxy    string
+	CAFiles  []string
+	Cert     string
+	Key      string
+	Insecure bool
+}
+
+func (base *TOMLNetwork) Merge(in *TOMLNetwork) {
+	if in.Proxy != "" {
+		base.Proxy = in.Proxy
+	}
+
+	if len(in.CAFiles) > 0 {
+		base.CAFiles = append(base.CAFiles, in.CAFiles...)
+	}
+
+	if in.Cert != "" {
+		base.Cert = in.Cert
+	}
+
+	if in.Key != "" {
+		base.Key = in.Key
+	}
+
+	if in.Insecure {
+		base.Insecure = true
+	}
+}
+
+type TOMLLogs struct {
+	MaxAge  string
+	MaxSize string
+	Keep    uint
+}
+
+type TOMLSecrets struct {
+	Providers        []*TOMLSecretProvider
+	MinLength        uint
+	StrictPermission bool
+}
+
+type TOMLSecretProvider struct {
+	Type   string
+	Prefix string
+	Name   string
+}
+
 type TOMLMetadata struct {
 	Name        string
 	Description string
 	Type        string
+
+	// Inputs are the glob patterns of the task's input files where `**`
+	// matches any number of directories. When set, the task is skipped as
+	// "up to date" if its inputs, commands, and variables are unchanged
+	// since its last successful run and all its Outputs still exist.
+	//
+	// Only the test, build, compose, publish, and clean tasks honour it.
+	Inputs []string
+
+	// Outputs are the paths the task produces
+	Outputs []string
 }
 
 func (me *TOMLMetadata) Sanitize(path string) (err error) {
@@ -146,14 +227,75 @@ type TOMLReleaseData struct {
 	Format string
 }
 
+// TOMLAuth is the credentials for a source download.
+//
+// Type is either `basic` (Username and Password), `bearer` (Token) or `netrc`
+// (Netrc file path, defaulting to the user's one). All the values are
+// templated so the credentials can be sourced with `GetSecret`.
+type TOMLAuth struct {
+	Type     string
+	Username string
+	Password string
+	Token    string
+	Netrc    string
+}
+
+// TOMLSignature is the detached signature of a source download.
+//
+// Type is either `minisign` or `ssh` (`ssh-keygen -Y sign`). The signature is
+// either given inline as Value or downloaded from URL (defaulting to the
+// source URL with `.minisig` or `.sig` extension). Keys are the trusted public
+// key files where relative paths are from the repository root. Namespace is
+// for `ssh` type only (defaulting to `file`).
+type TOMLSignature struct {
+	Type      string
+	URL       string
+	Value     string
+	Namespace string
+	Keys      []string
+}
+
+// TOMLGit is the pinned revision of a `git` type source.
+//
+// Ref is the branch, tag or commit to checkout (defaulting to Commit). Commit
+// is the full commit hash Ref must resolve to.
+type TOMLGit struct {
+	Ref    string
+	Commit string
+}
+
 type TOMLSource struct {
 	Checksum    *TOMLChecksum
+	Auth        *TOMLAuth
+	Signature   *TOMLSignature
+	Git         *TOMLGit
 	Headers     map[string]string
 	Archive     string
 	Format      string
 	URL         string
 	Method      string
 	Destination string
+
+	// Fallbacks are the alternative URLs tried in order when URL fails
+	Fallbacks []string
+
+	// Network is the merged workspace and task network settings (not
+	// configurable)
+	Network *TOMLNetwork `toml:"-"`
+
+	// Origin is the URL before mirror rewriting (not configurable)
+	Origin string `toml:"-"`
+}
+
+// OriginURL is to get the URL before mirror rewriting.
+//
+// It is used for identifying the source regardless of the mirror in use.
+func (base *TOMLSource) OriginURL() string {
+	if base.Origin != "" {
+		return base.Origin
+	}
+
+	return base.URL
 }
 
 func (base *TOMLSource) Merge(in *TOMLSource) {
@@ -173,6 +315,10 @@ func (base *TOMLSource) Merge(in *TOMLSource) {
 		base.Method = in.Method
 	}
 
+	if len(in.Fallbacks) > 0 {
+		base.Fallbacks = in.Fallbacks
+	}
+
 	if len(in.Headers) > 0 {
 		if len(base.Headers) == 0 {
 			base.Headers = map[string]string{}
@@ -184,6 +330,88 @@ func (base *TOMLSource) Merge(in *TOMLSource) {
 	}
 
 	base.mergeChecksum(in)
+	base.mergeAuth(in)
+	base.mergeSignature(in)
+	base.mergeGit(in)
+}
+
+func (base *TOMLSource) mergeGit(in *TOMLSource) {
+	if in.Git == nil {
+		return
+	}
+
+	if base.Git == nil {
+		base.Git = &TOMLGit{}
+	}
+
+	if in.Git.Ref != "" {
+		base.Git.Ref = in.Git.Ref
+	}
+
+	if in.Git.Commit != "" {
+		base.Git.Commit = in.Git.Commit
+	}
+}
+
+func (base *TOMLSource) mergeSignature(in *TOMLSource) {
+	if in.Signature == nil {
+		return
+	}
+
+	if base.Signature == nil {
+		base.Signature = &TOMLSignature{}
+	}
+
+	if in.Signature.Type != "" {
+		base.Signature.Type = in.Signature.Type
+	}
+
+	if in.Signature.URL != "" {
+		base.Signature.URL = in.Signature.URL
+	}
+
+	if in.Signature.Value != "" {
+		base.Signature.Value = in.Signature.Value
+	}
+
+	if in.Signature.Namespace != "" {
+		base.Signature.Namespace = in.Signature.Namespace
+	}
+
+	if len(in.Signature.Keys) > 0 {
+		base.Signature.Keys = in.Signature.Keys
+	}
+}
+
+func (base *TOMLSource) mergeAuth(in *TOMLSource) {
+	if in.Auth == nil {
+		return
+	}
+
+	if base.Auth == nil || (in.Auth.Type != "" &&
+		in.Auth.Type != base.Auth.Type) {
+		base.Auth = &TOMLAuth{}
+	}
+
+	if in.Auth.Type != "" {
+		base.Auth.Type = in.Auth.Type
+	}
+
+	if in.Auth.Username != "" {
+		base.Auth.Username = in.Auth.Username
+	}
+
+	if in.Auth.Password != "" {
+		base.Auth.Password = in.Auth.Password
+	}
+
+	if in.Auth.Token != "" {
+		base.Auth.Token = in.Auth.Token
+	}
+
+	if in.Auth.Netrc != "" {
+		base.Auth.Netrc = in.Auth.Netrc
+	}
 }
 
 func (base *TOMLSource) mergeChecksum(in *TOMLSource) {
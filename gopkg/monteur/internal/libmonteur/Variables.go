@@ -29,15 +29,24 @@ const (
 	VAR_BASE                      = "BaseDir"
 	VAR_BUILD                     = "BuildDir"
 	VAR_BIN                       = "BinDir"
+	VAR_CACHE                     = "Cache"
 	VAR_CFG                       = "ConfigDir"
 	VAR_CHANGELOG_ENTRIES         = "ChangelogEntries"
 	VAR_COMPUTE                   = "ComputeSystem"
 	VAR_DATA                      = "DataDir"
 	VAR_DOC                       = "DocsDir"
+	VAR_DOWNLOADS                 = "Downloads"
+	VAR_FORCE                     = "Force"
 	VAR_FORMAT                    = "Format"
 	VAR_HOME                      = "HomeDir"
+	VAR_JOB                       = "Job"
 	VAR_LOG                       = "LogDir"
+	VAR_LOG_FORMAT                = "LogFormat"
+	VAR_LOG_VERBOSITY             = "LogVerbosity"
 	VAR_METHOD                    = "Method"
+	VAR_MIRRORS                   = "Mirrors"
+	VAR_NETWORK                   = "Network"
+	VAR_OFFLINE                   = "Offline"
 	VAR_OS                        = "OS"
 	VAR_PACKAGE                   = "PackageDir"
 	VAR_PACKAGE_ARCH              = "PkgArch"
@@ -48,10 +57,12 @@ const (
 	VAR_RELEASE                   = "ReleaseDir"
 	VAR_ROOT                      = "RootDir"
 	VAR_SECRETS                   = "Secrets"
+	VAR_SETUP_LOCK                = "SetupLock"
 	VAR_SOURCE                    = "Source"
 	VAR_SOURCE_ARCH               = "SourceArch"
 	VAR_SOURCE_COMPUTE            = "SourceCompute"
 	VAR_SOURCE_OS                 = "SourceOS"
+	VAR_STATE                     = "StateDir"
 	VAR_TARGET                    = "Target"
 	VAR_TIMESTAMP                 = "Timestamp"
 	VAR_URL                       = "URL"
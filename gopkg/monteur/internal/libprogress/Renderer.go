@@ -0,0 +1,322 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libprogress
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/styler"
+	"gitlab.com/zoralab/monteur/gopkg/oshelper"
+	"golang.org/x/term"
+)
+
+const (
+	// DEFAULT_COLUMNS is the terminal width when it cannot be detected.
+	DEFAULT_COLUMNS = 80
+)
+
+// Renderer is the interactive terminal display of the running Jobs.
+//
+// It keeps one updating line per running task showing its spinner, current
+// step, elapsed time, and download progress. Completed tasks, outputs,
+// warnings, and errors are printed permanently above the live lines.
+//
+// Renderer implements both conductor.Display and conductor.Logger so that it
+// can be assigned as Conductor's Display and Log at the same time. All the
+// statements are forwarded to Renderer.Log for file logging.
+//
+// Renderer is safe to be created using the standard `&struct{}` method.
+type Renderer struct {
+	// Log is the logger receiving all statements without terminal printing
+	Log conductor.Logger
+
+	// Terminal is the interactive terminal for rendering
+	Terminal *os.File
+
+	mutex *sync.Mutex
+	tasks map[string]*task
+	stop  chan bool
+	done  chan bool
+	lines int
+	frame int
+}
+
+type task struct {
+	started    time.Time
+	name       string
+	step       string
+	downloaded int64
+	total      int64
+}
+
+// IsInteractive is to check the given file is suitable for live rendering.
+//
+// It is `false` when the file is not a terminal, or when `NO_COLOR` or `CI`
+// is set, or when `TERM` is `dumb`.
+func IsInteractive(f *os.File) bool {
+	switch {
+	case f == nil,
+		os.Getenv("NO_COLOR") != "",
+		os.Getenv("CI") != "",
+		os.Getenv("TERM") == "dumb":
+		return false
+	default:
+	}
+
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// Start is to begin the live rendering in the background.
+func (me *Renderer) Start() {
+	me.mutex = &sync.Mutex{}
+	me.tasks = map[string]*task{}
+	me.stop = make(chan bool)
+	me.done = make(chan bool)
+
+	go me.animate()
+}
+
+// Stop is to end the live rendering and clear all the live lines.
+func (me *Renderer) Stop() {
+	if me == nil || me.stop == nil {
+		return
+	}
+
+	close(me.stop)
+	<-me.done
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.write(me.clear())
+	me.stop = nil
+}
+
+// Render is to update the live lines with the given Job's Event.
+func (me *Renderer) Render(event conductor.Event) {
+	var line string
+
+	if me.mutex == nil {
+		return
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	t, ok := me.tasks[event.Task]
+
+	switch event.Type {
+	case conductor.EVENT_STARTED:
+		me.tasks[event.Task] = &task{
+			started: time.Now(),
+			name:    event.Task,
+		}
+	case conductor.EVENT_STEP_STARTED:
+		if ok {
+			t.step = event.Step
+			t.downloaded = 0
+			t.total = 0
+		}
+	case conductor.EVENT_PROGRESS:
+		if ok {
+			t.downloaded = event.Downloaded
+			t.total = event.Total
+		}
+	case conductor.EVENT_DONE:
+		line = "✔ " + event.Task + _duration(event.Duration)
+	case conductor.EVENT_ERROR:
+		line = "✘ " + event.Task + _duration(event.Duration)
+	default:
+		return
+	}
+
+	if line == "" {
+		me.write(me.clear() + me.draw())
+		return
+	}
+
+	delete(me.tasks, event.Task)
+	me.write(me.clear() + line + "\n" + me.draw())
+}
+
+// Info is to log an informative statement without printing it.
+func (me *Renderer) Info(format string, args ...interface{}) {
+	if me.Log != nil {
+		me.Log.Info(format, args...)
+	}
+}
+
+// Success is to log a success statement without printing it.
+func (me *Renderer) Success(format string, args ...interface{}) {
+	if me.Log != nil {
+		me.Log.Success(format, args...)
+	}
+}
+
+// Warning is to log and print a warning statement above the live lines.
+func (me *Renderer) Warning(format string, args ...interface{}) {
+	if me.Log != nil {
+		me.Log.Warning(format, args...)
+	}
+
+	me.print(format, args...)
+}
+
+// Error is to log and print an error statement above the live lines.
+func (me *Renderer) Error(format string, args ...interface{}) {
+	if me.Log != nil {
+		me.Log.Error(format, args...)
+	}
+
+	me.print(format, args...)
+}
+
+// Output is to log and print an output statement above the live lines.
+func (me *Renderer) Output(format string, args ...interface{}) {
+	if me.Log != nil {
+		me.Log.Output(format, args...)
+	}
+
+	me.print(format, args...)
+}
+
+// IsHealthy is to check the Renderer is ready for use.
+func (me *Renderer) IsHealthy() error {
+	if me.Log != nil {
+		return me.Log.IsHealthy() //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+func (me *Renderer) print(format string, args ...interface{}) {
+	if me.mutex == nil {
+		return
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	out := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	me.write(me.clear() + out + "\n" + me.draw())
+}
+
+func (me *Renderer) animate() {
+	ticker := time.NewTicker(libmonteur.PROGRESS_INTERVAL)
+	defer func() {
+		ticker.Stop()
+		close(me.done)
+	}()
+
+	for {
+		select {
+		case <-me.stop:
+			return
+		case <-ticker.C:
+			me.mutex.Lock()
+			me.frame++
+			me.write(me.clear() + me.draw())
+			me.mutex.Unlock()
+		}
+	}
+}
+
+// clear generates the sequence erasing the previously drawn live lines.
+func (me *Renderer) clear() string {
+	out := styler.CursorUp(me.lines) + styler.TERM_CLEAR_DOWN
+	me.lines = 0
+
+	return out
+}
+
+// draw generates the live lines of all the running tasks.
+func (me *Renderer) draw() string {
+	var sb strings.Builder
+
+	list := []*task{}
+	for _, t := range me.tasks {
+		list = append(list, t)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+
+	_, columns := oshelper.TermSize()
+	if columns == 0 {
+		columns = DEFAULT_COLUMNS
+	}
+
+	for _, t := range list {
+		sb.WriteString(styler.Truncate(me.line(t), columns-1) + "\n")
+	}
+
+	me.lines = len(list)
+
+	return sb.String()
+}
+
+func (me *Renderer) line(t *task) (out string) {
+	step := t.step
+	if step == "" {
+		step = "starting"
+	}
+
+	out = fmt.Sprintf("%s %s ➤ %s (%s)",
+		styler.Spinner(me.frame),
+		t.name,
+		step,
+		time.Since(t.started).Round(100*time.Millisecond),
+	)
+
+	switch {
+	case t.total > 0:
+		out += fmt.Sprintf(" ⇣ %.0f%% %s/%s",
+			float64(t.downloaded)/float64(t.total)*100,
+			styler.Bytes(t.downloaded),
+			styler.Bytes(t.total),
+		)
+	case t.downloaded > 0:
+		out += " ⇣ " + styler.Bytes(t.downloaded)
+	}
+
+	return out
+}
+
+func (me *Renderer) write(s string) {
+	if me.Terminal == nil {
+		return
+	}
+
+	_, _ = me.Terminal.WriteString(s)
+}
+
+func _duration(ms *int64) string {
+	if ms == nil {
+		return ""
+	}
+
+	d := time.Duration(*ms) * time.Millisecond
+
+	return " (" + d.Round(time.Millisecond).String() + ")"
+}
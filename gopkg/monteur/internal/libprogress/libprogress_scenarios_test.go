@@ -0,0 +1,142 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libprogress
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testIsInteractive,
+			Description: `
+IsInteractive should be false when:
+1. no file is given.
+`,
+			Switches: map[string]bool{
+				useNilFile: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testIsInteractive,
+			Description: `
+IsInteractive should be false when:
+1. the file is not a terminal.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      3,
+			TestType: testIsInteractive,
+			Description: `
+IsInteractive should be true when:
+1. the file is a terminal.
+2. NO_COLOR and CI are unset and TERM is not dumb.
+`,
+			Switches: map[string]bool{
+				useTerminal:       true,
+				expectInteractive: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testIsInteractive,
+			Description: `
+IsInteractive should be false when:
+1. the file is a terminal.
+2. NO_COLOR is set.
+`,
+			Switches: map[string]bool{
+				useTerminal: true,
+				useNoColor:  true,
+			},
+		}, {
+			UID:      5,
+			TestType: testIsInteractive,
+			Description: `
+IsInteractive should be false when:
+1. the file is a terminal.
+2. CI is set.
+`,
+			Switches: map[string]bool{
+				useTerminal: true,
+				useCI:       true,
+			},
+		}, {
+			UID:      6,
+			TestType: testIsInteractive,
+			Description: `
+IsInteractive should be false when:
+1. the file is a terminal.
+2. TERM is dumb.
+`,
+			Switches: map[string]bool{
+				useTerminal:     true,
+				useDumbTerminal: true,
+			},
+		}, {
+			UID:      7,
+			TestType: testIsInteractive,
+			Description: `
+IsInteractive should be false when:
+1. the file is not a terminal.
+2. NO_COLOR is set.
+`,
+			Switches: map[string]bool{
+				useNoColor: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testRenderer,
+			Description: `
+Renderer should do nothing when:
+1. it is nil as the non-interactive fallback.
+2. Stop is called.
+`,
+			Switches: map[string]bool{
+				useNilRenderer: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testRenderer,
+			Description: `
+Renderer should only log the statements when:
+1. it is never started.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      10,
+			TestType: testRenderer,
+			Description: `
+Renderer should only log the statements when:
+1. it is started.
+2. it has no Terminal.
+`,
+			Switches: map[string]bool{
+				useStarted:    true,
+				useNoTerminal: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testRenderer,
+			Description: `
+Renderer should log the statements and print the warnings, errors,
+outputs, and completed tasks when:
+1. it is started with a Terminal.
+`,
+			Switches: map[string]bool{
+				useStarted:    true,
+				expectPrinted: true,
+			},
+		},
+	}
+}
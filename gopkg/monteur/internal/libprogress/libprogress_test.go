@@ -0,0 +1,231 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package libprogress
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+)
+
+const (
+	testIsInteractive = "testIsInteractive"
+	testRenderer      = "testRenderer"
+)
+
+const (
+	useNilFile      = "useNilFile"
+	useTerminal     = "useTerminal"
+	useNoColor      = "useNoColor"
+	useCI           = "useCI"
+	useDumbTerminal = "useDumbTerminal"
+
+	useNilRenderer = "useNilRenderer"
+	useStarted     = "useStarted"
+	useNoTerminal  = "useNoTerminal"
+
+	expectInteractive = "expectInteractive"
+	expectPrinted     = "expectPrinted"
+)
+
+const (
+	testTask = "Build App"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// testLogger records every statement as `level: message`.
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Info(format string, args ...interface{}) {
+	l.record("info", format, args...)
+}
+
+func (l *testLogger) Success(format string, args ...interface{}) {
+	l.record("success", format, args...)
+}
+
+func (l *testLogger) Warning(format string, args ...interface{}) {
+	l.record("warning", format, args...)
+}
+
+func (l *testLogger) Error(format string, args ...interface{}) {
+	l.record("error", format, args...)
+}
+
+func (l *testLogger) Output(format string, args ...interface{}) {
+	l.record("output", format, args...)
+}
+
+func (l *testLogger) IsHealthy() error {
+	return nil
+}
+
+func (l *testLogger) record(level string,
+	format string,
+	args ...interface{}) {
+	l.lines = append(l.lines, level+": "+fmt.Sprintf(format, args...))
+}
+
+// setEnvironment sets the scenario's terminal environment variables.
+func (s *testScenario) setEnvironment(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CI", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	switch {
+	case s.Switches[useNoColor]:
+		t.Setenv("NO_COLOR", "1")
+	case s.Switches[useCI]:
+		t.Setenv("CI", "true")
+	case s.Switches[useDumbTerminal]:
+		t.Setenv("TERM", "dumb")
+	}
+}
+
+// createFile is to get the scenario's output file. The ok is false when the
+// terminal is requested but not available on this system.
+func (s *testScenario) createFile(t *testing.T) (f *os.File, ok bool) {
+	var err error
+
+	switch {
+	case s.Switches[useNilFile]:
+		return nil, true
+	case s.Switches[useTerminal]:
+		f = _openTerminal(t)
+		return f, f != nil
+	}
+
+	f, err = os.Create(filepath.Join(t.TempDir(), "output.txt"))
+	if err != nil {
+		t.Fatalf("failed to create test file: %s", err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+
+	return f, true
+}
+
+func (s *testScenario) assertIsInteractive(th *thelper.THelper, ok bool) {
+	th.ExpectSameBool("interactive", ok,
+		"expected interactive", s.Switches[expectInteractive],
+	)
+}
+
+// createRenderer is to get the scenario's Renderer alongside its logger and
+// terminal file.
+func (s *testScenario) createRenderer(t *testing.T) (r *Renderer,
+	log *testLogger,
+	f *os.File) {
+	log = &testLogger{}
+	f, _ = s.createFile(t)
+
+	if s.Switches[useNilRenderer] {
+		return nil, log, f
+	}
+
+	r = &Renderer{
+		Log:      log,
+		Terminal: f,
+	}
+
+	if s.Switches[useNoTerminal] {
+		r.Terminal = nil
+	}
+
+	return r, log, f
+}
+
+// render runs a task through the Renderer while logging every statement.
+func (s *testScenario) render(r *Renderer) {
+	duration := int64(1500)
+
+	if s.Switches[useStarted] {
+		r.Start()
+	}
+
+	if r != nil {
+		r.Render(conductor.Event{
+			Type: conductor.EVENT_STARTED,
+			Task: testTask,
+		})
+		r.Info("info %d", 1)
+		r.Success("success %d", 2)
+		r.Warning("warning %d", 3)
+		r.Error("error %d", 4)
+		r.Output("output %d\n", 5)
+		r.Render(conductor.Event{
+			Type:     conductor.EVENT_DONE,
+			Task:     testTask,
+			Duration: &duration,
+		})
+	}
+
+	r.Stop()
+}
+
+func (s *testScenario) assertRenderer(th *thelper.THelper,
+	log *testLogger,
+	f *os.File) {
+	expect := []string{
+		"info: info 1",
+		"success: success 2",
+		"warning: warning 3",
+		"error: error 4",
+		"output: output 5\n",
+	}
+	if s.Switches[useNilRenderer] {
+		expect = nil
+	}
+
+	th.ExpectSameStrings("logged", strings.Join(log.lines, "|"),
+		"expected logged", strings.Join(expect, "|"),
+	)
+
+	data, _ := os.ReadFile(f.Name())
+	out := string(data)
+
+	printed := strings.Contains(out, "warning 3\n") &&
+		strings.Contains(out, "error 4\n") &&
+		strings.Contains(out, "output 5\n") &&
+		strings.Contains(out, "✔ "+testTask+" (1.5s)\n")
+	th.ExpectSameBool("printed", printed,
+		"expected printed", s.Switches[expectPrinted],
+	)
+
+	th.ExpectSameBool("printed info", strings.Contains(out, "info 1") ||
+		strings.Contains(out, "success 2"),
+		"expected printed info", false,
+	)
+
+	if !s.Switches[expectPrinted] {
+		th.ExpectSameStrings("terminal", out, "expected terminal", "")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package libprogress
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// _openTerminal opens a new pseudo terminal. It returns nil when it is not
+// available.
+func _openTerminal(t *testing.T) *os.File {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		return nil
+	}
+	t.Cleanup(func() { _ = ptmx.Close() })
+
+	fd := int(ptmx.Fd())
+
+	err = unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0)
+	if err != nil {
+		return nil
+	}
+
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		return nil
+	}
+
+	pts, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n),
+		os.O_RDWR|unix.O_NOCTTY,
+		0,
+	)
+	if err != nil {
+		return nil
+	}
+	t.Cleanup(func() { _ = pts.Close() })
+
+	return pts
+}
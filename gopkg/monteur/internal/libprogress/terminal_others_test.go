@@ -0,0 +1,29 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+
+//go:build !linux
+// +build !linux
+
+package libprogress
+
+import (
+	"os"
+	"testing"
+)
+
+// _openTerminal opens a new pseudo terminal. It is not available on this
+// system so it always returns nil.
+func _openTerminal(t *testing.T) *os.File {
+	return nil
+}
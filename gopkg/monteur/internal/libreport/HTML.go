@@ -0,0 +1,136 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libreport
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// htmlPage is the self-contained HTML report page without external assets.
+const htmlPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Monteur {{ .Stage }} Report</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; margin: 1rem 0; }
+th, td { border: 1px solid #ccc; padding: .3rem .6rem; text-align: left;
+	vertical-align: top; }
+th { background: #f4f4f4; }
+pre { background: #111; color: #eee; padding: 1rem; overflow-x: auto; }
+code { white-space: pre-wrap; word-break: break-all; }
+details { border: 1px solid #ccc; margin: 1rem 0; padding: .5rem 1rem; }
+summary { cursor: pointer; font-weight: bold; }
+.status { padding: .1rem .5rem; border-radius: .3rem; color: #fff; }
+.passed { background: #2e7d32; }
+.failed { background: #c62828; }
+.cancelled, .running { background: #757575; }
+.error { color: #c62828; }
+</style>
+</head>
+<body>
+<h1>Monteur {{ .Stage }} Report</h1>
+<table>
+<tr><th>Stage</th><td>{{ .Stage }}</td></tr>
+<tr><th>Status</th>
+<td><span class="status {{ .Status }}">{{ .Status }}</span></td></tr>
+<tr><th>Started</th><td>{{ timestamp .Started }}</td></tr>
+<tr><th>Duration</th><td>{{ duration .Duration }}</td></tr>
+<tr><th>Tasks</th><td>{{ len .Tasks }}</td></tr>
+</table>
+{{- if .Error }}
+<p class="error">{{ .Error }}</p>
+{{- end }}
+<table>
+<tr><th>Task</th><th>Status</th><th>Duration</th><th>Logs</th></tr>
+{{- range .Tasks }}
+<tr>
+<td><a href="#{{ .Name }}">{{ .Name }}</a></td>
+<td><span class="status {{ .Status }}">{{ .Status }}</span></td>
+<td>{{ duration .Duration }}</td>
+<td><a href="{{ .StatusLog }}">status</a> |
+<a href="{{ .OutputLog }}">output</a></td>
+</tr>
+{{- end }}
+</table>
+{{- range .Tasks }}
+<details id="{{ .Name }}"{{ if ne .Status "passed" }} open{{ end }}>
+<summary>{{ .Name }} <span class="status {{ .Status }}">{{ .Status }}</span>
+({{ duration .Duration }})</summary>
+{{- if .Error }}
+<p class="error">{{ .Error }}</p>
+{{- end }}
+<table>
+<tr><th>#</th><th>Step</th><th>Type</th><th>Command</th><th>Exit Code</th>
+<th>Duration</th></tr>
+{{- range .Steps }}
+<tr>
+<td>{{ .Index }}</td>
+<td>{{ .Name }}</td>
+<td>{{ .Type }}</td>
+<td><code>{{ .Command }}</code></td>
+<td>{{ if .Finished }}{{ .ExitCode }}{{ else }}-{{ end }}</td>
+<td>{{ if .Finished }}{{ duration .Duration }}{{ else }}-{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+<p>Logs: <a href="{{ .StatusLog }}">{{ .StatusLog }}</a> |
+<a href="{{ .OutputLog }}">{{ .OutputLog }}</a></p>
+{{- if .Tail }}
+<p>Output:</p>
+<pre>{{ .Tail }}</pre>
+{{- end }}
+</details>
+{{- end }}
+</body>
+</html>
+`
+
+func (me *Report) html() (buf *bytes.Buffer, err error) {
+	var t *template.Template
+
+	t, err = template.New("report").Funcs(template.FuncMap{
+		"duration":  _duration,
+		"timestamp": _timestamp,
+	}).Parse(htmlPage)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_REPORT_WRITE, err)
+	}
+
+	buf = &bytes.Buffer{}
+
+	err = t.Execute(buf, me)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_REPORT_WRITE, err)
+	}
+
+	return buf, nil
+}
+
+func _duration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+func _timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libreport
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name      `xml:"testsuites"`
+	Name     string        `xml:"name,attr"`
+	Time     string        `xml:"time,attr"`
+	Suites   []*junitSuite `xml:"testsuite"`
+	Tests    int           `xml:"tests,attr"`
+	Failures int           `xml:"failures,attr"`
+	Skipped  int           `xml:"skipped,attr"`
+}
+
+type junitSuite struct {
+	Name      string       `xml:"name,attr"`
+	Timestamp string       `xml:"timestamp,attr"`
+	Time      string       `xml:"time,attr"`
+	Cases     []*junitCase `xml:"testcase"`
+	Tests     int          `xml:"tests,attr"`
+	Failures  int          `xml:"failures,attr"`
+	Skipped   int          `xml:"skipped,attr"`
+}
+
+type junitCase struct {
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func (me *Report) junit() (buf *bytes.Buffer, err error) {
+	var data []byte
+
+	suite := &junitSuite{
+		Name:      me.Stage,
+		Timestamp: _timestamp(me.Started),
+		Time:      _seconds(me.Duration()),
+	}
+
+	for _, task := range me.Tasks {
+		c := &junitCase{
+			Name:      task.Name,
+			Classname: "monteur." + me.Stage,
+			Time:      _seconds(task.Duration),
+			SystemOut: task.summary(),
+		}
+
+		switch task.Status {
+		case conductor.RESULT_FAILED:
+			c.Failure = &junitMessage{
+				Message: task.Error,
+				Body:    task.Tail,
+			}
+			suite.Failures++
+		case conductor.RESULT_CANCELLED, conductor.RESULT_RUNNING:
+			c.Skipped = &junitMessage{Message: task.Status}
+			suite.Skipped++
+		default:
+		}
+
+		suite.Cases = append(suite.Cases, c)
+		suite.Tests++
+	}
+
+	data, err = xml.MarshalIndent(&junitSuites{
+		Name:     "monteur",
+		Time:     suite.Time,
+		Suites:   []*junitSuite{suite},
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_REPORT_WRITE, err)
+	}
+
+	buf = bytes.NewBufferString(xml.Header)
+	buf.Write(data)
+	buf.WriteString("\n")
+
+	return buf, nil
+}
+
+func (task *Task) summary() string {
+	var sb strings.Builder
+
+	for _, step := range task.Steps {
+		if !step.Finished {
+			fmt.Fprintf(&sb, "Step %d '%s': unfinished\n",
+				step.Index,
+				step.Name,
+			)
+
+			continue
+		}
+
+		fmt.Fprintf(&sb, "Step %d '%s' [%s] exit %d (%s): %s\n",
+			step.Index,
+			step.Name,
+			step.Type,
+			step.ExitCode,
+			_duration(step.Duration),
+			step.Command,
+		)
+	}
+
+	fmt.Fprintf(&sb, "Logs: %s, %s\n", task.StatusLog, task.OutputLog)
+
+	if task.Tail != "" {
+		fmt.Fprintf(&sb, "\nOutput:\n%s\n", task.Tail)
+	}
+
+	return sb.String()
+}
+
+func _seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libreport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libcmd"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	// TAIL_LINES is the maximum lines of a task's log kept in the report.
+	TAIL_LINES = 40
+
+	// TAIL_SIZE is the maximum bytes read from the end of a task's log.
+	TAIL_SIZE = 64 * 1024
+)
+
+// Report is the run report of a Job written after all its tasks ended.
+//
+// Report is safe to be created using the standard `&struct{}` method.
+type Report struct {
+	// Started is the Job's starting timestamp
+	Started time.Time
+
+	// Stage is the Job's name (e.g. `build`)
+	Stage string
+
+	// Dir is the Job's log directory housing all the tasks' log files
+	//
+	// The reports are written into this directory.
+	Dir string
+
+	// Error is the Job's failure message if available
+	Error string
+
+	// Tasks are the tasks' execution records
+	Tasks []*Task
+}
+
+// Task is the execution record of a single task inside the Report.
+type Task struct {
+	*conductor.Result
+
+	// StatusLog is the task's status log filename relative to Report.Dir
+	StatusLog string
+
+	// OutputLog is the task's output log filename relative to Report.Dir
+	OutputLog string
+
+	// Tail is the last TAIL_LINES of the task's output log
+	Tail string
+}
+
+// Add is to add a task's execution record into the Report.
+//
+// The task's log files are located from its name inside Report.Dir.
+func (me *Report) Add(result *conductor.Result) {
+	task := &Task{Result: result}
+	task.StatusLog, task.OutputLog = libcmd.LogFilenames(result.Name)
+	task.Tail = _tail(filepath.Join(me.Dir, task.OutputLog))
+
+	me.Tasks = append(me.Tasks, task)
+}
+
+// Status is to obtain the overall status of the Job.
+//
+// It is `failed` when any task failed or the Job has an error, `cancelled`
+// when any task did not complete, and `passed` otherwise.
+func (me *Report) Status() string {
+	status := conductor.RESULT_PASSED
+	if me.Error != "" {
+		status = conductor.RESULT_FAILED
+	}
+
+	for _, task := range me.Tasks {
+		switch task.Status {
+		case conductor.RESULT_FAILED:
+			return conductor.RESULT_FAILED
+		case conductor.RESULT_CANCELLED:
+			status = conductor.RESULT_CANCELLED
+		default:
+		}
+	}
+
+	return status
+}
+
+// Duration is to obtain the Job's elapsed time since Report.Started.
+func (me *Report) Duration() time.Duration {
+	return time.Since(me.Started)
+}
+
+// Write is to write both the HTML and JUnit XML reports into Report.Dir.
+func (me *Report) Write() (err error) {
+	var buf *bytes.Buffer
+
+	buf, err = me.html()
+	if err != nil {
+		return err
+	}
+
+	err = _write(filepath.Join(me.Dir, libmonteur.FILE_REPORT_HTML), buf)
+	if err != nil {
+		return err
+	}
+
+	buf, err = me.junit()
+	if err != nil {
+		return err
+	}
+
+	return _write(filepath.Join(me.Dir, libmonteur.FILE_REPORT_JUNIT), buf)
+}
+
+func _write(path string, buf *bytes.Buffer) (err error) {
+	err = os.WriteFile(path, buf.Bytes(), libmonteur.PERMISSION_FILE)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_REPORT_WRITE, err)
+	}
+
+	return nil
+}
+
+func _tail(path string) string {
+	var info os.FileInfo
+	var data []byte
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	info, err = f.Stat()
+	if err != nil {
+		return ""
+	}
+
+	if info.Size() > TAIL_SIZE {
+		_, err = f.Seek(-TAIL_SIZE, io.SeekEnd)
+		if err != nil {
+			return ""
+		}
+	}
+
+	data, err = io.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > TAIL_LINES {
+		lines = lines[len(lines)-TAIL_LINES:]
+	}
+
+	return strings.Join(lines, "\n")
+}
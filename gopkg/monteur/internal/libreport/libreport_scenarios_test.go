@@ -0,0 +1,130 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libreport
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testHTML,
+			Description: `
+Report.Write should write the HTML page with the task's log links when:
+1. the task passed.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testHTML,
+			Description: `
+Report.Write should write the HTML page with the error and the output log's tail
+when:
+1. the task failed.
+`,
+			Switches: map[string]bool{
+				useFailedTask: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testHTML,
+			Description: `
+Report.Write should write the HTML page with the task marked as unfinished when:
+1. the task was cancelled.
+`,
+			Switches: map[string]bool{
+				useCancelledTask: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testHTML,
+			Description: `
+Report.Write should write the HTML page with only the last lines of the output
+log when:
+1. the task failed.
+2. the output log is longer than TAIL_LINES.
+`,
+			Switches: map[string]bool{
+				useFailedTask: true,
+				useLongOutput: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testHTML,
+			Description: `
+Report.Write should write the HTML page with all the markup escaped when:
+1. the task failed.
+2. its name, command, error and output have markup characters.
+`,
+			Switches: map[string]bool{
+				useFailedTask: true,
+				useMarkup:     true,
+			},
+		}, {
+			UID:      6,
+			TestType: testJUnit,
+			Description: `
+Report.Write should write the JUnit XML with the task's log links when:
+1. the task passed.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      7,
+			TestType: testJUnit,
+			Description: `
+Report.Write should write the JUnit XML with the error and the output log's tail
+when:
+1. the task failed.
+`,
+			Switches: map[string]bool{
+				useFailedTask: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testJUnit,
+			Description: `
+Report.Write should write the JUnit XML with the task marked as unfinished when:
+1. the task was cancelled.
+`,
+			Switches: map[string]bool{
+				useCancelledTask: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testJUnit,
+			Description: `
+Report.Write should write the JUnit XML with only the last lines of the output
+log when:
+1. the task failed.
+2. the output log is longer than TAIL_LINES.
+`,
+			Switches: map[string]bool{
+				useFailedTask: true,
+				useLongOutput: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testJUnit,
+			Description: `
+Report.Write should write the JUnit XML with all the markup escaped when:
+1. the task failed.
+2. its name, command, error and output have markup characters.
+`,
+			Switches: map[string]bool{
+				useFailedTask: true,
+				useMarkup:     true,
+			},
+		},
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libreport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/conductor"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libcmd"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	testHTML  = "testHTML"
+	testJUnit = "testJUnit"
+)
+
+const (
+	useFailedTask    = "useFailedTask"
+	useCancelledTask = "useCancelledTask"
+	useMarkup        = "useMarkup"
+	useLongOutput    = "useLongOutput"
+)
+
+const (
+	reportStage    = "build"
+	reportTask     = "Build App"
+	reportStep     = "Compile"
+	reportCommand  = "go build -o bin/app"
+	reportError    = "exit status 2"
+	reportOutput   = "output line"
+	reportStatus   = "status line"
+	reportMarkup   = "<script>alert('x' & \"y\")"
+	reportEscaped  = "&lt;script&gt;"
+	reportLongLine = 50
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createReport creates the Report with a single task whose status and output
+// logs are in the Report's directory.
+func (s *testScenario) createReport(t *testing.T) *Report {
+	report := &Report{
+		Started: time.Now().Add(-time.Second),
+		Stage:   reportStage,
+		Dir:     t.TempDir(),
+	}
+
+	result := &conductor.Result{
+		Started:  report.Started,
+		Name:     reportTask,
+		Status:   conductor.RESULT_PASSED,
+		Duration: 500 * time.Millisecond,
+		Steps: []*conductor.StepResult{
+			{
+				Name:     reportStep,
+				Type:     "command",
+				Command:  reportCommand,
+				Index:    1,
+				Duration: 400 * time.Millisecond,
+				Finished: true,
+			},
+		},
+	}
+
+	switch {
+	case s.Switches[useFailedTask]:
+		result.Status = conductor.RESULT_FAILED
+		result.Error = reportError
+		result.Steps[0].ExitCode = 2
+	case s.Switches[useCancelledTask]:
+		result.Status = conductor.RESULT_CANCELLED
+		result.Steps[0].Finished = false
+	}
+
+	if s.Switches[useMarkup] {
+		result.Name = reportTask + " " + reportMarkup
+		result.Error = reportMarkup
+		result.Steps[0].Command = reportMarkup
+	}
+
+	status, output := libcmd.LogFilenames(result.Name)
+	s.createLog(t, filepath.Join(report.Dir, status), reportStatus)
+	s.createLog(t, filepath.Join(report.Dir, output), s.output())
+
+	report.Add(result)
+
+	return report
+}
+
+func (s *testScenario) createLog(t *testing.T, path string, line string) {
+	lines := []string{line}
+	if s.Switches[useLongOutput] {
+		lines = []string{}
+		for i := 1; i <= reportLongLine; i++ {
+			lines = append(lines, fmt.Sprintf("%s %d", line, i))
+		}
+	}
+
+	err := os.WriteFile(path,
+		[]byte(strings.Join(lines, "\n")+"\n"),
+		0644,
+	)
+	if err != nil {
+		t.Fatalf("failed to create test log: %s", err)
+	}
+}
+
+func (s *testScenario) output() string {
+	if s.Switches[useMarkup] {
+		return reportMarkup
+	}
+
+	return reportOutput
+}
+
+// expectTail is to get the expected tail of the task's output log.
+func (s *testScenario) expectTail() string {
+	if !s.Switches[useLongOutput] {
+		return s.output()
+	}
+
+	lines := []string{}
+	for i := reportLongLine - TAIL_LINES + 1; i <= reportLongLine; i++ {
+		lines = append(lines, fmt.Sprintf("%s %d", reportOutput, i))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (s *testScenario) read(th *thelper.THelper,
+	report *Report,
+	filename string) string {
+	data, err := os.ReadFile(filepath.Join(report.Dir, filename))
+	th.ExpectError(err, false)
+
+	return string(data)
+}
+
+func (s *testScenario) assertTail(th *thelper.THelper, report *Report) {
+	th.ExpectSameStrings("tail", report.Tasks[0].Tail,
+		"expected tail", s.expectTail(),
+	)
+}
+
+func (s *testScenario) assertContains(th *thelper.THelper,
+	page string,
+	label string,
+	text string,
+	expected bool) {
+	th.ExpectSameBool(label, strings.Contains(page, text),
+		"expected "+label, expected,
+	)
+}
+
+func (s *testScenario) assertHTML(th *thelper.THelper,
+	report *Report,
+	err error) {
+	th.ExpectError(err, false)
+	s.assertTail(th, report)
+
+	page := s.read(th, report, libmonteur.FILE_REPORT_HTML)
+	task := report.Tasks[0]
+
+	s.assertContains(th, page, "status log tail", reportStatus, false)
+	s.assertContains(th, page, "opened details", " open>",
+		s.Switches[useFailedTask] || s.Switches[useCancelledTask],
+	)
+
+	if s.Switches[useMarkup] {
+		s.assertContains(th, page, "raw markup", reportMarkup, false)
+		s.assertContains(th, page, "escaped markup",
+			reportEscaped, true,
+		)
+
+		return
+	}
+
+	s.assertContains(th, page, "status log link",
+		`href="`+task.StatusLog+`"`, true,
+	)
+	s.assertContains(th, page, "output log link",
+		`href="`+task.OutputLog+`"`, true,
+	)
+	s.assertContains(th, page, "command", reportCommand, true)
+	s.assertContains(th, page, "output tail",
+		"<pre>"+s.expectTail()+"</pre>", true,
+	)
+	s.assertContains(th, page, "error", reportError,
+		s.Switches[useFailedTask],
+	)
+	s.assertContains(th, page, "truncated output",
+		reportOutput+" 1\n", false,
+	)
+}
+
+func (s *testScenario) assertJUnit(th *thelper.THelper,
+	report *Report,
+	err error) {
+	th.ExpectError(err, false)
+	s.assertTail(th, report)
+
+	page := s.read(th, report, libmonteur.FILE_REPORT_JUNIT)
+	suites := &junitSuites{}
+
+	err = xml.Unmarshal([]byte(page), suites)
+	th.ExpectError(err, false)
+
+	if len(suites.Suites) != 1 || len(suites.Suites[0].Cases) != 1 {
+		th.ExpectSameStrings("suites", page,
+			"expected suites", "1 suite with 1 test case",
+		)
+
+		return
+	}
+
+	suite := suites.Suites[0]
+	c := suite.Cases[0]
+
+	th.ExpectSameStrings("suite", suite.Name, "expected suite", reportStage)
+	th.ExpectSameStrings("case", c.Name,
+		"expected case", report.Tasks[0].Name,
+	)
+	th.ExpectSameStrings("counts",
+		fmt.Sprint(suites.Tests, suites.Failures, suites.Skipped),
+		"expected counts",
+		fmt.Sprint(1,
+			map[bool]int{true: 1}[s.Switches[useFailedTask]],
+			map[bool]int{true: 1}[s.Switches[useCancelledTask]],
+		),
+	)
+	th.ExpectSameBool("failure", c.Failure != nil,
+		"expected failure", s.Switches[useFailedTask],
+	)
+	th.ExpectSameBool("skipped", c.Skipped != nil,
+		"expected skipped", s.Switches[useCancelledTask],
+	)
+	th.ExpectSameBool("output in system-out",
+		strings.Contains(c.SystemOut, s.expectTail()),
+		"expected output in system-out", true,
+	)
+	th.ExpectSameBool("status in system-out",
+		strings.Contains(c.SystemOut, reportStatus),
+		"expected status in system-out", false,
+	)
+
+	if c.Failure != nil {
+		th.ExpectSameStrings("failure message", c.Failure.Message,
+			"expected failure message", report.Tasks[0].Error,
+		)
+		th.ExpectSameStrings("failure body", c.Failure.Body,
+			"expected failure body", s.expectTail(),
+		)
+	}
+
+	if s.Switches[useMarkup] {
+		s.assertContains(th, page, "raw markup", reportMarkup, false)
+	}
+}
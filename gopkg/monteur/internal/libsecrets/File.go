@@ -0,0 +1,146 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libsecrets
+
+import (
+	"fmt"
+	"os"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/endec/toml"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/secrets"
+)
+
+const (
+	// FILE_PERMISSION is the file permission for writing secrets files.
+	FILE_PERMISSION = 0600
+
+	// PERMISSION_MASK is the permission bits a plain secrets file shall not
+	// have (any access by group or others).
+	PERMISSION_MASK = 0077
+)
+
+// ReadFile reads a secrets file and decrypts it when it is encrypted.
+//
+// The returned data is the plain TOML document. The caller is responsible to
+// zero it using `secrets.Zero` after use.
+func ReadFile(path string, key []byte) (out []byte, err error) {
+	var data []byte
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", libmonteur.ERROR_SECRET_PARSE, err)
+	}
+
+	if !secrets.IsEncrypted(data) {
+		return data, nil
+	}
+
+	out, err = secrets.Decrypt(data, key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %s",
+			libmonteur.ERROR_SECRET_DECRYPT,
+			err,
+			path,
+		)
+	}
+
+	return out, nil
+}
+
+// EncryptFile encrypts a plain secrets file in place.
+//
+// The file must be a valid TOML document to avoid sealing a broken secrets
+// file.
+func EncryptFile(path string, key []byte) (err error) {
+	var plain, out []byte
+
+	plain, err = os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SECRET_ENCRYPT, err)
+	}
+	defer secrets.Zero(plain)
+
+	if secrets.IsEncrypted(plain) {
+		return fmt.Errorf("%s: already encrypted: %s",
+			libmonteur.ERROR_SECRET_ENCRYPT,
+			path,
+		)
+	}
+
+	out, err = Seal(plain, key)
+	if err != nil {
+		return err
+	}
+
+	//nolint:wrapcheck
+	return os.WriteFile(path, out, FILE_PERMISSION)
+}
+
+// DecryptFile decrypts an encrypted secrets file in place.
+func DecryptFile(path string, key []byte) (err error) {
+	var plain []byte
+
+	plain, err = os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_SECRET_DECRYPT, err)
+	}
+
+	if !secrets.IsEncrypted(plain) {
+		return fmt.Errorf("%s: %s: %s",
+			libmonteur.ERROR_SECRET_DECRYPT,
+			secrets.ERROR_NOT_ENCRYPTED,
+			path,
+		)
+	}
+
+	plain, err = ReadFile(path, key)
+	if err != nil {
+		return err
+	}
+	defer secrets.Zero(plain)
+
+	err = os.WriteFile(path, plain, FILE_PERMISSION)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// existing file retains its permission so restrict it explicitly
+	return os.Chmod(path, FILE_PERMISSION) //nolint:wrapcheck
+}
+
+// Seal checks the plain data is a valid TOML document and encrypts it.
+func Seal(plain []byte, key []byte) (out []byte, err error) {
+	data := map[string]interface{}{}
+
+	err = toml.DecodeBytes(plain, &data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_SECRET_ENCRYPT,
+			libmonteur.ERROR_SECRET_PARSE,
+		)
+	}
+
+	out, err = secrets.Encrypt(plain, key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_SECRET_ENCRYPT,
+			err,
+		)
+	}
+
+	return out, nil
+}
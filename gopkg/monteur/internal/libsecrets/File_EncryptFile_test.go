@@ -0,0 +1,75 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libsecrets
+
+import (
+	"testing"
+)
+
+func TestEncryptFile(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testFile {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		path := s.createFile(t)
+
+		// test
+		err := EncryptFile(path, []byte(testFileKey))
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertEncryptFile(th, path, err)
+		s.log(th, map[string]interface{}{
+			"path":  path,
+			"error": err,
+		})
+		th.Conclude()
+	}
+}
+
+func TestDecryptFile(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testFile || s.Switches[expectEncryptError] {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		path := s.createFile(t)
+
+		err := EncryptFile(path, []byte(testFileKey))
+		if err != nil {
+			t.Fatalf("failed to encrypt test file: %s", err)
+		}
+
+		s.tamperFile(t, path)
+
+		// test
+		err = DecryptFile(path, s.decryptKey())
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertDecryptFile(th, path, err)
+		s.log(th, map[string]interface{}{
+			"path":  path,
+			"error": err,
+		})
+		th.Conclude()
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libsecrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/secrets"
+	"golang.org/x/term"
+)
+
+// Key obtains the passphrase for encrypted secrets files.
+//
+// The passphrase is sourced in the following order:
+//   1. `MONTEUR_SECRETS_KEY` environment variable.
+//   2. the file pointed by `MONTEUR_SECRETS_KEY_FILE` environment variable.
+//   3. interactive prompt when the standard input is a terminal.
+//
+// When `confirm` is set, the interactive prompt asks the passphrase twice and
+// both must match. It is meant for encrypting a new file.
+func Key(confirm bool) (key []byte, err error) {
+	var path string
+	var again []byte
+
+	if v := os.Getenv(libmonteur.ENV_SECRETS_KEY); v != "" {
+		return []byte(v), nil
+	}
+
+	path = os.Getenv(libmonteur.ENV_SECRETS_KEY_FILE)
+	if path != "" {
+		return _keyFromFile(path)
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("%s: set %s or %s",
+			libmonteur.ERROR_SECRET_KEY_MISSING,
+			libmonteur.ENV_SECRETS_KEY,
+			libmonteur.ENV_SECRETS_KEY_FILE,
+		)
+	}
+
+	key, err = _prompt("Secrets Key: ")
+	if err != nil || !confirm {
+		return key, err
+	}
+
+	again, err = _prompt("Confirm Secrets Key: ")
+	defer secrets.Zero(again)
+	if err != nil {
+		secrets.Zero(key)
+		return nil, err
+	}
+
+	if !bytes.Equal(key, again) {
+		secrets.Zero(key)
+		return nil, fmt.Errorf("%s: keys do not match",
+			libmonteur.ERROR_SECRET_KEY_MISSING,
+		)
+	}
+
+	return key, nil
+}
+
+func _keyFromFile(path string) (key []byte, err error) {
+	var data []byte
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_SECRET_KEY_MISSING,
+			err,
+		)
+	}
+
+	key = make([]byte, len(bytes.TrimRight(data, "\r\n")))
+	copy(key, data)
+	secrets.Zero(data)
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%s: empty %s",
+			libmonteur.ERROR_SECRET_KEY_MISSING,
+			path,
+		)
+	}
+
+	return key, nil
+}
+
+func _prompt(label string) (key []byte, err error) {
+	fmt.Fprintf(os.Stderr, "%s", label)
+	key, err = term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintf(os.Stderr, "\n")
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_SECRET_KEY_MISSING,
+			err,
+		)
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf(libmonteur.ERROR_SECRET_KEY_MISSING)
+	}
+
+	return key, nil
+}
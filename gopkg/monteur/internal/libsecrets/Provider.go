@@ -0,0 +1,164 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+const (
+	// HELPER_TIMEOUT is the maximum duration for a secret helper to reply.
+	HELPER_TIMEOUT = 30 * time.Second
+)
+
+// Provider is a secret source other than the secrets files.
+//
+// Providers are only queried when the key is absent from the secrets files.
+// `ok` is `false` when the provider does not have the key.
+type Provider interface {
+	Get(key string) (value interface{}, ok bool, err error)
+}
+
+// NewProvider creates a Provider from its workspace configuration.
+func NewProvider(cfg *libmonteur.TOMLSecretProvider) (p Provider, err error) {
+	if cfg == nil {
+		return nil, fmt.Errorf(libmonteur.ERROR_SECRET_PROVIDER_BAD)
+	}
+
+	switch cfg.Type {
+	case libmonteur.SECRET_PROVIDER_ENV:
+		return &envProvider{prefix: cfg.Prefix}, nil
+	case libmonteur.SECRET_PROVIDER_HELPER:
+		if cfg.Name == "" || strings.ContainsAny(cfg.Name, `/\ `) {
+			return nil, fmt.Errorf("%s: bad helper name '%s'",
+				libmonteur.ERROR_SECRET_PROVIDER_BAD,
+				cfg.Name,
+			)
+		}
+
+		return &helperProvider{
+			program: libmonteur.SECRET_HELPER_PREFIX + cfg.Name,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_SECRET_PROVIDER_UNKNOWN,
+			cfg.Type,
+		)
+	}
+}
+
+// envProvider sources secrets from the environment variables.
+//
+// The key is looked up as `<Prefix><key>` first. If absent, the key is
+// normalized into the conventional environment variable name where
+// `db.Password` with `MONTEUR_SECRET_` prefix becomes
+// `MONTEUR_SECRET_DB_PASSWORD`.
+type envProvider struct {
+	prefix string
+}
+
+func (me *envProvider) Get(key string) (value interface{}, ok bool,
+	err error) {
+	for _, name := range []string{
+		me.prefix + key,
+		me.prefix + _envName(key),
+	} {
+		value, ok = os.LookupEnv(name)
+		if ok {
+			return value, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func _envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// helperProvider sources secrets from an external helper executable.
+//
+// The helper is executed as `monteur-secret-<Name> get <key>` and must reply a
+// JSON object through its stdout. The value is in the `value` field. An empty
+// object (`{}`) or a reply without `value` field means the helper does not have
+// the key. Its stderr is passed through for interactive helpers.
+type helperProvider struct {
+	program string
+}
+
+func (me *helperProvider) Get(key string) (value interface{}, ok bool,
+	err error) {
+	var path string
+	var out []byte
+
+	reply := map[string]interface{}{}
+
+	path, err = exec.LookPath(me.program)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_SECRET_HELPER_FAILED,
+			err,
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), HELPER_TIMEOUT)
+	defer cancel()
+
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, path, libmonteur.SECRET_HELPER_GET, key)
+	cmd.Stderr = os.Stderr
+
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %s: %s",
+			libmonteur.ERROR_SECRET_HELPER_FAILED,
+			me.program,
+			err,
+		)
+	}
+
+	err = json.Unmarshal(out, &reply)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %s: bad reply",
+			libmonteur.ERROR_SECRET_HELPER_FAILED,
+			me.program,
+		)
+	}
+
+	value, ok = reply["value"]
+	if !ok || value == nil {
+		return nil, false, nil
+	}
+
+	return value, true, nil
+}
@@ -17,65 +17,457 @@ package libsecrets
 
 import (
 	"fmt"
-	"strings"
+	"io"
+	"os"
+	"runtime"
+	"sort"
 	"sync"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/endec/toml"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/redactor"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/secrets"
 )
 
 type Secrets struct {
-	data  map[string]interface{}
-	mutex *sync.RWMutex
+	data      map[string]interface{}
+	missing   map[string]bool
+	pending   map[string]chan struct{}
+	providers []Provider
+	mutex     *sync.RWMutex
+	redactor  *redactor.Redactor
+	key       []byte
+	queried   map[string]bool
+	warnings  []error
+	audit     *sync.Mutex
+
+	// MinLength is the minimum length of a secret value to be redacted.
+	//
+	// Shorter values (e.g. `true` or `1`) are not redacted. When it is `0`,
+	// libmonteur.SECRET_MIN_LENGTH is used.
+	MinLength uint
+
+	// StrictPermission fails the parsing when a plain secrets file is
+	// accessible by group or others. Otherwise, only a warning is recorded
+	// (see `Warnings`).
+	StrictPermission bool
 }
 
 func (me *Secrets) Parse(pathings []string) (err error) {
 	me.data = map[string]interface{}{}
+	me.missing = map[string]bool{}
+	me.pending = map[string]chan struct{}{}
+	me.warnings = nil
+	if me.MinLength == 0 {
+		me.MinLength = libmonteur.SECRET_MIN_LENGTH
+	}
+	me.redactor = redactor.New(int(me.MinLength), libmonteur.SECRET_REDACTED)
 	if me.mutex == nil {
 		me.mutex = &sync.RWMutex{}
 	}
 
+	if me.audit == nil {
+		me.audit = &sync.Mutex{}
+	}
+
 	me.mutex.Lock()
 	defer me.mutex.Unlock()
 
-	s := &secrets.Processor{DecodeFx: toml.SilentDecodeFile}
+	defer func() {
+		secrets.Zero(me.key)
+		me.key = nil
+	}()
+
+	s := &secrets.Processor{DecodeFx: me._decodeFile}
+
+	for _, path := range pathings {
+		if _, err = os.Stat(path); err != nil {
+			continue
+		}
+
+		me.data, err = s.DecodeDir(me.data, path, nil)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	for k, v := range me.data {
+		me.data[k] = me._store(v)
+	}
+
+	return nil
+}
+
+// _store converts the value into its storage type and registers it for
+// redaction.
+//
+// Texts are stored as `[]byte` so that `Delete` can zero the stored copy.
+// The strings handed out by `Query` are separate copies that cannot be zeroed
+// and are left to the garbage collector.
+func (me *Secrets) _store(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		// never a secret on its own
+		return v
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		me.redactor.Add(fmt.Sprintf("%v", v))
+		return v
+	case string:
+		me.redactor.Add(v)
+		return []byte(v)
+	default:
+		out := []byte(fmt.Sprintf("%v", v))
+		me.redactor.Add(string(out))
+
+		return out
+	}
+}
+
+func (me *Secrets) _decodeFile(path string,
+	out interface{}, config interface{}) (err error) {
+	var data []byte
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return toml.SilentDecodeFile(path, out, config)
+	}
+
+	if !secrets.IsEncrypted(data) {
+		secrets.Zero(data)
+
+		err = me._checkPermission(path)
+		if err != nil {
+			return err
+		}
+
+		return toml.SilentDecodeFile(path, out, config)
+	}
+
+	// only obtain the key when an encrypted file is found
+	if me.key == nil {
+		me.key, err = Key(false)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %s",
+				libmonteur.ERROR_SECRET_PARSE,
+				err,
+				path,
+			)
+		}
+	}
 
-	me.data, err = s.DecodeMultiPath(me.data, pathings, nil)
+	data, err = ReadFile(path, me.key)
 	if err != nil {
-		err = fmt.Errorf("%s: %s",
+		return err
+	}
+	defer secrets.Zero(data)
+
+	err = toml.DecodeBytes(data, out, config)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
 			libmonteur.ERROR_SECRET_PARSE,
-			err,
+			path,
 		)
 	}
 
-	return err
+	return nil
+}
+
+func (me *Secrets) _checkPermission(path string) (err error) {
+	var info os.FileInfo
+
+	if runtime.GOOS == "windows" {
+		return nil // permission bits are not meaningful
+	}
+
+	info, err = os.Stat(path)
+	if err != nil || info.Mode().Perm()&PERMISSION_MASK == 0 {
+		return nil //nolint:nilerr
+	}
+
+	err = fmt.Errorf("%s (%#o, expecting %#o): %s",
+		libmonteur.ERROR_SECRET_PERMISSION,
+		info.Mode().Perm(),
+		FILE_PERMISSION,
+		path,
+	)
+
+	if me.StrictPermission {
+		return err
+	}
+
+	me.Warn(err)
+
+	return nil
 }
 
+// Filter redacts all known secret values from the given text.
+//
+// The secret values' base64, URL-encoded, and JSON-escaped forms are redacted
+// as well. Values shorter than MinLength are left as it is.
 func (me *Secrets) Filter(s string) string {
-	me.mutex.RLock()
-	defer me.mutex.RUnlock()
+	return me.redactor.String(s)
+}
 
-	for _, v := range me.data {
-		s = strings.ReplaceAll(s,
-			fmt.Sprintf("%v", v),
-			libmonteur.SECRET_REDACTED,
-		)
+// Writer creates a streaming redaction writer into the given output.
+//
+// It is safe for chunked output where a secret value can be split across
+// multiple writes. The writer MUST be closed to flush its held back data.
+func (me *Secrets) Writer(w io.Writer) io.WriteCloser {
+	return me.redactor.NewWriter(w)
+}
+
+// Providers sets the secret providers queried after the secrets files.
+//
+// The providers are queried lazily in the given order only when a key is
+// absent from the secrets files. Their results (including absence) are cached
+// for the rest of the run.
+func (me *Secrets) Providers(list []*libmonteur.TOMLSecretProvider) (
+	err error) {
+	var p Provider
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.providers = []Provider{}
+	for _, cfg := range list {
+		p, err = NewProvider(cfg)
+		if err != nil {
+			return err
+		}
+
+		me.providers = append(me.providers, p)
 	}
 
-	return s
+	return nil
 }
 
 func (me *Secrets) Query(s string) (out interface{}) {
 	var ok bool
 
+	me.mutex.RLock()
+	out, ok = me.data[s]
+	skip := me.missing[s] || len(me.providers) == 0
+	me.mutex.RUnlock()
+
+	me._record(s)
+
+	switch {
+	case ok:
+		return _value(out)
+	case skip:
+		return libmonteur.SECRET_NO_DATA
+	default:
+		return _value(me._queryProviders(s))
+	}
+}
+
+// Has reports whether the key is defined without reading its value.
+//
+// Unlike `Query`, neither the providers are asked nor the key is recorded.
+// Since the providers cannot list their keys, any key is deemed defined once a
+// provider is configured.
+func (me *Secrets) Has(key string) (ok bool) {
+	if me == nil || me.mutex == nil {
+		return false
+	}
+
 	me.mutex.RLock()
 	defer me.mutex.RUnlock()
 
+	_, ok = me.data[key]
+
+	return ok || len(me.providers) > 0
+}
+
+// _value converts the stored value back into its query type.
+func _value(value interface{}) interface{} {
+	if v, ok := value.([]byte); ok {
+		return string(v)
+	}
+
+	return value
+}
+
+func (me *Secrets) _record(key string) {
+	if me.queried == nil {
+		return
+	}
+
+	me.audit.Lock()
+	defer me.audit.Unlock()
+
+	me.queried[key] = true
+}
+
+// Warn records a non-fatal error for the caller to log (see `Warnings`).
+func (me *Secrets) Warn(err error) {
+	me.audit.Lock()
+	defer me.audit.Unlock()
+
+	me.warnings = append(me.warnings, err)
+}
+
+// Warnings drains the non-fatal errors recorded so far.
+//
+// They are the plain secrets file permission warnings from `Parse`, the failed
+// provider queries and the errors given to `Warn`, recorded by the Secrets or
+// session that made them.
+// Since Secrets has no logger of its own, the caller is responsible to log
+// them.
+func (me *Secrets) Warnings() (out []error) {
+	if me == nil || me.audit == nil {
+		return nil
+	}
+
+	me.audit.Lock()
+	defer me.audit.Unlock()
+
+	out = me.warnings
+	me.warnings = nil
+
+	return out
+}
+
+// Session creates a Secrets sharing the same data for recording queries.
+//
+// The session records every queried key (not its value) so that each task can
+// log the secrets it used. See `Queried`. It also records its own `Warnings`.
+func (me *Secrets) Session() *Secrets {
+	s := *me
+	s.queried = map[string]bool{}
+	s.warnings = nil
+	s.audit = &sync.Mutex{}
+
+	return &s
+}
+
+// Queried lists the keys queried through the session in sorted order.
+//
+// It returns an empty list if the Secrets is not created by `Session`.
+func (me *Secrets) Queried() (out []string) {
+	out = []string{}
+
+	if me == nil || me.queried == nil {
+		return out
+	}
+
+	me.audit.Lock()
+	defer me.audit.Unlock()
+
+	for k := range me.queried {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+// Delete zeroes all the stored secret values and removes them from memory.
+//
+// Strings already returned by `Query` are copies and are not covered.
+//
+// It is meant to be called when the workspace is shutting down. All sessions
+// created from the same Secrets are wiped as well.
+func (me *Secrets) Delete() {
+	if me == nil || me.mutex == nil {
+		return
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	_ = secrets.Delete(me.data)
+
+	for k := range me.data {
+		delete(me.data, k)
+	}
+
+	me.redactor.Wipe()
+}
+
+// _queryProviders resolves the key from the providers without holding the
+// lock since a provider may run an external helper for a long time.
+//
+// Concurrent queries of the same key wait for the first one instead of
+// running the providers again.
+func (me *Secrets) _queryProviders(s string) (out interface{}) {
+	var ok, busy bool
+	var done chan struct{}
+
+	me.mutex.Lock()
+
+	// another query may have resolved it while waiting for the lock
 	out, ok = me.data[s]
+	switch {
+	case ok:
+		me.mutex.Unlock()
+		return out
+	case me.missing[s]:
+		me.mutex.Unlock()
+		return libmonteur.SECRET_NO_DATA
+	}
+
+	done, busy = me.pending[s]
+	if !busy {
+		done = make(chan struct{})
+		me.pending[s] = done
+	}
+
+	providers := me.providers
+
+	me.mutex.Unlock()
+
+	if busy {
+		<-done
+
+		me.mutex.RLock()
+		defer me.mutex.RUnlock()
+
+		out, ok = me.data[s]
+		if !ok {
+			return libmonteur.SECRET_NO_DATA
+		}
+
+		return out
+	}
+
+	out, ok = me._resolve(providers, s)
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	delete(me.pending, s)
+	close(done)
+
 	if !ok {
-		out = libmonteur.SECRET_NO_DATA
+		me.missing[s] = true
+		return libmonteur.SECRET_NO_DATA
 	}
 
-	return out
+	me.data[s] = me._store(out)
+
+	return me.data[s]
+}
+
+// _resolve queries the providers in order until one has the key.
+func (me *Secrets) _resolve(providers []Provider,
+	s string) (out interface{}, ok bool) {
+	var err error
+
+	for _, p := range providers {
+		out, ok, err = p.Get(s)
+		if err != nil {
+			// Query cannot fail so record it and try the next one
+			me.Warn(err)
+			continue
+		}
+
+		if ok {
+			return out, true
+		}
+	}
+
+	return nil, false
 }
@@ -0,0 +1,133 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libsecrets
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testQuery,
+			Description: `
+Secrets.Query should resolve the key from the provider when:
+1. the key is absent from the secrets files.
+`,
+			Switches: map[string]bool{
+				expectFound: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testQuery,
+			Description: `
+Secrets.Query should only query the provider once when:
+1. the same key is queried concurrently.
+`,
+			Switches: map[string]bool{
+				useConcurrentQueries: true,
+				expectFound:          true,
+			},
+		}, {
+			UID:      3,
+			TestType: testQuery,
+			Description: `
+Secrets.Query should resolve other keys when:
+1. the provider is still resolving another key.
+`,
+			Switches: map[string]bool{
+				useConcurrentQueries: true,
+				useBlockedProvider:   true,
+				expectFound:          true,
+			},
+		}, {
+			UID:      4,
+			TestType: testQuery,
+			Description: `
+Secrets.Query should record a warning and try the next provider when:
+1. the first provider fails.
+`,
+			Switches: map[string]bool{
+				useFailingProvider: true,
+				expectFound:        true,
+				expectWarning:      true,
+			},
+		}, {
+			UID:      5,
+			TestType: testQuery,
+			Description: `
+Secrets.Query should cache the absence of the key when:
+1. no provider has the key.
+2. the key is queried many times.
+`,
+			Switches: map[string]bool{
+				useMissingKey: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testFile,
+			Description: `
+EncryptFile and DecryptFile should restore the plain file when:
+1. the plain file is a valid TOML document.
+2. the same key is used for both.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      7,
+			TestType: testFile,
+			Description: `
+DecryptFile should fail and keep the file encrypted when:
+1. the file is encrypted by EncryptFile.
+2. a different key is given.
+`,
+			Switches: map[string]bool{
+				useWrongKey:        true,
+				expectDecryptError: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testFile,
+			Description: `
+DecryptFile should fail and keep the file encrypted when:
+1. the file is encrypted by EncryptFile.
+2. a PEM header of the file is altered.
+`,
+			Switches: map[string]bool{
+				useTamperedHeader:  true,
+				expectDecryptError: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testFile,
+			Description: `
+EncryptFile should fail and leave the file untouched when:
+1. the file is already encrypted.
+`,
+			Switches: map[string]bool{
+				useEncryptedFile:   true,
+				expectEncryptError: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testFile,
+			Description: `
+EncryptFile should fail and leave the file untouched when:
+1. the file is not a valid TOML document.
+`,
+			Switches: map[string]bool{
+				useBadTOML:         true,
+				expectEncryptError: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libsecrets
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/secrets"
+)
+
+const (
+	testQuery = "testQuery"
+	testFile  = "testFile"
+)
+
+const (
+	useConcurrentQueries = "useConcurrentQueries"
+	useBlockedProvider   = "useBlockedProvider"
+	useFailingProvider   = "useFailingProvider"
+	useMissingKey        = "useMissingKey"
+	useWrongKey          = "useWrongKey"
+	useTamperedHeader    = "useTamperedHeader"
+	useEncryptedFile     = "useEncryptedFile"
+	useBadTOML           = "useBadTOML"
+
+	expectFound        = "expectFound"
+	expectWarning      = "expectWarning"
+	expectEncryptError = "expectEncryptError"
+	expectDecryptError = "expectDecryptError"
+)
+
+const (
+	testKey      = "Token"
+	testOtherKey = "Other"
+	testValue    = "s3cr3t-value"
+	testQueries  = 8
+	testTimeout  = 5 * time.Second
+	testFileName = "secrets.toml"
+	testPlain    = "[Token]\nValue = \"s3cr3t-value\"\n"
+	testBadTOML  = "[Token\nValue = \"s3cr3t-value\"\n"
+	testFileKey  = "correct horse battery staple"
+	testWrongKey = "incorrect horse battery staple"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// testProvider is the Provider counting its calls. A blocked key waits until
+// the release channel is closed.
+type testProvider struct {
+	mutex   sync.Mutex
+	calls   map[string]int
+	values  map[string]string
+	blocked string
+	release chan struct{}
+	fail    bool
+}
+
+func (p *testProvider) Get(key string) (value interface{}, ok bool,
+	err error) {
+	p.mutex.Lock()
+	p.calls[key]++
+	p.mutex.Unlock()
+
+	if key == p.blocked {
+		<-p.release
+	}
+
+	if p.fail {
+		return nil, false, fmt.Errorf("helper failed for %s", key)
+	}
+
+	v, ok := p.values[key]
+
+	return v, ok, nil
+}
+
+func (s *testScenario) createSecrets(t *testing.T) (subject *Secrets,
+	providers []*testProvider) {
+	subject = &Secrets{}
+
+	err := subject.Parse(nil)
+	if err != nil {
+		t.Fatalf("failed to create test secrets: %s", err)
+	}
+
+	values := map[string]string{
+		testKey:      testValue,
+		testOtherKey: testValue,
+	}
+	if s.Switches[useMissingKey] {
+		values = map[string]string{}
+	}
+
+	p := &testProvider{
+		calls:   map[string]int{},
+		values:  values,
+		release: make(chan struct{}),
+	}
+
+	if s.Switches[useBlockedProvider] {
+		p.blocked = testKey
+	}
+
+	providers = []*testProvider{p}
+
+	if s.Switches[useFailingProvider] {
+		failing := &testProvider{
+			calls:   map[string]int{},
+			release: make(chan struct{}),
+			fail:    true,
+		}
+		providers = []*testProvider{failing, p}
+	}
+
+	for _, p := range providers {
+		subject.providers = append(subject.providers, p)
+	}
+
+	return subject, providers
+}
+
+// query runs the queries of the scenario and returns their results.
+func (s *testScenario) query(subject *Secrets,
+	p *testProvider) (out []interface{}, other interface{}) {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	count := 1
+	if s.Switches[useConcurrentQueries] || s.Switches[useMissingKey] {
+		count = testQueries
+	}
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v := subject.Query(testKey)
+
+			mutex.Lock()
+			out = append(out, v)
+			mutex.Unlock()
+		}()
+	}
+
+	if s.Switches[useBlockedProvider] {
+		// another key resolves while the blocked key is being resolved
+		done := make(chan interface{})
+		go func() {
+			done <- subject.Query(testOtherKey)
+		}()
+
+		select {
+		case other = <-done:
+		case <-time.After(testTimeout):
+		}
+
+		close(p.release)
+	}
+
+	wg.Wait()
+
+	return out, other
+}
+
+func (s *testScenario) assertQuery(th *thelper.THelper,
+	subject *Secrets,
+	providers []*testProvider,
+	out []interface{},
+	other interface{}) {
+	expect := interface{}(libmonteur.SECRET_NO_DATA)
+	if s.Switches[expectFound] {
+		expect = testValue
+	}
+
+	for _, v := range out {
+		th.ExpectSameStrings("value", fmt.Sprintf("%v", v),
+			"expected value", fmt.Sprintf("%v", expect),
+		)
+	}
+
+	p := providers[len(providers)-1]
+	th.ExpectSameBool("single provider call", p.calls[testKey] == 1,
+		"expected single provider call", true,
+	)
+
+	if s.Switches[useBlockedProvider] {
+		th.ExpectSameStrings("other value", fmt.Sprintf("%v", other),
+			"expected other value", testValue,
+		)
+	}
+
+	warnings := subject.Warnings()
+	th.ExpectSameBool("warning", len(warnings) > 0,
+		"expected warning", s.Switches[expectWarning],
+	)
+	th.ExpectSameBool("drained", len(subject.Warnings()) == 0,
+		"expected drained", true,
+	)
+}
+
+// createFile writes the plain secrets file of the scenario into a temporary
+// directory.
+func (s *testScenario) createFile(t *testing.T) (path string) {
+	data := []byte(testPlain)
+	if s.Switches[useBadTOML] {
+		data = []byte(testBadTOML)
+	}
+
+	if s.Switches[useEncryptedFile] {
+		sealed, err := secrets.Encrypt(data, []byte(testFileKey))
+		if err != nil {
+			t.Fatalf("failed to encrypt test file: %s", err)
+		}
+
+		data = sealed
+	}
+
+	path = filepath.Join(t.TempDir(), testFileName)
+
+	err := os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatalf("failed to create test file: %s", err)
+	}
+
+	return path
+}
+
+// tamperFile alters a PEM header of the encrypted secrets file.
+func (s *testScenario) tamperFile(t *testing.T, path string) {
+	if !s.Switches[useTamperedHeader] {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read test file: %s", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("test file is not encrypted")
+	}
+
+	block.Headers[secrets.HEADER_SALT] = strings.Repeat("00",
+		secrets.SALT_SIZE,
+	)
+
+	err = os.WriteFile(path, pem.EncodeToMemory(block), 0600)
+	if err != nil {
+		t.Fatalf("failed to tamper test file: %s", err)
+	}
+}
+
+func (s *testScenario) decryptKey() []byte {
+	if s.Switches[useWrongKey] {
+		return []byte(testWrongKey)
+	}
+
+	return []byte(testFileKey)
+}
+
+func (s *testScenario) assertEncryptFile(th *thelper.THelper,
+	path string,
+	err error) {
+	data, _ := os.ReadFile(path)
+
+	if s.Switches[expectEncryptError] {
+		th.ExpectError(err, true)
+		untouched := string(data) == testBadTOML
+		if s.Switches[useEncryptedFile] {
+			untouched = secrets.IsEncrypted(data)
+		}
+
+		th.ExpectSameBool("file untouched", untouched,
+			"expected file untouched", true,
+		)
+
+		return
+	}
+
+	th.ExpectError(err, false)
+	th.ExpectSameBool("encrypted", secrets.IsEncrypted(data),
+		"expected encrypted", true,
+	)
+	th.ExpectSameBool("armored", !bytes.Contains(data, []byte(testValue)),
+		"expected armored", true,
+	)
+}
+
+func (s *testScenario) assertDecryptFile(th *thelper.THelper,
+	path string,
+	err error) {
+	data, _ := os.ReadFile(path)
+
+	if s.Switches[expectDecryptError] {
+		th.ExpectError(err, true)
+		th.ExpectSameBool("error is "+libmonteur.ERROR_SECRET_DECRYPT,
+			err != nil && strings.HasPrefix(err.Error(),
+				libmonteur.ERROR_SECRET_DECRYPT,
+			),
+			"expected error is "+libmonteur.ERROR_SECRET_DECRYPT, true,
+		)
+		th.ExpectSameBool("still encrypted", secrets.IsEncrypted(data),
+			"expected still encrypted", true,
+		)
+
+		return
+	}
+
+	th.ExpectError(err, false)
+	th.ExpectSameStrings("plain", string(data),
+		"expected plain", testPlain,
+	)
+
+	info, _ := os.Stat(path)
+	th.ExpectSameBool("permission", info != nil &&
+		info.Mode().Perm() == FILE_PERMISSION,
+		"expected permission", true,
+	)
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libsignature
+
+import (
+	"fmt"
+	"os"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/signature"
+)
+
+// Verify checks the file at path against its detached signature data.
+//
+// The signature must be made by one of the sanitized TOMLSignature's trusted
+// keys. It is entirely offline and requires no external program.
+func Verify(sig *libmonteur.TOMLSignature,
+	data []byte,
+	path string) (err error) {
+	var keys, content []byte
+	var f *os.File
+
+	for _, v := range sig.Keys {
+		content, err = os.ReadFile(v)
+		if err != nil {
+			return fmt.Errorf("%s: %s",
+				libmonteur.ERROR_PROGRAM_SIGNATURE_KEY,
+				err,
+			)
+		}
+
+		keys = append(append(keys, content...), '\n')
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_FAILED,
+			err,
+		)
+	}
+	defer f.Close()
+
+	switch sig.Type {
+	case libmonteur.PROGRAM_SIGNATURE_MINISIGN:
+		err = signature.Minisign(keys, data, f)
+	case libmonteur.PROGRAM_SIGNATURE_SSH:
+		namespace := sig.Namespace
+		if namespace == "" {
+			namespace = signature.SSH_NAMESPACE_DEFAULT
+		}
+
+		err = signature.SSH(keys, namespace, data, f)
+	default:
+		return fmt.Errorf("%s: '%s'",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_TYPE_UNKNOWN,
+			sig.Type,
+		)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %s",
+			libmonteur.ERROR_PROGRAM_SIGNATURE_FAILED,
+			err,
+		)
+	}
+
+	return nil
+}
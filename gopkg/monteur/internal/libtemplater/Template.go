@@ -164,3 +164,23 @@ func ToDigitLedVersion(in string) string {
 
 	return in
 }
+
+// Analyze statically parses the given text without executing it.
+//
+// It is used for validating the template before the actual run where all the
+// referenced variables and secret queries can be checked upfront.
+func Analyze(in string) (out *templater.Analysis, err error) {
+	funcMap := map[string]interface{}{
+		"GetSecret": (*libsecrets.Secrets)(nil).Query,
+	}
+
+	out, err = templater.Analyze(in, funcMap)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_PACKAGER_FMT_BAD,
+			err,
+		)
+	}
+
+	return out, nil
+}
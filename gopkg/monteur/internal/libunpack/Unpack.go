@@ -0,0 +1,109 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libunpack
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/bz2"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/gz"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/tarbz2"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/archive/tarxz"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+// TarXZ is to unpack a `.tar.xz` program source.
+func TarXZ(source *libmonteur.TOMLSource,
+	variables map[string]interface{}) (err error) {
+	return _unpack(source, variables, func(path, raw string) archive.Archiver {
+		return &tarxz.Archiver{
+			Archive:         path,
+			Raw:             raw,
+			CreateDirectory: true,
+			Overwrite:       true,
+		}
+	})
+}
+
+// TarBZ2 is to unpack a `.tar.bz2` program source.
+func TarBZ2(source *libmonteur.TOMLSource,
+	variables map[string]interface{}) (err error) {
+	return _unpack(source, variables, func(path, raw string) archive.Archiver {
+		return &tarbz2.Archiver{
+			Archive:         path,
+			Raw:             raw,
+			CreateDirectory: true,
+			Overwrite:       true,
+		}
+	})
+}
+
+// GZ is to unpack a plain `.gz` compressed program source.
+func GZ(source *libmonteur.TOMLSource,
+	variables map[string]interface{}) (err error) {
+	return _unpack(source, variables, func(path, raw string) archive.Archiver {
+		return &gz.Archiver{
+			Archive:         path,
+			Raw:             raw,
+			CreateDirectory: true,
+			Overwrite:       true,
+		}
+	})
+}
+
+// BZ2 is to unpack a plain `.bz2` compressed program source.
+func BZ2(source *libmonteur.TOMLSource,
+	variables map[string]interface{}) (err error) {
+	return _unpack(source, variables, func(path, raw string) archive.Archiver {
+		return &bz2.Archiver{
+			Archive:         path,
+			Raw:             raw,
+			CreateDirectory: true,
+			Overwrite:       true,
+		}
+	})
+}
+
+func _unpack(source *libmonteur.TOMLSource,
+	variables map[string]interface{},
+	create func(path, raw string) archive.Archiver) (err error) {
+	var ok bool
+	var raw, path string
+
+	// extract Raw directory pathing
+	raw, ok = variables[libmonteur.VAR_TMP].(string)
+	if !ok {
+		panic("MONTEUR DEV: why is VAR_TMP not assigned?")
+	}
+
+	// process pathing
+	path = filepath.Join(raw, source.Archive)
+
+	// extract Raw data from Archive
+	err = create(path, raw).Extract()
+	if err != nil {
+		err = fmt.Errorf("%s (%s -> %s): %s",
+			"error unpack ."+source.Format+" program",
+			path,
+			raw,
+			err,
+		)
+	}
+
+	return err
+}
@@ -25,6 +25,7 @@ import (
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/filesystem"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/styler"
 )
 
 type UserPath struct {
@@ -49,6 +50,9 @@ type Pathing struct {
 	LogDir     string
 	DataDir    string
 	ReleaseDir string
+	HistoryDir string
+	StateDir   string
+	LockFile   string
 
 	// workspace Pathing
 	WorkspaceTOMLFile string
@@ -65,6 +69,7 @@ type Pathing struct {
 	SetupTMPDir    string
 	SetupConfigDir string
 	SetupTOMLFile  string
+	SetupLockFile  string
 
 	// sub-directories for publish fx
 	PublishTMPDir    string
@@ -377,6 +382,33 @@ func (fp *Pathing) updateBasePaths(langCode string) (err error) {
 		return err
 	}
 
+	if fp.HistoryDir == "" {
+		fp.HistoryDir = libmonteur.DIRECTORY_HISTORY
+	}
+
+	err = fp._initDependentDir(&fp.HistoryDir, "HistoryDir")
+	if err != nil {
+		return err
+	}
+
+	if fp.StateDir == "" {
+		fp.StateDir = libmonteur.DIRECTORY_STATE
+	}
+
+	err = fp._initDependentDir(&fp.StateDir, "StateDir")
+	if err != nil {
+		return err
+	}
+
+	if fp.LockFile == "" {
+		fp.LockFile = libmonteur.FILE_LOCK
+	}
+
+	err = fp._initDependentDir(&fp.LockFile, "LockFile")
+	if err != nil {
+		return err
+	}
+
 	fp.AppConfigDir = filepath.Join(libmonteur.DIRECTORY_APP_CONFIG,
 		langCode)
 	err = fp._initConfigSubPath(&fp.AppConfigDir, "AppConfigDir")
@@ -411,6 +443,14 @@ func (fp *Pathing) updateSetupPaths() (err error) {
 		return err
 	}
 
+	fp.SetupLockFile = filepath.Join(libmonteur.DIRECTORY_SETUP,
+		libmonteur.FILE_SETUP_LOCK,
+	)
+	err = fp._initConfigSubPath(&fp.SetupLockFile, "SetupLockFile")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -782,3 +822,65 @@ func (fp *Pathing) _initSecretsDir() (err error) {
 func (fp *Pathing) Join(paths ...string) string {
 	return filepath.Join(paths...)
 }
+
+// String is the standard string interface for printing out all Pathing data.
+func (fp *Pathing) String() (s string) {
+	s = styler.PortraitKV("CurrentDir", fp.CurrentDir)
+	s += styler.PortraitKV("RootDir", fp.RootDir)
+	s += styler.PortraitKV("ConfigDir", fp.ConfigDir)
+	s += styler.PortraitKV("BaseDir", fp.BaseDir)
+	s += styler.PortraitKV("WorkingDir", fp.WorkingDir)
+	s += styler.PortraitKV("BuildDir", fp.BuildDir)
+	s += styler.PortraitKV("ScriptDir", fp.ScriptDir)
+	s += styler.PortraitKV("BinDir", fp.BinDir)
+	s += styler.PortraitKV("BinCfgDir", fp.BinCfgDir)
+	s += styler.PortraitKV("LogDir", fp.LogDir)
+	s += styler.PortraitKV("DataDir", fp.DataDir)
+	s += styler.PortraitKV("ReleaseDir", fp.ReleaseDir)
+	s += styler.PortraitKV("HistoryDir", fp.HistoryDir)
+	s += styler.PortraitKV("StateDir", fp.StateDir)
+	s += styler.PortraitKV("LockFile", fp.LockFile)
+	s += styler.PortraitKV("WorkspaceTOMLFile", fp.WorkspaceTOMLFile)
+	s += styler.PortraitKV("WorkspaceLogDir", fp.WorkspaceLogDir)
+	s += styler.PortraitKV("AppConfigDir", fp.AppConfigDir)
+	s += styler.PortraitKV("AppMetaTOMLFile", fp.AppMetaTOMLFile)
+	s += styler.PortraitKV("AppHelpTOMLFile", fp.AppHelpTOMLFile)
+	s += styler.PortraitKV("AppDebianTOMLFile", fp.AppDebianTOMLFile)
+	s += styler.PortraitKV("AppCopyrightsDir", fp.AppCopyrightsDir)
+	s += styler.PortraitKV("SetupTMPDir", fp.SetupTMPDir)
+	s += styler.PortraitKV("SetupConfigDir", fp.SetupConfigDir)
+	s += styler.PortraitKV("SetupTOMLFile", fp.SetupTOMLFile)
+	s += styler.PortraitKV("SetupLockFile", fp.SetupLockFile)
+	s += styler.PortraitKV("PublishTMPDir", fp.PublishTMPDir)
+	s += styler.PortraitKV("PublishConfigDir", fp.PublishConfigDir)
+	s += styler.PortraitKV("PublishTOMLFile", fp.PublishTOMLFile)
+	s += styler.PortraitKV("ComposeTMPDir", fp.ComposeTMPDir)
+	s += styler.PortraitKV("ComposeConfigDir", fp.ComposeConfigDir)
+	s += styler.PortraitKV("ComposeTOMLFile", fp.ComposeTOMLFile)
+	s += styler.PortraitKV("TestTMPDir", fp.TestTMPDir)
+	s += styler.PortraitKV("TestConfigDir", fp.TestConfigDir)
+	s += styler.PortraitKV("TestTOMLFile", fp.TestTOMLFile)
+	s += styler.PortraitKV("PrepareTMPDir", fp.PrepareTMPDir)
+	s += styler.PortraitKV("PrepareConfigDir", fp.PrepareConfigDir)
+	s += styler.PortraitKV("PrepareTOMLFile", fp.PrepareTOMLFile)
+	s += styler.PortraitKV("BuildTMPDir", fp.BuildTMPDir)
+	s += styler.PortraitKV("BuildConfigDir", fp.BuildConfigDir)
+	s += styler.PortraitKV("BuildTOMLFile", fp.BuildTOMLFile)
+	s += styler.PortraitKV("PackageTMPDir", fp.PackageTMPDir)
+	s += styler.PortraitKV("PackageConfigDir", fp.PackageConfigDir)
+	s += styler.PortraitKV("PackageTOMLFile", fp.PackageTOMLFile)
+	s += styler.PortraitKV("ReleaseTMPDir", fp.ReleaseTMPDir)
+	s += styler.PortraitKV("ReleaseConfigDir", fp.ReleaseConfigDir)
+	s += styler.PortraitKV("ReleaseTOMLFile", fp.ReleaseTOMLFile)
+	s += styler.PortraitKV("CleanTMPDir", fp.CleanTMPDir)
+	s += styler.PortraitKV("CleanConfigDir", fp.CleanConfigDir)
+	s += styler.PortraitKV("CleanTOMLFile", fp.CleanTOMLFile)
+
+	if fp.User != nil {
+		s += styler.PortraitKV("User.Home", fp.User.Home)
+	}
+
+	s += styler.PortraitKArray("SecretsDir", fp.SecretsDir)
+
+	return s
+}
@@ -0,0 +1,289 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libworkspace
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+)
+
+type retention struct {
+	maxAge  time.Duration
+	maxSize int64
+	keep    uint
+}
+
+type logRun struct {
+	timestamp time.Time
+	path      string
+	size      int64
+}
+
+// PruneLogs is to enforce the log retention settings on the job's log
+// directory.
+//
+// The oldest runs are removed first until all the `Keep`, `MaxAge` and
+// `MaxSize` settings are satisfied. The current run is never removed and is
+// counted as one of the kept runs. It returns all the removed directories.
+func (me *Workspace) PruneLogs() (removed []string, err error) {
+	var runs []*logRun
+	var total int64
+	var kept uint
+
+	if me.retention == nil {
+		return nil, nil
+	}
+
+	if me.retention.keep == 0 &&
+		me.retention.maxAge == 0 &&
+		me.retention.maxSize == 0 {
+		return nil, nil
+	}
+
+	runs, err = me._listLogRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	kept = 1
+	total = _dirSize(me.Filesystem.WorkspaceLogDir)
+
+	for _, run := range runs {
+		kept++
+		total += run.size
+
+		switch {
+		case me.retention.keep != 0 && kept > me.retention.keep:
+		case me.retention.maxAge != 0 &&
+			me.Timestamp.Sub(run.timestamp) > me.retention.maxAge:
+		case me.retention.maxSize != 0 && total > me.retention.maxSize:
+		default:
+			continue
+		}
+
+		err = os.RemoveAll(run.path)
+		if err != nil {
+			return removed, fmt.Errorf("%s: %s",
+				libmonteur.ERROR_LOG_RETENTION_FAIL,
+				err,
+			)
+		}
+
+		removed = append(removed, run.path)
+		kept--
+		total -= run.size
+	}
+
+	return removed, nil
+}
+
+// LinkLatestLog is to point the job's `latest` symlink to the current run's
+// log directory.
+func (me *Workspace) LinkLatestLog() (err error) {
+	var info os.FileInfo
+
+	path := filepath.Join(filepath.Dir(me.Filesystem.WorkspaceLogDir),
+		libmonteur.LOG_DIRECTORY_LATEST,
+	)
+
+	info, err = os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_LOG_LATEST_FAILED, err)
+	case info.Mode()&os.ModeSymlink == 0:
+		return fmt.Errorf("%s: not a symlink '%s'",
+			libmonteur.ERROR_LOG_LATEST_FAILED,
+			path,
+		)
+	default:
+		_ = os.Remove(path)
+	}
+
+	err = os.Symlink(filepath.Base(me.Filesystem.WorkspaceLogDir), path)
+	if err != nil {
+		return fmt.Errorf("%s: %s", libmonteur.ERROR_LOG_LATEST_FAILED, err)
+	}
+
+	return nil
+}
+
+// _listLogRuns lists all the previous runs from newest to oldest.
+func (me *Workspace) _listLogRuns() (list []*logRun, err error) {
+	var entries []fs.DirEntry
+	var t time.Time
+
+	dir := filepath.Dir(me.Filesystem.WorkspaceLogDir)
+	current := filepath.Base(me.Filesystem.WorkspaceLogDir)
+
+	entries, err = os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s",
+			libmonteur.ERROR_LOG_RETENTION_FAIL,
+			err,
+		)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == current {
+			continue
+		}
+
+		t, err = time.Parse(libmonteur.LOG_DIRECTORY_TIMESTAMP,
+			entry.Name(),
+		)
+		if err != nil {
+			continue
+		}
+
+		list = append(list, &logRun{
+			timestamp: t,
+			path:      filepath.Join(dir, entry.Name()),
+			size:      _dirSize(filepath.Join(dir, entry.Name())),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].timestamp.After(list[j].timestamp)
+	})
+
+	return list, nil
+}
+
+func (me *Workspace) _parseRetention(cfg *libmonteur.TOMLLogs) (err error) {
+	me.retention = &retention{
+		keep: cfg.Keep,
+	}
+
+	me.retention.maxAge, err = _parseAge(cfg.MaxAge)
+	if err != nil {
+		return fmt.Errorf("%s: MaxAge '%s'",
+			libmonteur.ERROR_LOG_RETENTION_BAD,
+			cfg.MaxAge,
+		)
+	}
+
+	me.retention.maxSize, err = _parseSize(cfg.MaxSize)
+	if err != nil {
+		return fmt.Errorf("%s: MaxSize '%s'",
+			libmonteur.ERROR_LOG_RETENTION_BAD,
+			cfg.MaxSize,
+		)
+	}
+
+	return nil
+}
+
+func _dirSize(path string) (size int64) {
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size
+}
+
+// _parseAge parses Go duration (e.g. `72h`) with additional days (e.g. `30d`)
+// unit.
+func _parseAge(s string) (age time.Duration, err error) {
+	var days uint64
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	if !strings.HasSuffix(s, "d") {
+		return time.ParseDuration(s) //nolint:wrapcheck
+	}
+
+	days, err = strconv.ParseUint(strings.TrimSuffix(s, "d"), 10, 16)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// _parseSize parses the size with the following case-insensitive grammar:
+//
+//	size   = number [ " " ] [ unit ]
+//	number = digits [ "." digits ]
+//	unit   = "B" | "KB" | "MB" | "GB" | "TB"
+//
+// Units are binary where `1KB` is `1024` bytes (e.g. `1024`, `500MB`,
+// `1.5GB`). Fractional bytes are truncated and the size must fit in `int64`.
+func _parseSize(s string) (size int64, err error) {
+	var value uint64
+	var fraction float64
+
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	unit := int64(1)
+	for i, suffix := range []string{"TB", "GB", "MB", "KB", "B"} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+
+		s = strings.TrimSpace(strings.TrimSuffix(s, suffix))
+		unit = int64(1) << (10 * (4 - i))
+		break
+	}
+
+	parts := strings.SplitN(s, ".", 2)
+
+	value, err = strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	if len(parts) == 2 {
+		if parts[1] == "" || strings.Trim(parts[1], "0123456789") != "" {
+			return 0, strconv.ErrSyntax
+		}
+
+		fraction, err = strconv.ParseFloat("0."+parts[1], 64)
+		if err != nil {
+			return 0, err //nolint:wrapcheck
+		}
+	}
+
+	if value > uint64(math.MaxInt64/unit) {
+		return 0, strconv.ErrRange
+	}
+
+	size = int64(value)*unit + int64(fraction*float64(unit))
+	if size < 0 {
+		return 0, strconv.ErrRange
+	}
+
+	return size, nil
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/endec/toml"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libcache"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
 	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libtemplater"
@@ -41,6 +42,12 @@ type Workspace struct {
 	App        *libmonteur.Software
 	Variables  *map[string]interface{}
 	Secrets    *libsecrets.Secrets
+	Cache      *libcache.Cache
+	Mirrors    []*libmonteur.TOMLMirror
+	Network    *libmonteur.TOMLNetwork
+
+	secretsConfig *libmonteur.TOMLSecrets
+	retention     *retention
 
 	Job           string
 	Version       string
@@ -64,12 +71,27 @@ func (me *Workspace) Init() error {
 		return err
 	}
 
-	me.processSecrets()
+	if err := me.processSecrets(); err != nil {
+		return err
+	}
+
 	me.processDataByJob()
 
 	return nil
 }
 
+// Close is to shut down the workspace safely.
+//
+// All stored secret values are zeroed and removed from memory. The workspace
+// shall not be used after closing.
+func (me *Workspace) Close() {
+	if me == nil {
+		return
+	}
+
+	me.Secrets.Delete()
+}
+
 func (me *Workspace) parseWorkspaceData() (err error) {
 	me.Language = &libmonteur.Language{}
 	me.Variables = &map[string]interface{}{}
@@ -80,7 +102,9 @@ func (me *Workspace) parseWorkspaceData() (err error) {
 
 	// initialize pathing for parsing
 	me.Filesystem = &Pathing{
-		timestampDir: me.Timestamp.Format("2006-Jan-02T15-04-05UTC"),
+		timestampDir: me.Timestamp.Format(
+			libmonteur.LOG_DIRECTORY_TIMESTAMP,
+		),
 	}
 
 	err = me.Filesystem.Init()
@@ -92,14 +116,29 @@ func (me *Workspace) parseWorkspaceData() (err error) {
 	fmtVar := map[string]interface{}{}
 
 	// parse workspace TOML data
+	me.secretsConfig = &libmonteur.TOMLSecrets{}
+	me.Network = &libmonteur.TOMLNetwork{}
+	logs := &libmonteur.TOMLLogs{}
+	cache := &libmonteur.TOMLCache{}
+
 	s := struct {
 		Language     *libmonteur.Language
 		Filesystem   *Pathing
+		Secrets      *libmonteur.TOMLSecrets
+		Logs         *libmonteur.TOMLLogs
+		Cache        *libmonteur.TOMLCache
+		Mirrors      *[]*libmonteur.TOMLMirror
+		Network      *libmonteur.TOMLNetwork
 		Variables    map[string]interface{}
 		FMTVariables *map[string]interface{}
 	}{
 		Language:     me.Language,
 		Filesystem:   me.Filesystem,
+		Secrets:      me.secretsConfig,
+		Logs:         logs,
+		Cache:        cache,
+		Mirrors:      &me.Mirrors,
+		Network:      me.Network,
 		Variables:    *me.Variables,
 		FMTVariables: &fmtVar,
 	}
@@ -117,6 +156,11 @@ func (me *Workspace) parseWorkspaceData() (err error) {
 		return err //nolint:wrapcheck
 	}
 
+	err = me._parseRetention(logs)
+	if err != nil {
+		return err
+	}
+
 	err = me._sanitizeLanguage()
 	if err != nil {
 		return err
@@ -128,6 +172,11 @@ func (me *Workspace) parseWorkspaceData() (err error) {
 		return err
 	}
 
+	me.Cache, err = libcache.New(cache, me.Filesystem.RootDir)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
 	// init app
 	me.App = &libmonteur.Software{
 		Time: me.__createTimestamp(),
@@ -332,26 +381,43 @@ func (me *Workspace) __createTimestamp() *libmonteur.Timestamp {
 	}
 }
 
-func (me *Workspace) processSecrets() {
-	me.Secrets = &libsecrets.Secrets{}
-	_ = me.Secrets.Parse(me.Filesystem.SecretsDir)
+func (me *Workspace) processSecrets() (err error) {
+	me.Secrets = &libsecrets.Secrets{
+		MinLength:        me.secretsConfig.MinLength,
+		StrictPermission: me.secretsConfig.StrictPermission,
+	}
+
+	// a bad secrets file only fails the tasks querying it so it is
+	// reported as a warning instead of stopping every job
+	err = me.Secrets.Parse(me.Filesystem.SecretsDir)
+	if err != nil {
+		me.Secrets.Warn(err)
+	}
+
+	//nolint:wrapcheck
+	return me.Secrets.Providers(me.secretsConfig.Providers)
 }
 
 func (me *Workspace) processDataByJob() {
 	(*me.Variables)[libmonteur.VAR_OS] = me.OS
 	(*me.Variables)[libmonteur.VAR_ARCH] = me.ARCH
 	(*me.Variables)[libmonteur.VAR_COMPUTE] = me.ComputeSystem
+	(*me.Variables)[libmonteur.VAR_JOB] = me.Job
 	(*me.Variables)[libmonteur.VAR_HOME] = me.Filesystem.CurrentDir
 	(*me.Variables)[libmonteur.VAR_ROOT] = me.Filesystem.RootDir
 	(*me.Variables)[libmonteur.VAR_BASE] = me.Filesystem.BaseDir
 	(*me.Variables)[libmonteur.VAR_CFG] = me.Filesystem.BinCfgDir
 	(*me.Variables)[libmonteur.VAR_BIN] = me.Filesystem.BinDir
 	(*me.Variables)[libmonteur.VAR_BUILD] = me.Filesystem.BuildTMPDir
+	(*me.Variables)[libmonteur.VAR_CACHE] = me.Cache
 	(*me.Variables)[libmonteur.VAR_DOC] = me.Filesystem.ComposeTMPDir
+	(*me.Variables)[libmonteur.VAR_MIRRORS] = me.Mirrors
+	(*me.Variables)[libmonteur.VAR_NETWORK] = me.Network
 	(*me.Variables)[libmonteur.VAR_SECRETS] = me.Secrets
 	(*me.Variables)[libmonteur.VAR_TIMESTAMP] = me.Timestamp
 	(*me.Variables)[libmonteur.VAR_DATA] = me.Filesystem.DataDir
 	(*me.Variables)[libmonteur.VAR_RELEASE] = me.Filesystem.ReleaseDir
+	(*me.Variables)[libmonteur.VAR_STATE] = me.Filesystem.StateDir
 
 	switch me.Job {
 	case libmonteur.JOB_SETUP:
@@ -0,0 +1,354 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libworkspace
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testParseAge,
+			Description: `
+_parseAge should disable the setting without error when:
+1. the age is empty.
+`,
+			Switches: map[string]bool{
+				useEmpty: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testParseAge,
+			Description: `
+_parseAge should parse the age as Go duration when:
+1. the age has no days unit (e.g. '72h').
+`,
+			Switches: map[string]bool{
+				useDuration: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testParseAge,
+			Description: `
+_parseAge should parse the age in days when:
+1. the age has the days unit (e.g. '30d').
+`,
+			Switches: map[string]bool{
+				useDays: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testParseAge,
+			Description: `
+_parseAge should parse the age in days when:
+1. the age is surrounded by spaces (e.g. ' 2d ').
+`,
+			Switches: map[string]bool{
+				useSpaces: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testParseAge,
+			Description: `
+_parseAge should return error when:
+1. the days are negative (e.g. '-1d').
+`,
+			Switches: map[string]bool{
+				useNegative: true,
+				expectError: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testParseAge,
+			Description: `
+_parseAge should return error when:
+1. the days are not a number (e.g. 'xd').
+`,
+			Switches: map[string]bool{
+				useBadNumber: true,
+				expectError:  true,
+			},
+		}, {
+			UID:      7,
+			TestType: testParseAge,
+			Description: `
+_parseAge should return error when:
+1. the days are too large (e.g. '70000d').
+`,
+			Switches: map[string]bool{
+				useOverflow: true,
+				expectError: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testParseAge,
+			Description: `
+_parseAge should return error when:
+1. the age is neither Go duration nor days (e.g. 'bad').
+`,
+			Switches: map[string]bool{
+				expectError: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testParseSize,
+			Description: `
+_parseSize should disable the setting without error when:
+1. the size is empty.
+`,
+			Switches: map[string]bool{
+				useEmpty: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testParseSize,
+			Description: `
+_parseSize should parse the size in bytes beyond 32-bit when:
+1. the size has no unit (e.g. '5000000000').
+`,
+			Switches: map[string]bool{
+				useBytes: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testParseSize,
+			Description: `
+_parseSize should parse the size in binary unit when:
+1. the unit is in lowercase (e.g. '500mb').
+`,
+			Switches: map[string]bool{
+				useUnit: true,
+			},
+		}, {
+			UID:      12,
+			TestType: testParseSize,
+			Description: `
+_parseSize should parse the fractional size when:
+1. the size has a fraction (e.g. '1.5GB').
+`,
+			Switches: map[string]bool{
+				useFraction: true,
+			},
+		}, {
+			UID:      13,
+			TestType: testParseSize,
+			Description: `
+_parseSize should truncate the fractional bytes when:
+1. the size is below a byte (e.g. '0.5B').
+`,
+			Switches: map[string]bool{
+				useTinyFraction: true,
+			},
+		}, {
+			UID:      14,
+			TestType: testParseSize,
+			Description: `
+_parseSize should parse the fractional size when:
+1. the size is spaced from its unit and surroundings (e.g. ' 1.25 KB ').
+`,
+			Switches: map[string]bool{
+				useSpaces: true,
+			},
+		}, {
+			UID:      15,
+			TestType: testParseSize,
+			Description: `
+_parseSize should return error when:
+1. the size is negative (e.g. '-1KB').
+`,
+			Switches: map[string]bool{
+				useNegative: true,
+				expectError: true,
+			},
+		}, {
+			UID:      16,
+			TestType: testParseSize,
+			Description: `
+_parseSize should return error when:
+1. the size has an empty fraction (e.g. '1.KB').
+`,
+			Switches: map[string]bool{
+				useBadFraction: true,
+				expectError:    true,
+			},
+		}, {
+			UID:      17,
+			TestType: testParseSize,
+			Description: `
+_parseSize should return error when:
+1. the size has an exponent (e.g. '1.5e3KB').
+`,
+			Switches: map[string]bool{
+				useExponent: true,
+				expectError: true,
+			},
+		}, {
+			UID:      18,
+			TestType: testParseSize,
+			Description: `
+_parseSize should return error when:
+1. the unit is unknown (e.g. '1PB').
+`,
+			Switches: map[string]bool{
+				useUnknownUnit: true,
+				expectError:    true,
+			},
+		}, {
+			UID:      19,
+			TestType: testParseSize,
+			Description: `
+_parseSize should return error when:
+1. the size overflows int64 (e.g. '8589934592GB').
+`,
+			Switches: map[string]bool{
+				useOverflow: true,
+				expectError: true,
+			},
+		}, {
+			UID:      20,
+			TestType: testParseSize,
+			Description: `
+_parseSize should return error when:
+1. the size is not a number (e.g. 'bad').
+`,
+			Switches: map[string]bool{
+				expectError: true,
+			},
+		}, {
+			UID:      21,
+			TestType: testPruneLogs,
+			Description: `
+PruneLogs should not remove anything when:
+1. no retention setting is given.
+2. the latest symlink and a foreign directory are present.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      22,
+			TestType: testPruneLogs,
+			Description: `
+PruneLogs should remove the oldest runs and keep the latest symlink when:
+1. Keep allows the current and the 2 newest runs.
+2. the latest symlink and a foreign directory are present.
+`,
+			Switches: map[string]bool{
+				useKeep: true,
+			},
+		}, {
+			UID:      23,
+			TestType: testPruneLogs,
+			Description: `
+PruneLogs should remove the oldest runs and keep the latest symlink when:
+1. MaxAge only allows the 2 newest runs.
+2. the latest symlink and a foreign directory are present.
+`,
+			Switches: map[string]bool{
+				useMaxAge: true,
+			},
+		}, {
+			UID:      24,
+			TestType: testPruneLogs,
+			Description: `
+PruneLogs should remove the oldest runs and keep the latest symlink when:
+1. MaxSize only fits the current and the 2 newest runs.
+2. the latest symlink and a foreign directory are present.
+`,
+			Switches: map[string]bool{
+				useMaxSize: true,
+			},
+		}, {
+			UID:      25,
+			TestType: testPruneLogs,
+			Description: `
+PruneLogs should remove the oldest runs only once when:
+1. Keep, MaxAge and MaxSize all remove the same runs.
+2. the latest symlink and a foreign directory are present.
+`,
+			Switches: map[string]bool{
+				useKeep:    true,
+				useMaxAge:  true,
+				useMaxSize: true,
+			},
+		}, {
+			UID:      26,
+			TestType: testPathing,
+			Description: `
+Pathing.String should list every path when:
+1. all paths are given.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      27,
+			TestType: testPathing,
+			Description: `
+Pathing.String should list every path as '' when:
+1. no path is given.
+`,
+			Switches: map[string]bool{
+				useEmptyPathing: true,
+			},
+		}, {
+			UID:      28,
+			TestType: testPathing,
+			Description: `
+Pathing.String should list the user home when:
+1. all paths are given.
+2. the user path is given.
+`,
+			Switches: map[string]bool{
+				useUserPath: true,
+			},
+		}, {
+			UID:      29,
+			TestType: testPathing,
+			Description: `
+Pathing.String should list the secrets directories when:
+1. all paths are given.
+2. a secrets directory is given.
+`,
+			Switches: map[string]bool{
+				useSecretsDir: true,
+			},
+		}, {
+			UID:      30,
+			TestType: testSecrets,
+			Description: `
+Workspace.processSecrets should load the secrets without warnings when:
+1. the secrets file is valid.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      31,
+			TestType: testSecrets,
+			Description: `
+Workspace.processSecrets should only warn about the secrets file when:
+1. the encrypted secrets file cannot be decrypted with the given key.
+`,
+			Switches: map[string]bool{
+				useWrongSecretsKey: true,
+			},
+		}, {
+			UID:      32,
+			TestType: testSecrets,
+			Description: `
+Workspace.processSecrets should only warn about the secrets file when:
+1. the secrets file is accessible by group or others.
+2. StrictPermission is enabled.
+`,
+			Switches: map[string]bool{
+				useStrictPermission: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libworkspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+)
+
+const (
+	testParseAge  = "testParseAge"
+	testParseSize = "testParseSize"
+	testPruneLogs = "testPruneLogs"
+	testPathing   = "testPathing"
+	testSecrets   = "testSecrets"
+)
+
+const (
+	useEmpty        = "useEmpty"
+	useDuration     = "useDuration"
+	useDays         = "useDays"
+	useBytes        = "useBytes"
+	useUnit         = "useUnit"
+	useFraction     = "useFraction"
+	useTinyFraction = "useTinyFraction"
+	useSpaces       = "useSpaces"
+	useNegative     = "useNegative"
+	useBadNumber    = "useBadNumber"
+	useBadFraction  = "useBadFraction"
+	useExponent     = "useExponent"
+	useUnknownUnit  = "useUnknownUnit"
+	useOverflow     = "useOverflow"
+
+	useKeep    = "useKeep"
+	useMaxAge  = "useMaxAge"
+	useMaxSize = "useMaxSize"
+
+	useEmptyPathing = "useEmptyPathing"
+	useUserPath     = "useUserPath"
+	useSecretsDir   = "useSecretsDir"
+
+	useWrongSecretsKey  = "useWrongSecretsKey"
+	useStrictPermission = "useStrictPermission"
+
+	expectError = "expectError"
+)
+
+const (
+	logJob     = "build"
+	logSize    = 1024
+	logRuns    = 4
+	logRemoved = 2
+	logForeign = "notes"
+)
+
+const (
+	secretsKey   = "Token"
+	secretsValue = "abcdefgh"
+	secretsSeal  = "monteur"
+)
+
+const (
+	pathingRoot    = "/monteur/"
+	pathingHome    = "/home/monteur"
+	pathingSecrets = "/monteur/.configs/secrets"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createAge is to get the age setting alongside its expected duration.
+func (s *testScenario) createAge() (age string, expect time.Duration) {
+	switch {
+	case s.Switches[useEmpty]:
+		return "", 0
+	case s.Switches[useDuration]:
+		return "72h", 72 * time.Hour
+	case s.Switches[useDays]:
+		return "30d", 30 * 24 * time.Hour
+	case s.Switches[useSpaces]:
+		return " 2d ", 2 * 24 * time.Hour
+	case s.Switches[useNegative]:
+		return "-1d", 0
+	case s.Switches[useBadNumber]:
+		return "xd", 0
+	case s.Switches[useOverflow]:
+		return "70000d", 0
+	}
+
+	return "bad", 0
+}
+
+// createSize is to get the size setting alongside its expected bytes.
+func (s *testScenario) createSize() (size string, expect int64) {
+	switch {
+	case s.Switches[useEmpty]:
+		return "", 0
+	case s.Switches[useBytes]:
+		return "5000000000", 5000000000
+	case s.Switches[useUnit]:
+		return "500mb", 500 << 20
+	case s.Switches[useFraction]:
+		return "1.5GB", 3 << 29
+	case s.Switches[useTinyFraction]:
+		return "0.5B", 0
+	case s.Switches[useSpaces]:
+		return " 1.25 KB ", 1280
+	case s.Switches[useNegative]:
+		return "-1KB", 0
+	case s.Switches[useBadFraction]:
+		return "1.KB", 0
+	case s.Switches[useExponent]:
+		return "1.5e3KB", 0
+	case s.Switches[useUnknownUnit]:
+		return "1PB", 0
+	case s.Switches[useOverflow]:
+		return "8589934592GB", 0
+	}
+
+	return "bad", 0
+}
+
+func (s *testScenario) assertParseAge(th *thelper.THelper,
+	age time.Duration,
+	expect time.Duration,
+	err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	th.ExpectSameStrings("age", age.String(),
+		"expected age", expect.String(),
+	)
+}
+
+func (s *testScenario) assertParseSize(th *thelper.THelper,
+	size int64,
+	expect int64,
+	err error) {
+	th.ExpectError(err, s.Switches[expectError])
+	th.ExpectSameBool("size", size == expect,
+		"expected size", true,
+	)
+}
+
+// createWorkspace creates the job's log directory with the current run, the
+// previous runs spaced an hour apart, a foreign directory and the `latest`
+// symlink pointing to the current run.
+func (s *testScenario) createWorkspace(t *testing.T) (w *Workspace,
+	runs []string) {
+	now := time.Date(2022, time.January, 2, 3, 4, 5, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), logJob)
+
+	w = &Workspace{
+		Timestamp: &now,
+		Filesystem: &Pathing{
+			WorkspaceLogDir: filepath.Join(dir,
+				now.Format(libmonteur.LOG_DIRECTORY_TIMESTAMP),
+			),
+		},
+	}
+
+	for i := 1; i <= logRuns; i++ {
+		runs = append(runs, filepath.Join(dir,
+			now.Add(-time.Duration(i)*time.Hour).Format(
+				libmonteur.LOG_DIRECTORY_TIMESTAMP,
+			),
+		))
+	}
+
+	for _, path := range append([]string{
+		w.Filesystem.WorkspaceLogDir,
+		filepath.Join(dir, logForeign),
+	}, runs...) {
+		err := os.MkdirAll(path, 0755)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(path, "run.log"),
+				make([]byte, logSize),
+				0644,
+			)
+		}
+
+		if err != nil {
+			t.Fatalf("failed to create test logs: %s", err)
+		}
+	}
+
+	err := w.LinkLatestLog()
+	if err == nil {
+		err = w._parseRetention(s.createRetention())
+	}
+
+	if err != nil {
+		t.Fatalf("failed to prepare test workspace: %s", err)
+	}
+
+	return w, runs
+}
+
+// createRetention is to get the settings that remove the oldest runs.
+func (s *testScenario) createRetention() *libmonteur.TOMLLogs {
+	logs := &libmonteur.TOMLLogs{}
+
+	if s.Switches[useKeep] {
+		logs.Keep = logRuns - logRemoved + 1
+	}
+
+	if s.Switches[useMaxAge] {
+		logs.MaxAge = "150m"
+	}
+
+	if s.Switches[useMaxSize] {
+		logs.MaxSize = "3KB"
+	}
+
+	return logs
+}
+
+func (s *testScenario) assertPruneLogs(th *thelper.THelper,
+	w *Workspace,
+	runs []string,
+	removed []string,
+	err error) {
+	var expect []string
+
+	if s.Switches[useKeep] || s.Switches[useMaxAge] ||
+		s.Switches[useMaxSize] {
+		expect = runs[logRuns-logRemoved:]
+	}
+
+	th.ExpectError(err, false)
+	th.ExpectSameBool("removed count", len(removed) == len(expect),
+		"expected removed count", true,
+	)
+
+	gone := map[string]bool{}
+	for _, path := range removed {
+		gone[path] = true
+	}
+
+	for i, path := range runs {
+		_, statErr := os.Stat(path)
+		th.ExpectSameBool("kept "+filepath.Base(path), statErr == nil,
+			"expected kept "+filepath.Base(path),
+			i < logRuns-len(expect),
+		)
+		th.ExpectSameBool("reported "+filepath.Base(path), gone[path],
+			"expected reported "+filepath.Base(path),
+			i >= logRuns-len(expect),
+		)
+	}
+
+	dir := filepath.Dir(w.Filesystem.WorkspaceLogDir)
+
+	_, err = os.Stat(filepath.Join(dir, logForeign))
+	th.ExpectError(err, false)
+
+	_, err = os.Stat(w.Filesystem.WorkspaceLogDir)
+	th.ExpectError(err, false)
+
+	target, err := os.Readlink(filepath.Join(dir,
+		libmonteur.LOG_DIRECTORY_LATEST,
+	))
+	th.ExpectError(err, false)
+	th.ExpectSameStrings("latest", target,
+		"expected latest", filepath.Base(w.Filesystem.WorkspaceLogDir),
+	)
+}
+
+// createPathing is to get the Pathing where every path field holds its own
+// name unless the scenario asks for an empty one.
+func (s *testScenario) createPathing() (fp *Pathing) {
+	fp = &Pathing{}
+
+	if !s.Switches[useEmptyPathing] {
+		v := reflect.ValueOf(fp).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !_isPath(field) {
+				continue
+			}
+
+			v.Field(i).SetString(pathingRoot + field.Name)
+		}
+	}
+
+	if s.Switches[useUserPath] {
+		fp.User = &UserPath{Home: pathingHome}
+	}
+
+	if s.Switches[useSecretsDir] {
+		fp.SecretsDir = []string{pathingSecrets}
+	}
+
+	return fp
+}
+
+func (s *testScenario) assertPathing(th *thelper.THelper,
+	fp *Pathing,
+	out string) {
+	missing := []string{}
+
+	v := reflect.ValueOf(fp).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !_isPath(field) {
+			continue
+		}
+
+		value := v.Field(i).String()
+		if value == "" {
+			value = "''"
+		}
+
+		if !strings.Contains(out, strings.ToUpper(field.Name)+"\n"+
+			value+"\n\n") {
+			missing = append(missing, field.Name)
+		}
+	}
+
+	th.ExpectSameStrings("missing paths", strings.Join(missing, ", "),
+		"expected missing paths", "",
+	)
+	th.ExpectSameBool("user home", strings.Contains(out,
+		"USER.HOME\n"+pathingHome+"\n\n",
+	), "expected user home", s.Switches[useUserPath])
+	th.ExpectSameBool("secrets dir", strings.Contains(out,
+		"SECRETSDIR\n(1): "+pathingSecrets+"\n\n",
+	), "expected secrets dir", s.Switches[useSecretsDir])
+}
+
+// _isPath checks the Pathing field is an exported path.
+func _isPath(field reflect.StructField) bool {
+	return field.PkgPath == "" && field.Type.Kind() == reflect.String
+}
+
+// createSecrets creates the workspace with a secrets file in its secrets
+// directory.
+func (s *testScenario) createSecrets(t *testing.T) *Workspace {
+	var err error
+
+	mode := os.FileMode(0600)
+	data := []byte(secretsKey + " = \"" + secretsValue + "\"\n")
+
+	switch {
+	case s.Switches[useWrongSecretsKey]:
+		t.Setenv(libmonteur.ENV_SECRETS_KEY, "wrong")
+
+		data, err = libsecrets.Seal(data, []byte(secretsSeal))
+		if err != nil {
+			t.Fatalf("failed to encrypt test secrets file: %s", err)
+		}
+	case s.Switches[useStrictPermission]:
+		mode = 0644
+	}
+
+	path := filepath.Join(t.TempDir(), "secrets.toml")
+
+	err = os.WriteFile(path, data, mode)
+	if err == nil {
+		err = os.Chmod(path, mode)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to create test secrets file: %s", err)
+	}
+
+	return &Workspace{
+		Filesystem: &Pathing{SecretsDir: []string{path}},
+		secretsConfig: &libmonteur.TOMLSecrets{
+			StrictPermission: s.Switches[useStrictPermission],
+		},
+	}
+}
+
+func (s *testScenario) assertSecrets(th *thelper.THelper,
+	w *Workspace,
+	warnings []error,
+	err error) {
+	th.ExpectError(err, false)
+
+	failed := s.Switches[useWrongSecretsKey] ||
+		s.Switches[useStrictPermission]
+
+	th.ExpectSameBool("warned", len(warnings) == 1,
+		"expected warned", failed,
+	)
+
+	th.ExpectSameBool("loaded", w.Secrets.Has(secretsKey),
+		"expected loaded", !failed,
+	)
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libworkspace
+
+import (
+	"testing"
+)
+
+func TestParseAge(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testParseAge {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		input, expect := s.createAge()
+
+		// test
+		age, err := _parseAge(input)
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertParseAge(th, age, expect, err)
+		s.log(th, map[string]interface{}{
+			"input": input,
+			"age":   age,
+			"error": err,
+		})
+		th.Conclude()
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redactor replaces sensitive values from texts and output streams.
+//
+// Each added value is expanded into its commonly leaked encoded forms (base64,
+// URL-encoded, and JSON-escaped) so that the same secret cannot escape through
+// a different representation. Values shorter than the minimum length are
+// ignored to avoid redacting trivial words like `true` or `1` everywhere.
+package redactor
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// Redactor holds the list of sensitive values and replaces them.
+//
+// Redactor must be created using `New` and is safe for concurrent use.
+type Redactor struct {
+	patterns    map[byte][][]byte
+	count       int
+	replacement []byte
+	min         int
+	mutex       *sync.RWMutex
+}
+
+// New creates a Redactor with the given minimum length and replacement text.
+//
+// If `min` is `0`, DEFAULT_MIN_LENGTH is used.
+func New(min int, replacement string) *Redactor {
+	if min <= 0 {
+		min = DEFAULT_MIN_LENGTH
+	}
+
+	return &Redactor{
+		patterns:    map[byte][][]byte{},
+		replacement: []byte(replacement),
+		min:         min,
+		mutex:       &sync.RWMutex{},
+	}
+}
+
+// Add registers a sensitive value together with its encoded variants.
+//
+// Values (and variants) shorter than the minimum length are ignored.
+func (me *Redactor) Add(value string) {
+	if len(value) < me.min {
+		return
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for _, v := range variants(value) {
+		me._add(v)
+	}
+}
+
+func (me *Redactor) _add(value string) {
+	if len(value) < me.min {
+		return
+	}
+
+	for _, p := range me.patterns[value[0]] {
+		if string(p) == value {
+			return
+		}
+	}
+
+	me.count++
+
+	list := append(me.patterns[value[0]], []byte(value))
+	sort.SliceStable(list, func(i, j int) bool {
+		return len(list[i]) > len(list[j])
+	})
+	me.patterns[value[0]] = list
+}
+
+// Wipe zeroes and removes all the registered sensitive values.
+func (me *Redactor) Wipe() {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for k, list := range me.patterns {
+		for _, p := range list {
+			for i := range p {
+				p[i] = 0x00
+			}
+		}
+
+		delete(me.patterns, k)
+	}
+
+	me.count = 0
+}
+
+// String replaces all the sensitive values inside the given text.
+func (me *Redactor) String(s string) string {
+	var out bytes.Buffer
+
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+
+	if me.count == 0 {
+		return s
+	}
+
+	me.redact(&out, []byte(s), true)
+
+	return out.String()
+}
+
+// redact writes the redacted `data` into `out` and returns the consumed length.
+//
+// When `final` is `false`, the scan stops right before a position where a
+// sensitive value may continue in the next chunk so that the remaining bytes
+// can be held back by the caller.
+func (me *Redactor) redact(out *bytes.Buffer, data []byte, final bool) int {
+	var i, start int
+
+	for i < len(data) {
+		match, partial := me._matchAt(data[i:])
+
+		switch {
+		case partial && !final:
+			out.Write(data[start:i])
+			return i
+		case match > 0:
+			out.Write(data[start:i])
+			out.Write(me.replacement)
+			i += match
+			start = i
+		default:
+			i++
+		}
+	}
+
+	out.Write(data[start:])
+
+	return len(data)
+}
+
+// _matchAt returns the longest sensitive value length matching at the start of
+// `data` and whether a longer value may still match with more data.
+func (me *Redactor) _matchAt(data []byte) (match int, partial bool) {
+	for _, p := range me.patterns[data[0]] {
+		switch {
+		case len(p) > len(data):
+			if !partial && bytes.HasPrefix(p, data) {
+				partial = true
+			}
+		case match == 0 && bytes.HasPrefix(data, p):
+			match = len(p)
+		}
+	}
+
+	return match, partial
+}
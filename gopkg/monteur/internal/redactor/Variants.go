@@ -0,0 +1,80 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// variants generates the commonly leaked representations of a value.
+func variants(value string) (out []string) {
+	out = []string{value}
+
+	out = append(out, _base64Variants(value)...)
+	out = append(out, url.QueryEscape(value), url.PathEscape(value))
+	out = append(out, _jsonVariants(value)...)
+
+	return out
+}
+
+// _base64Variants encodes the value at all 3 possible byte alignments.
+//
+// A value embedded in a larger base64 payload (e.g. the password part of a
+// HTTP Basic `user:password` credential) only shares the characters fully
+// determined by its own bits. Hence, the partially determined characters at
+// both ends are dropped.
+func _base64Variants(value string) (out []string) {
+	for shift := 0; shift < 3; shift++ {
+		data := append(make([]byte, shift), value...)
+		s := base64.RawStdEncoding.EncodeToString(data)
+
+		start := (8*shift + 5) / 6
+		end := 8 * len(data) / 6
+		if start >= end {
+			continue
+		}
+
+		s = s[start:end]
+		out = append(out, s, strings.NewReplacer("+", "-", "/", "_").
+			Replace(s),
+		)
+	}
+
+	return out
+}
+
+// _jsonVariants escapes the value as a JSON string without its quotes.
+func _jsonVariants(value string) (out []string) {
+	var b bytes.Buffer
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		out = append(out, string(data[1:len(data)-1]))
+	}
+
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if encoder.Encode(value) == nil {
+		data = bytes.TrimSpace(b.Bytes())
+		out = append(out, string(data[1:len(data)-1]))
+	}
+
+	return out
+}
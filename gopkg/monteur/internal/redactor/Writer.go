@@ -0,0 +1,89 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactor
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Writer is the streaming redaction io.WriteCloser.
+//
+// A sensitive value can be split across multiple `Write` calls (e.g. chunked
+// command output). Hence, Writer holds back the trailing bytes that may be the
+// beginning of a sensitive value until the next `Write` resolves it. `Close`
+// MUST be called to flush the held back bytes.
+type Writer struct {
+	redactor *Redactor
+	output   io.Writer
+	pending  []byte
+	mutex    *sync.Mutex
+}
+
+// NewWriter creates a streaming Writer redacting into the given output.
+func (me *Redactor) NewWriter(output io.Writer) *Writer {
+	return &Writer{
+		redactor: me,
+		output:   output,
+		mutex:    &sync.Mutex{},
+	}
+}
+
+// Write redacts the given data and writes the resolved part into the output.
+//
+// It always reports the full length of `p` as written unless the output fails.
+func (me *Writer) Write(p []byte) (n int, err error) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.pending = append(me.pending, p...)
+
+	err = me.flush(false)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close flushes all held back bytes into the output.
+//
+// It does not close the output.
+func (me *Writer) Close() (err error) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	return me.flush(true)
+}
+
+func (me *Writer) flush(final bool) (err error) {
+	var out bytes.Buffer
+
+	me.redactor.mutex.RLock()
+	n := me.redactor.redact(&out, me.pending, final)
+	me.redactor.mutex.RUnlock()
+
+	me.pending = append(me.pending[:0], me.pending[n:]...)
+
+	if out.Len() == 0 {
+		return nil
+	}
+
+	_, err = me.output.Write(out.Bytes())
+
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactor
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testString,
+			Description: `
+redactor.String should redact the raw secret when:
+1. the secret is added.
+2. the raw form of secret is in the text.
+`,
+			Switches: map[string]bool{
+				useRawForm: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testString,
+			Description: `
+redactor.String should redact the base64 encoded secret when:
+1. the secret is added.
+2. the base64 form of secret is in the text.
+`,
+			Switches: map[string]bool{
+				useBase64Form: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testString,
+			Description: `
+redactor.String should redact the base64 encoded secret when:
+1. the secret is added.
+2. the secret is encoded as HTTP Basic credential with a username.
+`,
+			Switches: map[string]bool{
+				useBasicAuthForm: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testString,
+			Description: `
+redactor.String should redact the URL encoded secret when:
+1. the secret is added.
+2. the URL query encoded form of secret is in the text.
+`,
+			Switches: map[string]bool{
+				useURLForm: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testString,
+			Description: `
+redactor.String should redact the JSON escaped secret when:
+1. the secret is added.
+2. the JSON escaped form of secret is in the text.
+`,
+			Switches: map[string]bool{
+				useJSONForm: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testString,
+			Description: `
+redactor.String should not redact anything when:
+1. the secret is shorter than the minimum length.
+2. the raw form of secret is in the text.
+`,
+			Switches: map[string]bool{
+				useShortSecret: true,
+				useRawForm:     true,
+			},
+		}, {
+			UID:      7,
+			TestType: testString,
+			Description: `
+redactor.String should not modify anything when:
+1. no secret is added.
+`,
+			Switches: map[string]bool{
+				useNoSecret: true,
+				useRawForm:  true,
+			},
+		}, {
+			UID:      8,
+			TestType: testWriter,
+			Description: `
+redactor.Writer should redact the raw secret when:
+1. the secret is added.
+2. the raw form of secret is in the stream.
+3. the stream is written in a single chunk.
+`,
+			Switches: map[string]bool{
+				useRawForm:     true,
+				useSingleChunk: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testWriter,
+			Description: `
+redactor.Writer should redact the raw secret when:
+1. the secret is added.
+2. the raw form of secret is in the stream.
+3. the stream is written byte by byte.
+`,
+			Switches: map[string]bool{
+				useRawForm:    true,
+				useByteChunks: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testWriter,
+			Description: `
+redactor.Writer should redact the base64 encoded secret when:
+1. the secret is added.
+2. the secret is encoded as HTTP Basic credential with a username.
+3. the stream is written byte by byte.
+`,
+			Switches: map[string]bool{
+				useBasicAuthForm: true,
+				useByteChunks:    true,
+			},
+		}, {
+			UID:      11,
+			TestType: testWriter,
+			Description: `
+redactor.Writer should not modify anything when:
+1. the secret is shorter than the minimum length.
+2. the raw form of secret is in the stream.
+3. the stream is written byte by byte.
+`,
+			Switches: map[string]bool{
+				useShortSecret: true,
+				useRawForm:     true,
+				useByteChunks:  true,
+			},
+		},
+	}
+}
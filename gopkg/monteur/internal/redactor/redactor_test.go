@@ -0,0 +1,150 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+)
+
+const (
+	testString = "testString"
+	testWriter = "testWriter"
+)
+
+const (
+	useShortSecret = "useShortSecret"
+	useNoSecret    = "useNoSecret"
+
+	useRawForm       = "useRawForm"
+	useBase64Form    = "useBase64Form"
+	useBasicAuthForm = "useBasicAuthForm"
+	useURLForm       = "useURLForm"
+	useJSONForm      = "useJSONForm"
+
+	useSingleChunk = "useSingleChunk"
+	useByteChunks  = "useByteChunks"
+)
+
+const (
+	replacement = "<REDACTED>"
+	secret      = `s3cr&t/"p@ss+w0rd`
+	shortSecret = "true"
+	username    = "monteur"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createRedactor() *Redactor {
+	r := New(0, replacement)
+
+	switch {
+	case s.Switches[useNoSecret]:
+	case s.Switches[useShortSecret]:
+		r.Add(shortSecret)
+	default:
+		r.Add(secret)
+	}
+
+	return r
+}
+
+func (s *testScenario) createLeak() (leak string) {
+	value := secret
+	if s.Switches[useShortSecret] {
+		value = shortSecret
+	}
+
+	switch {
+	case s.Switches[useBase64Form]:
+		leak = base64.StdEncoding.EncodeToString([]byte(value))
+	case s.Switches[useBasicAuthForm]:
+		leak = base64.StdEncoding.EncodeToString(
+			[]byte(username + ":" + value),
+		)
+	case s.Switches[useURLForm]:
+		leak = url.QueryEscape(value)
+	case s.Switches[useJSONForm]:
+		leak = strings.NewReplacer(`"`, `\"`, "&", `\u0026`).
+			Replace(value)
+	default:
+		leak = value
+	}
+
+	return leak
+}
+
+func (s *testScenario) createInput(leak string) string {
+	return "token: " + leak + " and again " + leak + "\n"
+}
+
+func (s *testScenario) writeChunks(w *Writer, input string) (err error) {
+	if !s.Switches[useByteChunks] {
+		_, err = w.Write([]byte(input))
+		return err
+	}
+
+	for i := range input {
+		_, err = w.Write([]byte{input[i]})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *testScenario) assertOutput(th *thelper.THelper,
+	output string, input string, leak string) {
+	if s.Switches[useShortSecret] || s.Switches[useNoSecret] {
+		th.ExpectSameStrings("output", output, "input", input)
+		return
+	}
+
+	th.ExpectStringHasKeywords("output", output,
+		"replacement", replacement,
+	)
+
+	// only the characters partially determined by the secret may remain
+	if s.Switches[useBasicAuthForm] || s.Switches[useBase64Form] {
+		leak = leak[4 : len(leak)-4]
+	}
+
+	if strings.Contains(output, leak) {
+		th.Errorf("output still has the secret: %s", output)
+	}
+}
+
+func (s *testScenario) assertFlushed(th *thelper.THelper,
+	output *bytes.Buffer) {
+	if !strings.HasSuffix(output.String(), "\n") {
+		th.Errorf("output is not fully flushed: %q", output.String())
+	}
+}
@@ -22,6 +22,8 @@ const (
 	ERROR_DATAPATH_INVALID = "given DataPath is invalid"
 	ERROR_DATAPATH_CREATE  = "error creating DataPath"
 
+	ERROR_FILTER_MISSING = "given Filter is missing"
+
 	ERROR_FORMAT_UNSUPPORTED = "given Format is unsupported"
 
 	ERROR_PATH         = "error with Path"
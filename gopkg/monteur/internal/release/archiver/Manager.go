@@ -43,6 +43,14 @@ type Manager struct {
 	// If this is unassigned, the Manager will operate silently.
 	Log Logger
 
+	// Filter is the function to sanitize the release Jobs' messages.
+	//
+	// It is handed to the conductor for redacting sensitive data from the
+	// Jobs' status, output, and error messages.
+	//
+	// This field is **MANDATORY**.
+	Filter func(string) string
+
 	// Path is the output directory pathing.
 	//
 	// If path does not exists, Manager shall create it on-behalf.
@@ -87,6 +95,10 @@ func (me *Manager) Sanitize() (err error) {
 		return fmt.Errorf(ERROR_VERSION_MISSING)
 	}
 
+	if me.Filter == nil {
+		return fmt.Errorf(ERROR_FILTER_MISSING)
+	}
+
 	err = me.sanitizePath()
 	if err != nil {
 		return err
@@ -278,6 +290,7 @@ func (me *Manager) Release() (err error) {
 	c = &conductor.Conductor{
 		Runners: me.jobs,
 		Log:     logger,
+		Filter:  me.Filter,
 	}
 
 	// orchestrate
@@ -28,6 +28,13 @@ func Delete(data map[string]interface{}) (err error) {
 	return nil
 }
 
+// Zero overwrites the given byte slice with zeros.
+func Zero(data []byte) {
+	for i := range data {
+		data[i] = 0x00
+	}
+}
+
 func zeroClean(key string, value interface{}, data map[string]interface{}) {
 	switch ivalue := value.(type) {
 	case []interface{}:
@@ -19,3 +19,41 @@ const (
 	// QUERY_CONNECTOR is the connection character between keys.
 	QUERY_CONNECTOR = "."
 )
+
+const (
+	// ENCRYPTED_TYPE is the PEM block type of an encrypted secrets file.
+	ENCRYPTED_TYPE = "MONTEUR SECRETS"
+
+	// CIPHER_AES256GCM is the only supported encryption cipher.
+	CIPHER_AES256GCM = "AES-256-GCM"
+
+	// KDF_SCRYPT is the only supported passphrase key derivation function.
+	KDF_SCRYPT = "scrypt"
+)
+
+// Encrypted secrets file PEM headers
+const (
+	HEADER_CIPHER = "Cipher"
+	HEADER_KDF    = "KDF"
+	HEADER_N      = "KDF-N"
+	HEADER_R      = "KDF-R"
+	HEADER_P      = "KDF-P"
+	HEADER_SALT   = "Salt"
+)
+
+// Encryption parameters for new encrypted secrets file
+const (
+	SCRYPT_N    = 32768
+	SCRYPT_R    = 8
+	SCRYPT_P    = 1
+	KEY_SIZE    = 32
+	SALT_SIZE   = 16
+	SCRYPT_MAXN = 1 << 20
+)
+
+// Limits of the scrypt parameters accepted from an encrypted secrets file
+const (
+	SCRYPT_MAXR  = 32
+	SCRYPT_MAXP  = 16
+	SCRYPT_MAXRP = 64
+)
@@ -0,0 +1,229 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// IsEncrypted checks the given data is an encrypted secrets document.
+func IsEncrypted(data []byte) bool {
+	block, _ := pem.Decode(bytes.TrimLeft(data, " \t\r\n"))
+
+	return block != nil && block.Type == ENCRYPTED_TYPE
+}
+
+// Encrypt seals the given plain data with the given passphrase.
+//
+// The data is encrypted using AES-256-GCM where its key is derived from the
+// passphrase using scrypt with a random salt. The output is a PEM armored
+// text document so that it is safe to commit into a git repository.
+//
+// The PEM headers are authenticated together with the data so any changes to
+// them fail the decryption.
+func Encrypt(plain []byte, passphrase []byte) (out []byte, err error) {
+	var key, salt, nonce []byte
+	var headers map[string]string
+	var aead cipher.AEAD
+
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf(ERROR_MISSING_KEY)
+	}
+
+	salt = make([]byte, SALT_SIZE)
+	_, err = io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_ENCRYPT_FAILED, err)
+	}
+
+	key, err = scrypt.Key(passphrase, salt, SCRYPT_N, SCRYPT_R, SCRYPT_P,
+		KEY_SIZE,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_ENCRYPT_FAILED, err)
+	}
+	defer Zero(key)
+
+	aead, err = _newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_ENCRYPT_FAILED, err)
+	}
+
+	nonce = make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_ENCRYPT_FAILED, err)
+	}
+
+	headers = map[string]string{
+		HEADER_CIPHER: CIPHER_AES256GCM,
+		HEADER_KDF:    KDF_SCRYPT,
+		HEADER_N:      strconv.Itoa(SCRYPT_N),
+		HEADER_R:      strconv.Itoa(SCRYPT_R),
+		HEADER_P:      strconv.Itoa(SCRYPT_P),
+		HEADER_SALT:   hex.EncodeToString(salt),
+	}
+
+	out = pem.EncodeToMemory(&pem.Block{
+		Type:    ENCRYPTED_TYPE,
+		Headers: headers,
+		Bytes:   aead.Seal(nonce, nonce, plain, _additionalData(headers)),
+	})
+
+	return out, nil
+}
+
+// Decrypt opens the given encrypted data with the given passphrase.
+//
+// The given data must be generated by `Encrypt`. Otherwise, ERROR_NOT_ENCRYPTED
+// is returned. A wrong passphrase or a tampered data are reported as
+// ERROR_DECRYPT_FAILED since both are indistinguishable.
+func Decrypt(data []byte, passphrase []byte) (out []byte, err error) {
+	var block *pem.Block
+	var key, salt []byte
+	var aead cipher.AEAD
+	var n, r, p int
+
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf(ERROR_MISSING_KEY)
+	}
+
+	block, _ = pem.Decode(bytes.TrimLeft(data, " \t\r\n"))
+	if block == nil || block.Type != ENCRYPTED_TYPE {
+		return nil, fmt.Errorf(ERROR_NOT_ENCRYPTED)
+	}
+
+	if len(block.Headers) != len(_headerKeys()) ||
+		block.Headers[HEADER_CIPHER] != CIPHER_AES256GCM ||
+		block.Headers[HEADER_KDF] != KDF_SCRYPT {
+		return nil, fmt.Errorf("%s: unsupported %s with %s",
+			ERROR_BAD_HEADER,
+			block.Headers[HEADER_CIPHER],
+			block.Headers[HEADER_KDF],
+		)
+	}
+
+	n, r, p, salt, err = _parseKDF(block.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err = scrypt.Key(passphrase, salt, n, r, p, KEY_SIZE)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_BAD_HEADER, err)
+	}
+	defer Zero(key)
+
+	aead, err = _newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_DECRYPT_FAILED, err)
+	}
+
+	if len(block.Bytes) < aead.NonceSize() {
+		return nil, fmt.Errorf(ERROR_DECRYPT_FAILED)
+	}
+
+	out, err = aead.Open(nil,
+		block.Bytes[:aead.NonceSize()],
+		block.Bytes[aead.NonceSize():],
+		_additionalData(block.Headers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf(ERROR_DECRYPT_FAILED)
+	}
+
+	return out, nil
+}
+
+func _newAEAD(key []byte) (aead cipher.AEAD, err error) {
+	var c cipher.Block
+
+	c, err = aes.NewCipher(key)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return cipher.NewGCM(c) //nolint:wrapcheck
+}
+
+// _headerKeys lists the PEM headers of an encrypted secrets file in their
+// serialization order.
+func _headerKeys() []string {
+	return []string{
+		HEADER_CIPHER,
+		HEADER_KDF,
+		HEADER_N,
+		HEADER_R,
+		HEADER_P,
+		HEADER_SALT,
+	}
+}
+
+// _additionalData serializes the PEM type and headers for authentication.
+func _additionalData(headers map[string]string) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(ENCRYPTED_TYPE + "\n")
+	for _, k := range _headerKeys() {
+		b.WriteString(k + ": " + headers[k] + "\n")
+	}
+
+	return b.Bytes()
+}
+
+// _parseKDF is to parse the scrypt parameters from the PEM headers.
+//
+// The parameters are capped so a crafted file cannot demand excessive memory
+// or CPU time from scrypt.
+func _parseKDF(headers map[string]string) (n int, r int, p int,
+	salt []byte, err error) {
+	n, err = strconv.Atoi(headers[HEADER_N])
+	if err != nil || n <= 1 || n > SCRYPT_MAXN {
+		goto bad
+	}
+
+	r, err = strconv.Atoi(headers[HEADER_R])
+	if err != nil || r <= 0 || r > SCRYPT_MAXR {
+		goto bad
+	}
+
+	p, err = strconv.Atoi(headers[HEADER_P])
+	if err != nil || p <= 0 || p > SCRYPT_MAXP || r*p > SCRYPT_MAXRP {
+		goto bad
+	}
+
+	salt, err = hex.DecodeString(headers[HEADER_SALT])
+	if err != nil || len(salt) == 0 {
+		goto bad
+	}
+
+	return n, r, p, salt, nil
+bad:
+	return 0, 0, 0, nil, fmt.Errorf("%s: bad %s parameters",
+		ERROR_BAD_HEADER,
+		KDF_SCRYPT,
+	)
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"testing"
+)
+
+func TestDecrypt(t *testing.T) {
+	for i, s := range getTestScenarios() {
+		if s.TestType != testDecrypt {
+			continue
+		}
+
+		// prepare
+		th := s.prepareTHelper(t)
+		data := s.createData(t)
+
+		// test
+		out, err := Decrypt(data, s.createKey())
+
+		// assert
+		th.ExpectUIDCorrectness(i, s.UID, false)
+		s.assertDecrypt(th, data, out, err)
+		s.log(th, map[string]interface{}{
+			"data":  string(data),
+			"error": err,
+		})
+		th.Conclude()
+	}
+}
@@ -17,4 +17,9 @@ package secrets
 
 const (
 	ERROR_MISSING_DECODE_FX = "missing DecodeFx function"
+	ERROR_MISSING_KEY       = "missing encryption key"
+	ERROR_DECRYPT_FAILED    = "failed to decrypt (wrong key or corrupted)"
+	ERROR_ENCRYPT_FAILED    = "failed to encrypt"
+	ERROR_NOT_ENCRYPTED     = "data is not encrypted"
+	ERROR_BAD_HEADER        = "bad encryption header"
 )
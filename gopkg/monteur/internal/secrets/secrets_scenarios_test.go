@@ -0,0 +1,143 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should return the plain data when:
+1. the data is encrypted by Encrypt.
+2. the same passphrase is given.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should fail when:
+1. the data is encrypted by Encrypt.
+2. a different passphrase is given.
+`,
+			Switches: map[string]bool{
+				useWrongKey:         true,
+				expectDecryptFailed: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should fail when:
+1. no passphrase is given.
+`,
+			Switches: map[string]bool{
+				useMissingKey:        true,
+				expectMissingKey: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should fail when:
+1. the data is not an encrypted secrets document.
+`,
+			Switches: map[string]bool{
+				usePlainData:       true,
+				expectNotEncrypted: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should fail when:
+1. a PEM header of the encrypted data is altered.
+`,
+			Switches: map[string]bool{
+				useTamperedHeader:   true,
+				expectDecryptFailed: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should fail when:
+1. the sealed bytes of the encrypted data are altered.
+`,
+			Switches: map[string]bool{
+				useTamperedData:     true,
+				expectDecryptFailed: true,
+			},
+		}, {
+			UID:      7,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should fail when:
+1. the data was sealed without authenticating its PEM headers.
+`,
+			Switches: map[string]bool{
+				useUnauthenticated:  true,
+				expectDecryptFailed: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should reject the header when:
+1. an unknown PEM header is added to the encrypted data.
+`,
+			Switches: map[string]bool{
+				useExtraHeader:  true,
+				expectBadHeader: true,
+			},
+		}, {
+			UID:      9,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should reject the header without running scrypt when:
+1. the scrypt r parameter is above SCRYPT_MAXR.
+`,
+			Switches: map[string]bool{
+				useExcessiveR:   true,
+				expectBadHeader: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should reject the header without running scrypt when:
+1. the scrypt p parameter is above SCRYPT_MAXP.
+`,
+			Switches: map[string]bool{
+				useExcessiveP:   true,
+				expectBadHeader: true,
+			},
+		}, {
+			UID:      11,
+			TestType: testDecrypt,
+			Description: `
+Decrypt should reject the header without running scrypt when:
+1. r and p are each within their limits.
+2. r times p is above SCRYPT_MAXRP.
+`,
+			Switches: map[string]bool{
+				useExcessiveRP:  true,
+				expectBadHeader: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	testDecrypt = "testDecrypt"
+)
+
+const (
+	useWrongKey         = "useWrongKey"
+	useMissingKey       = "useMissingKey"
+	usePlainData        = "usePlainData"
+	useTamperedHeader   = "useTamperedHeader"
+	useTamperedData     = "useTamperedData"
+	useExtraHeader      = "useExtraHeader"
+	useUnauthenticated  = "useUnauthenticated"
+	useExcessiveR       = "useExcessiveR"
+	useExcessiveP       = "useExcessiveP"
+	useExcessiveRP      = "useExcessiveRP"
+	expectBadHeader     = "expectBadHeader"
+	expectDecryptFailed = "expectDecryptFailed"
+	expectNotEncrypted  = "expectNotEncrypted"
+	expectMissingKey    = "expectMissingKey"
+)
+
+const (
+	testPlain    = "[Token]\nValue = \"s3cr3t\"\n"
+	testKey      = "correct horse battery staple"
+	testWrongKey = "incorrect horse battery staple"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createData encrypts the test plain data and alters it according to the
+// scenario.
+func (s *testScenario) createData(t *testing.T) (data []byte) {
+	var block *pem.Block
+	var err error
+
+	if s.Switches[usePlainData] {
+		return []byte(testPlain)
+	}
+
+	if s.Switches[useUnauthenticated] {
+		return s.createUnauthenticated(t)
+	}
+
+	data, err = Encrypt([]byte(testPlain), []byte(testKey))
+	if err != nil {
+		t.Fatalf("failed to encrypt test data: %s", err)
+	}
+
+	block, _ = pem.Decode(data)
+
+	switch {
+	case s.Switches[useTamperedHeader]:
+		block.Headers[HEADER_N] = strconv.Itoa(SCRYPT_N / 2)
+	case s.Switches[useTamperedData]:
+		block.Bytes[len(block.Bytes)-1] ^= 0xFF
+	case s.Switches[useExtraHeader]:
+		block.Headers["Comment"] = "monteur"
+	case s.Switches[useExcessiveR]:
+		block.Headers[HEADER_R] = strconv.Itoa(SCRYPT_MAXR + 1)
+	case s.Switches[useExcessiveP]:
+		block.Headers[HEADER_P] = strconv.Itoa(SCRYPT_MAXP + 1)
+	case s.Switches[useExcessiveRP]:
+		block.Headers[HEADER_R] = strconv.Itoa(SCRYPT_MAXR)
+		block.Headers[HEADER_P] = strconv.Itoa(SCRYPT_MAXRP/SCRYPT_MAXR + 1)
+	}
+
+	return pem.EncodeToMemory(block)
+}
+
+// createUnauthenticated seals the test plain data without authenticating its
+// headers.
+func (s *testScenario) createUnauthenticated(t *testing.T) []byte {
+	salt := make([]byte, SALT_SIZE)
+
+	_, err := io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		t.Fatalf("failed to create salt: %s", err)
+	}
+
+	key, err := scrypt.Key([]byte(testKey), salt,
+		SCRYPT_N, SCRYPT_R, SCRYPT_P, KEY_SIZE,
+	)
+	if err != nil {
+		t.Fatalf("failed to derive key: %s", err)
+	}
+
+	aead, err := _newAEAD(key)
+	if err != nil {
+		t.Fatalf("failed to create cipher: %s", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type: ENCRYPTED_TYPE,
+		Headers: map[string]string{
+			HEADER_CIPHER: CIPHER_AES256GCM,
+			HEADER_KDF:    KDF_SCRYPT,
+			HEADER_N:      strconv.Itoa(SCRYPT_N),
+			HEADER_R:      strconv.Itoa(SCRYPT_R),
+			HEADER_P:      strconv.Itoa(SCRYPT_P),
+			HEADER_SALT:   hex.EncodeToString(salt),
+		},
+		Bytes: aead.Seal(nonce, nonce, []byte(testPlain), nil),
+	})
+}
+
+func (s *testScenario) createKey() []byte {
+	switch {
+	case s.Switches[useMissingKey]:
+		return nil
+	case s.Switches[useWrongKey]:
+		return []byte(testWrongKey)
+	default:
+		return []byte(testKey)
+	}
+}
+
+func (s *testScenario) assertDecrypt(th *thelper.THelper,
+	data []byte,
+	out []byte,
+	err error) {
+	var expect string
+
+	switch {
+	case s.Switches[expectBadHeader]:
+		expect = ERROR_BAD_HEADER
+	case s.Switches[expectDecryptFailed]:
+		expect = ERROR_DECRYPT_FAILED
+	case s.Switches[expectNotEncrypted]:
+		expect = ERROR_NOT_ENCRYPTED
+	case s.Switches[expectMissingKey]:
+		expect = ERROR_MISSING_KEY
+	}
+
+	if expect == "" {
+		th.ExpectError(err, false)
+		th.ExpectSameBool("encrypted", IsEncrypted(data),
+			"expected encrypted", true,
+		)
+		th.ExpectSameBool("armored", !bytes.Contains(data,
+			[]byte(testPlain)),
+			"expected armored", true,
+		)
+		th.ExpectSameStrings("plain", string(out),
+			"expected plain", testPlain,
+		)
+
+		return
+	}
+
+	th.ExpectError(err, true)
+	th.ExpectSameBool("error is "+expect,
+		err != nil && strings.HasPrefix(err.Error(), expect),
+		"expected error is "+expect, true,
+	)
+	th.ExpectSameBool("no output", out == nil,
+		"expected no output", true,
+	)
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+// Error messages are the package standardized messages
+const (
+	ERROR_HASH_UNKNOWN      = "unsupported signature hash algorithm"
+	ERROR_KEY_BAD           = "bad public key"
+	ERROR_KEY_MISSING       = "no trusted public key given"
+	ERROR_KEY_UNTRUSTED     = "signed by an untrusted key"
+	ERROR_MESSAGE_READ      = "failed to read signed message"
+	ERROR_NAMESPACE_BAD     = "signature namespace mismatched"
+	ERROR_SIGNATURE_BAD     = "bad signature format"
+	ERROR_SIGNATURE_INVALID = "signature verification failed"
+)
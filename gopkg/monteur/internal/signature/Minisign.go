@@ -0,0 +1,199 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	MINISIGN_ALGO_LEGACY    = "Ed" // signs the message directly
+	MINISIGN_ALGO_PREHASHED = "ED" // signs the BLAKE2b-512 of the message
+
+	minisignKeySize       = 2 + 8 + ed25519.PublicKeySize
+	minisignSignatureSize = 2 + 8 + ed25519.SignatureSize
+	minisignTrusted       = "trusted comment: "
+	minisignUntrusted     = "untrusted comment: "
+)
+
+type minisignKey struct {
+	id  []byte
+	key ed25519.PublicKey
+}
+
+// Minisign verifies the minisign detached signature of the message.
+//
+// keys are the trusted minisign public keys (e.g. the content of one or more
+// `minisign.pub` files) where the signature must be made by one of them. Both
+// the legacy and the pre-hashed signatures are supported. The trusted comment
+// is verified as well.
+func Minisign(keys []byte, sig []byte, message io.Reader) (err error) {
+	var list []*minisignKey
+	var trusted *minisignKey
+	var signature, global []byte
+	var comment string
+	var digest []byte
+
+	list, err = _parseMinisignKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	signature, comment, global, err = _parseMinisignSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range list {
+		if bytes.Equal(k.id, signature[2:10]) {
+			trusted = k
+			break
+		}
+	}
+
+	if trusted == nil {
+		return fmt.Errorf("%s: key ID %X",
+			ERROR_KEY_UNTRUSTED,
+			_reverse(signature[2:10]),
+		)
+	}
+
+	switch string(signature[:2]) {
+	case MINISIGN_ALGO_PREHASHED:
+		digest, err = _blake2b(message)
+	case MINISIGN_ALGO_LEGACY:
+		digest, err = io.ReadAll(message)
+	default:
+		return fmt.Errorf("%s: algorithm '%s'",
+			ERROR_SIGNATURE_BAD,
+			signature[:2],
+		)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %s", ERROR_MESSAGE_READ, err)
+	}
+
+	if !ed25519.Verify(trusted.key, digest, signature[10:]) {
+		return fmt.Errorf(ERROR_SIGNATURE_INVALID)
+	}
+
+	// the trusted comment is signed together with the signature
+	if !ed25519.Verify(trusted.key,
+		append(append([]byte{}, signature[10:]...), comment...),
+		global,
+	) {
+		return fmt.Errorf("%s: trusted comment", ERROR_SIGNATURE_INVALID)
+	}
+
+	return nil
+}
+
+func _parseMinisignKeys(data []byte) (list []*minisignKey, err error) {
+	var raw []byte
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, minisignUntrusted) {
+			continue
+		}
+
+		raw, err = base64.StdEncoding.DecodeString(line)
+		if err != nil || len(raw) != minisignKeySize ||
+			string(raw[:2]) != MINISIGN_ALGO_LEGACY {
+			return nil, fmt.Errorf("%s: '%s'", ERROR_KEY_BAD, line)
+		}
+
+		list = append(list, &minisignKey{
+			id:  raw[2:10],
+			key: ed25519.PublicKey(raw[10:]),
+		})
+	}
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf(ERROR_KEY_MISSING)
+	}
+
+	return list, nil
+}
+
+func _parseMinisignSignature(data []byte) (signature []byte,
+	comment string,
+	global []byte,
+	err error) {
+	lines := []string{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) != 4 ||
+		!strings.HasPrefix(lines[0], minisignUntrusted) ||
+		!strings.HasPrefix(lines[2], minisignTrusted) {
+		return nil, "", nil, fmt.Errorf("%s: not a minisign signature",
+			ERROR_SIGNATURE_BAD,
+		)
+	}
+
+	signature, err = base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(signature) != minisignSignatureSize {
+		return nil, "", nil, fmt.Errorf("%s: signature line",
+			ERROR_SIGNATURE_BAD,
+		)
+	}
+
+	global, err = base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return nil, "", nil, fmt.Errorf("%s: global signature line",
+			ERROR_SIGNATURE_BAD,
+		)
+	}
+
+	comment = strings.TrimPrefix(lines[2], minisignTrusted)
+
+	return signature, comment, global, nil
+}
+
+func _blake2b(message io.Reader) (digest []byte, err error) {
+	h, _ := blake2b.New512(nil)
+
+	_, err = io.Copy(h, message)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return h.Sum(nil), nil
+}
+
+// _reverse is to print the little-endian key ID the way minisign does.
+func _reverse(in []byte) (out []byte) {
+	out = make([]byte, len(in))
+	for i := range in {
+		out[len(in)-1-i] = in[i]
+	}
+
+	return out
+}
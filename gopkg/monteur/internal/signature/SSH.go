@@ -0,0 +1,232 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	SSH_NAMESPACE_DEFAULT = "file"
+
+	sshMagic   = "SSHSIG"
+	sshVersion = 1
+	sshBegin   = "-----BEGIN SSH SIGNATURE-----"
+	sshEnd     = "-----END SSH SIGNATURE-----"
+)
+
+// sshSignature is the SSHSIG blob generated by `ssh-keygen -Y sign`.
+type sshSignature struct {
+	Version   uint32
+	PublicKey []byte
+	Namespace string
+	Reserved  string
+	Hash      string
+	Signature []byte
+}
+
+// sshSignedData is the data actually signed for the SSHSIG blob.
+type sshSignedData struct {
+	Namespace string
+	Reserved  string
+	Hash      string
+	Digest    []byte
+}
+
+// SSH verifies the SSH detached signature (`ssh-keygen -Y sign`) of the
+// message.
+//
+// keys are the trusted SSH public keys in either the `authorized_keys` or the
+// `allowed_signers` format where the signature must be made by one of them.
+// namespace must match the one used for signing (e.g. `file`).
+func SSH(keys []byte,
+	namespace string,
+	sig []byte,
+	message io.Reader) (err error) {
+	var list []ssh.PublicKey
+	var key ssh.PublicKey
+	var blob *sshSignature
+	var digest []byte
+	var signature ssh.Signature
+
+	list, err = _parseSSHKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	blob, err = _parseSSHSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	if blob.Namespace != namespace {
+		return fmt.Errorf("%s: '%s' instead of '%s'",
+			ERROR_NAMESPACE_BAD,
+			blob.Namespace,
+			namespace,
+		)
+	}
+
+	key, err = ssh.ParsePublicKey(blob.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%s: %s", ERROR_SIGNATURE_BAD, err)
+	}
+
+	if !_isTrustedSSHKey(list, key) {
+		return fmt.Errorf("%s: %s",
+			ERROR_KEY_UNTRUSTED,
+			ssh.FingerprintSHA256(key),
+		)
+	}
+
+	digest, err = _sshDigest(blob.Hash, message)
+	if err != nil {
+		return err
+	}
+
+	err = ssh.Unmarshal(blob.Signature, &signature)
+	if err != nil {
+		return fmt.Errorf("%s: %s", ERROR_SIGNATURE_BAD, err)
+	}
+
+	data := append([]byte(sshMagic), ssh.Marshal(&sshSignedData{
+		Namespace: blob.Namespace,
+		Reserved:  blob.Reserved,
+		Hash:      blob.Hash,
+		Digest:    digest,
+	})...)
+
+	err = key.Verify(data, &signature)
+	if err != nil {
+		return fmt.Errorf("%s: %s", ERROR_SIGNATURE_INVALID, err)
+	}
+
+	return nil
+}
+
+func _parseSSHKeys(data []byte) (list []ssh.PublicKey, err error) {
+	var key ssh.PublicKey
+	var raw []byte
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// the key type and data may be preceded by the principals and
+		// options in the allowed_signers format
+		key = nil
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields) && key == nil; i++ {
+			raw, err = base64.StdEncoding.DecodeString(fields[i+1])
+			if err != nil {
+				continue
+			}
+
+			key, err = ssh.ParsePublicKey(raw)
+			if err != nil || key.Type() != fields[i] {
+				key = nil
+			}
+		}
+
+		if key == nil {
+			return nil, fmt.Errorf("%s: '%s'", ERROR_KEY_BAD, line)
+		}
+
+		list = append(list, key)
+	}
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf(ERROR_KEY_MISSING)
+	}
+
+	return list, nil
+}
+
+func _parseSSHSignature(data []byte) (out *sshSignature, err error) {
+	var raw []byte
+
+	text := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(text, sshBegin) || !strings.HasSuffix(text, sshEnd) {
+		return nil, fmt.Errorf("%s: not an SSH signature",
+			ERROR_SIGNATURE_BAD,
+		)
+	}
+
+	text = strings.TrimSuffix(strings.TrimPrefix(text, sshBegin), sshEnd)
+	text = strings.Join(strings.Fields(text), "")
+
+	raw, err = base64.StdEncoding.DecodeString(text)
+	if err != nil || !bytes.HasPrefix(raw, []byte(sshMagic)) {
+		return nil, fmt.Errorf("%s: bad armor", ERROR_SIGNATURE_BAD)
+	}
+
+	out = &sshSignature{}
+
+	err = ssh.Unmarshal(raw[len(sshMagic):], out)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_SIGNATURE_BAD, err)
+	}
+
+	if out.Version != sshVersion {
+		return nil, fmt.Errorf("%s: version %d",
+			ERROR_SIGNATURE_BAD,
+			out.Version,
+		)
+	}
+
+	return out, nil
+}
+
+func _isTrustedSSHKey(list []ssh.PublicKey, key ssh.PublicKey) bool {
+	for _, k := range list {
+		if bytes.Equal(k.Marshal(), key.Marshal()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func _sshDigest(algo string, message io.Reader) (digest []byte, err error) {
+	var h hash.Hash
+
+	switch algo {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("%s: '%s'", ERROR_HASH_UNKNOWN, algo)
+	}
+
+	_, err = io.Copy(h, message)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ERROR_MESSAGE_READ, err)
+	}
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testMinisign,
+			Description: `
+Minisign should verify the signature when:
+1. the signature is pre-hashed and made by the trusted key.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      2,
+			TestType: testMinisign,
+			Description: `
+Minisign should verify the signature when:
+1. the signature is legacy and made by the trusted key.
+`,
+			Switches: map[string]bool{
+				useLegacy: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testMinisign,
+			Description: `
+Minisign should report error when:
+1. the signature is made by an untrusted key.
+`,
+			Switches: map[string]bool{
+				useUntrustedKey: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      4,
+			TestType: testMinisign,
+			Description: `
+Minisign should report error when:
+1. the message is tampered.
+`,
+			Switches: map[string]bool{
+				useTamperedData: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      5,
+			TestType: testMinisign,
+			Description: `
+Minisign should report error when:
+1. the trusted comment is tampered.
+`,
+			Switches: map[string]bool{
+				useTamperedNote: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      6,
+			TestType: testMinisign,
+			Description: `
+Minisign should report error when:
+1. the signature is malformed.
+`,
+			Switches: map[string]bool{
+				useBadSignature: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      7,
+			TestType: testMinisign,
+			Description: `
+Minisign should report error when:
+1. no trusted key is given.
+`,
+			Switches: map[string]bool{
+				useNoKey:    true,
+				expectError: true,
+			},
+		}, {
+			UID:      8,
+			TestType: testSSH,
+			Description: `
+SSH should verify the signature when:
+1. the signature uses sha512 and is made by the trusted key.
+`,
+			Switches: map[string]bool{},
+		}, {
+			UID:      9,
+			TestType: testSSH,
+			Description: `
+SSH should verify the signature when:
+1. the signature uses sha256 and is made by the trusted key.
+2. the trusted key is in the allowed_signers format.
+`,
+			Switches: map[string]bool{
+				useSHA256:         true,
+				useAllowedSigners: true,
+			},
+		}, {
+			UID:      10,
+			TestType: testSSH,
+			Description: `
+SSH should report error when:
+1. the signature is made by an untrusted key.
+`,
+			Switches: map[string]bool{
+				useUntrustedKey: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      11,
+			TestType: testSSH,
+			Description: `
+SSH should report error when:
+1. the message is tampered.
+`,
+			Switches: map[string]bool{
+				useTamperedData: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      12,
+			TestType: testSSH,
+			Description: `
+SSH should report error when:
+1. the signature is made for another namespace.
+`,
+			Switches: map[string]bool{
+				useOtherNamespace: true,
+				expectError:       true,
+			},
+		}, {
+			UID:      13,
+			TestType: testSSH,
+			Description: `
+SSH should report error when:
+1. the signature is malformed.
+`,
+			Switches: map[string]bool{
+				useBadSignature: true,
+				expectError:     true,
+			},
+		}, {
+			UID:      14,
+			TestType: testSSH,
+			Description: `
+SSH should report error when:
+1. no trusted key is given.
+`,
+			Switches: map[string]bool{
+				useNoKey:    true,
+				expectError: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	testMinisign = "testMinisign"
+	testSSH      = "testSSH"
+)
+
+const (
+	useLegacy         = "useLegacy"
+	useUntrustedKey   = "useUntrustedKey"
+	useTamperedData   = "useTamperedData"
+	useTamperedNote   = "useTamperedNote"
+	useOtherNamespace = "useOtherNamespace"
+	useSHA256         = "useSHA256"
+	useAllowedSigners = "useAllowedSigners"
+	useBadSignature   = "useBadSignature"
+	useNoKey          = "useNoKey"
+	expectError       = "expectError"
+)
+
+const (
+	testMessage = "monteur signed artifact"
+	testComment = "timestamp:1650000000\tfile:tool.tar.gz"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+func (s *testScenario) createKeys(t *testing.T) (signer ed25519.PrivateKey,
+	trusted ed25519.PublicKey) {
+	var err error
+
+	trusted, signer, err = ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %s", err)
+	}
+
+	if s.Switches[useUntrustedKey] {
+		trusted, _, _ = ed25519.GenerateKey(rand.Reader)
+	}
+
+	return signer, trusted
+}
+
+func (s *testScenario) createMessage() *bytes.Reader {
+	if s.Switches[useTamperedData] {
+		return bytes.NewReader([]byte(testMessage + "!"))
+	}
+
+	return bytes.NewReader([]byte(testMessage))
+}
+
+func (s *testScenario) createMinisignKey(trusted ed25519.PublicKey) []byte {
+	if s.Switches[useNoKey] {
+		return []byte("untrusted comment: nothing here\n")
+	}
+
+	id := []byte("monteur!")
+	if s.Switches[useUntrustedKey] {
+		id = []byte("unknown!")
+	}
+
+	raw := append(append([]byte(MINISIGN_ALGO_LEGACY), id...), trusted...)
+
+	return []byte(minisignUntrusted + "minisign public key\n" +
+		base64.StdEncoding.EncodeToString(raw) + "\n")
+}
+
+func (s *testScenario) createMinisignSignature(t *testing.T,
+	signer ed25519.PrivateKey) []byte {
+	algo := MINISIGN_ALGO_PREHASHED
+	digest, _ := _blake2b(bytes.NewReader([]byte(testMessage)))
+
+	if s.Switches[useLegacy] {
+		algo = MINISIGN_ALGO_LEGACY
+		digest = []byte(testMessage)
+	}
+
+	signature := ed25519.Sign(signer, digest)
+	global := ed25519.Sign(signer, append(append([]byte{}, signature...),
+		testComment...,
+	))
+
+	comment := testComment
+	if s.Switches[useTamperedNote] {
+		comment += " (edited)"
+	}
+
+	raw := append(append([]byte(algo), []byte("monteur!")...), signature...)
+	if s.Switches[useBadSignature] {
+		raw = raw[:len(raw)-1]
+	}
+
+	return []byte(minisignUntrusted + "signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(raw) + "\n" +
+		minisignTrusted + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n")
+}
+
+func (s *testScenario) createSSHKey(t *testing.T,
+	trusted ed25519.PublicKey) []byte {
+	if s.Switches[useNoKey] {
+		return []byte("# nothing here\n")
+	}
+
+	key, err := ssh.NewPublicKey(trusted)
+	if err != nil {
+		t.Fatalf("failed to create test SSH key: %s", err)
+	}
+
+	line := ssh.MarshalAuthorizedKey(key)
+	if s.Switches[useAllowedSigners] {
+		line = append([]byte(`monteur@example.com namespaces="file" `),
+			line...,
+		)
+	}
+
+	return append([]byte("# trusted signers\n"), line...)
+}
+
+func (s *testScenario) createSSHSignature(t *testing.T,
+	signer ed25519.PrivateKey) []byte {
+	algo := "sha512"
+	if s.Switches[useSHA256] {
+		algo = "sha256"
+	}
+
+	digest, _ := _sshDigest(algo, bytes.NewReader([]byte(testMessage)))
+
+	namespace := SSH_NAMESPACE_DEFAULT
+	if s.Switches[useOtherNamespace] {
+		namespace = "git"
+	}
+
+	sshSigner, err := ssh.NewSignerFromKey(signer)
+	if err != nil {
+		t.Fatalf("failed to create test SSH signer: %s", err)
+	}
+
+	data := append([]byte(sshMagic), ssh.Marshal(&sshSignedData{
+		Namespace: namespace,
+		Hash:      algo,
+		Digest:    digest,
+	})...)
+
+	signature, err := sshSigner.Sign(rand.Reader, data)
+	if err != nil {
+		t.Fatalf("failed to sign test data: %s", err)
+	}
+
+	blob := append([]byte(sshMagic), ssh.Marshal(&sshSignature{
+		Version:   sshVersion,
+		PublicKey: sshSigner.PublicKey().Marshal(),
+		Namespace: namespace,
+		Hash:      algo,
+		Signature: ssh.Marshal(signature),
+	})...)
+
+	if s.Switches[useBadSignature] {
+		blob = blob[:len(blob)/2]
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "SSH SIGNATURE",
+		Bytes: blob,
+	})
+}
+
+func (s *testScenario) assertVerify(th *thelper.THelper, err error) {
+	th.ExpectError(err, s.Switches[expectError])
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package styler
+
+import (
+	"fmt"
+)
+
+// Terminal control sequences for interactive (TTY) rendering
+const (
+	TERM_CLEAR_DOWN = "\x1b[J"
+)
+
+// CursorUp is to move the cursor to the beginning of the `n` lines above.
+//
+// If `n` is `0`, the cursor is only moved to the beginning of the current line.
+func CursorUp(n int) string {
+	if n <= 0 {
+		return "\r"
+	}
+
+	return fmt.Sprintf("\x1b[%dF", n)
+}
+
+// Spinner is to get the spinner character of the given animation frame.
+func Spinner(frame int) string {
+	frames := []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+	if frame < 0 {
+		frame = -frame
+	}
+
+	return string(frames[frame%len(frames)])
+}
+
+// Truncate is to shorten a string into the given columns width.
+//
+// The string is cut in characters (not bytes) and ends with `…` when it is
+// shortened. If the `width` is `0`, the string is returned as it is.
+func Truncate(s string, width uint) string {
+	runes := []rune(s)
+
+	if width == 0 || uint(len(runes)) <= width {
+		return s
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
+// Bytes is to format the given size in bytes with binary units (e.g. `1.5MB`).
+func Bytes(size int64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	value := float64(size)
+	i := 0
+
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+
+	if i == 0 {
+		return fmt.Sprintf("%d%s", size, units[i])
+	}
+
+	return fmt.Sprintf("%.1f%s", value, units[i])
+}
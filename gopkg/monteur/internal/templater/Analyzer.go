@@ -0,0 +1,121 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templater
+
+import (
+	"text/template/parse"
+)
+
+// Analysis is the static analysis result of a template text.
+type Analysis struct {
+	// Variables are the top-level variable keys referenced by the text
+	// (e.g. `App` for `{{- .App.Name -}}`).
+	Variables []string
+
+	// Calls are the string literal arguments of each called function
+	// keyed by the function name (e.g. `GetSecret "key"`).
+	Calls map[string][][]string
+}
+
+// Analyze parses a given text without executing it.
+//
+// It returns the variables and function calls used by the text so that any
+// unresolved reference can be detected before the actual run. Variables
+// referenced inside a `range` or `with` body are not reported since their dot
+// is no longer the variables list.
+func Analyze(text string,
+	funcMap map[string]interface{}) (out *Analysis, err error) {
+	t := textTemplate("Name", funcMap)
+
+	t, err = t.Parse(text)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	out = &Analysis{
+		Variables: []string{},
+		Calls:     map[string][][]string{},
+	}
+
+	if t.Tree != nil && t.Tree.Root != nil {
+		out.walk(t.Tree.Root)
+	}
+
+	return out, nil
+}
+
+func (me *Analysis) walk(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, v := range n.Nodes {
+			me.walk(v)
+		}
+	case *parse.ActionNode:
+		me.walk(n.Pipe)
+	case *parse.IfNode:
+		me.walk(n.Pipe)
+		me.walk(n.List)
+		me.walk(n.ElseList)
+	case *parse.RangeNode:
+		me.walk(n.Pipe)
+		me.walk(n.ElseList)
+	case *parse.WithNode:
+		me.walk(n.Pipe)
+		me.walk(n.ElseList)
+	case *parse.TemplateNode:
+		me.walk(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		for _, v := range n.Cmds {
+			me.walk(v)
+		}
+	case *parse.CommandNode:
+		me._walkCommand(n)
+	case *parse.FieldNode:
+		me.Variables = append(me.Variables, n.Ident[0])
+	case *parse.ChainNode:
+		me.walk(n.Node)
+	}
+}
+
+func (me *Analysis) _walkCommand(node *parse.CommandNode) {
+	var args []string
+
+	for _, v := range node.Args {
+		me.walk(v)
+	}
+
+	fx, ok := node.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return
+	}
+
+	args = []string{}
+	for _, v := range node.Args[1:] {
+		if s, ok := v.(*parse.StringNode); ok {
+			args = append(args, s.Text)
+		}
+	}
+
+	me.Calls[fx.Ident] = append(me.Calls[fx.Ident], args)
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+func getTestScenarios() []testScenario {
+	return []testScenario{
+		{
+			UID:      1,
+			TestType: testInspectValue,
+			Description: `
+apiInspect._value should redact the whole value when:
+1. the value is the secrets store.
+`,
+			Switches: map[string]bool{
+				useSecrets: true,
+			},
+		}, {
+			UID:      2,
+			TestType: testInspectValue,
+			Description: `
+apiInspect._value should redact the secret inside the value when:
+1. the value is a string holding a secret.
+`,
+			Switches: map[string]bool{
+				useSecretString: true,
+			},
+		}, {
+			UID:      3,
+			TestType: testInspectValue,
+			Description: `
+apiInspect._value should redact the secret inside the value when:
+1. the value is a list holding a secret.
+`,
+			Switches: map[string]bool{
+				useSecretList: true,
+			},
+		}, {
+			UID:      4,
+			TestType: testInspectValue,
+			Description: `
+apiInspect._value should print the value as it is when:
+1. the value is a string without secrets.
+`,
+			Switches: map[string]bool{
+				usePlainString: true,
+			},
+		}, {
+			UID:      5,
+			TestType: testInspectValue,
+			Description: `
+apiInspect._value should print the software's name and version when:
+1. the value is a software.
+`,
+			Switches: map[string]bool{
+				useSoftware: true,
+			},
+		}, {
+			UID:      6,
+			TestType: testInspectValue,
+			Description: `
+apiInspect._value should print the timestamp when:
+1. the value is a time.
+`,
+			Switches: map[string]bool{
+				useTime: true,
+			},
+		},
+	}
+}
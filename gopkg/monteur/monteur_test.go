@@ -0,0 +1,115 @@
+// Copyright 2022 ZORALab Enterprise (hello@zoralab.com)
+// Copyright 2022 "Holloway" Chew, Kean Ho (hollowaykeanho@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monteur
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.com/zoralab/cerigo/testing/thelper"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libmonteur"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libsecrets"
+	"gitlab.com/zoralab/monteur/gopkg/monteur/internal/libworkspace"
+)
+
+const (
+	testInspectValue = "testInspectValue"
+)
+
+const (
+	useSecrets      = "useSecrets"
+	useSecretString = "useSecretString"
+	useSecretList   = "useSecretList"
+	usePlainString  = "usePlainString"
+	useSoftware     = "useSoftware"
+	useTime         = "useTime"
+)
+
+const (
+	testSecretKey   = "Token"
+	testSecretValue = "s3cr3t-t0k3n-value"
+	testPlain       = "monteur"
+)
+
+type testScenario thelper.Scenario
+
+func (s *testScenario) prepareTHelper(t *testing.T) *thelper.THelper {
+	return thelper.NewTHelper(t)
+}
+
+func (s *testScenario) log(th *thelper.THelper,
+	data map[string]interface{}) {
+	th.LogScenario(thelper.Scenario(*s), data)
+}
+
+// createInspect is to get the apiInspect with a secrets file holding
+// testSecretValue.
+func (s *testScenario) createInspect(t *testing.T) *apiInspect {
+	path := filepath.Join(t.TempDir(), "secrets.toml")
+
+	err := os.WriteFile(path,
+		[]byte(testSecretKey+" = \""+testSecretValue+"\"\n"),
+		0600,
+	)
+	if err != nil {
+		t.Fatalf("failed to create test secrets file: %s", err)
+	}
+
+	secrets := &libsecrets.Secrets{}
+
+	err = secrets.Parse([]string{path})
+	if err != nil {
+		t.Fatalf("failed to create test secrets: %s", err)
+	}
+
+	return &apiInspect{
+		workspace: &libworkspace.Workspace{Secrets: secrets},
+	}
+}
+
+// createValue is to get the inspected value alongside its expected output.
+func (s *testScenario) createValue(api *apiInspect) (value interface{},
+	expect string) {
+	switch {
+	case s.Switches[useSecrets]:
+		return api.workspace.Secrets, libmonteur.SECRET_REDACTED
+	case s.Switches[useSecretString]:
+		return "token=" + testSecretValue,
+			"\"token=" + libmonteur.SECRET_REDACTED + "\""
+	case s.Switches[useSecretList]:
+		return []string{testPlain, testSecretValue},
+			"[]string{\"" + testPlain + "\", \"" +
+				libmonteur.SECRET_REDACTED + "\"}"
+	case s.Switches[useSoftware]:
+		return &libmonteur.Software{Name: "Monteur", Version: "1.2.3"},
+			"*libmonteur.Software{Name: \"Monteur\", " +
+				"Version: \"1.2.3\"}"
+	case s.Switches[useTime]:
+		timestamp := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		return &timestamp, timestamp.String()
+	}
+
+	return testPlain, fmt.Sprintf("%#v", testPlain)
+}
+
+func (s *testScenario) assertValue(th *thelper.THelper,
+	out string,
+	expect string) {
+	th.ExpectSameStrings("value", out, "expected value", expect)
+}
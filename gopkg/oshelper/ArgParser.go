@@ -173,12 +173,19 @@ func (me *ArgParser) parseArgs() {
 	}
 
 	var oldLabel string
+	var trailing *Argument
 
 	for i, arg := range me.args {
 		if i == 0 {
 			continue
 		}
 
+		if trailing != nil && oldLabel == "" &&
+			arg != "" && arg[:1] != "-" {
+			*trailing.Trailing = append(*trailing.Trailing, arg)
+			continue
+		}
+
 		label, value, hasTail := me.analyzeArg(arg, oldLabel)
 		f := me.flags[label]
 
@@ -186,8 +193,17 @@ func (me *ArgParser) parseArgs() {
 			return
 		}
 
+		// type: boolean switch (-v, --verbose) never takes a tail value
+		if _, ok := f.Value.(*bool); ok && hasTail {
+			value, hasTail = "true", false
+		}
+
 		f.setValue(value)
 
+		if f.Trailing != nil && !hasTail {
+			trailing = f
+		}
+
 		oldLabel = ""
 		if hasTail {
 			oldLabel = label
@@ -126,6 +126,16 @@ type Argument struct {
 	// This field is optional.
 	HelpExamples []string
 
+	// Trailing is the variable pointer for collecting positional values.
+	//
+	// When set, all the values after this Argument that are not started
+	// with a dash (`-`) are appended into it instead of being parsed as
+	// other Arguments. Example: `$ ./program inspect build go` collects
+	// `build` and `go` into Trailing for the `inspect` Argument.
+	//
+	// This field is optional.
+	Trailing *[]string
+
 	// DisableHelp is to instruct ArgumentParser to discard help printout.
 	//
 	// This is useful for controlling printout of this Argument. The default